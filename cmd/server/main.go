@@ -7,16 +7,32 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	// Import modul auth yang baru
+	"starterpack-golang-cleanarch/internal/app/apikey"
 	"starterpack-golang-cleanarch/internal/app/auth"
+	"starterpack-golang-cleanarch/internal/app/department"
+	"starterpack-golang-cleanarch/internal/app/employee"
+	"starterpack-golang-cleanarch/internal/app/invitation"
+	"starterpack-golang-cleanarch/internal/app/passwordpolicy"
+	"starterpack-golang-cleanarch/internal/app/privacy"
+	"starterpack-golang-cleanarch/internal/app/profile"
+	"starterpack-golang-cleanarch/internal/app/rbac"
+	"starterpack-golang-cleanarch/internal/app/sso"
+	"starterpack-golang-cleanarch/internal/app/tenant"
+	"starterpack-golang-cleanarch/internal/app/users"
 	"starterpack-golang-cleanarch/internal/repository"
 
+	"starterpack-golang-cleanarch/internal/platform/breached"
 	"starterpack-golang-cleanarch/internal/platform/http/middleware"
+	"starterpack-golang-cleanarch/internal/platform/mailer"
 	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/jwtkeys"
 	"starterpack-golang-cleanarch/internal/utils/log"
+	"starterpack-golang-cleanarch/internal/utils/secretbox"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
@@ -68,6 +84,9 @@ func main() {
 		log.Fatalf(context.Background(), "Failed to ping database: %v", err)
 	}
 	log.Info(context.Background(), "Successfully connected to database.")
+	if err := repository.CheckTenantScopedRole(context.Background(), db); err != nil {
+		log.Fatalf(context.Background(), "Database is not set up for row-level security: %v", err)
+	}
 
 	defer func() {
 		log.Info(context.Background(), "Closing database connection...")
@@ -76,6 +95,28 @@ func main() {
 		}
 	}()
 
+	// Background jobs (e.g. key rotation) run until this context is cancelled on shutdown.
+	appCtx, stopBackgroundJobs := context.WithCancel(context.Background())
+	defer stopBackgroundJobs()
+
+	// JWT signing keys. RS256/EdDSA keys are persisted in JWT_KEYS_DIR and rotated every
+	// JWT_KEY_ROTATION_HOURS; retired keys keep verifying tokens for the refresh-token lifetime.
+	refreshHours, _ := strconv.Atoi(os.Getenv("REFRESH_TOKEN_EXPIRES_IN_HOURS"))
+	keyManager, err := jwtkeys.NewManager(jwtkeys.Config{
+		Algorithm:  os.Getenv("JWT_SIGNING_ALG"),
+		KeysDir:    os.Getenv("JWT_KEYS_DIR"),
+		Retention:  time.Duration(refreshHours) * time.Hour,
+		HMACSecret: os.Getenv("JWT_SECRET"),
+	})
+	if err != nil {
+		log.Fatalf(context.Background(), "Failed to initialize JWT key manager: %v", err)
+	}
+	utils.SetKeyManager(keyManager)
+	if rotationHours, _ := strconv.Atoi(os.Getenv("JWT_KEY_ROTATION_HOURS")); rotationHours > 0 {
+		keyManager.StartRotation(appCtx, time.Duration(rotationHours)*time.Hour)
+	}
+	log.Infof(context.Background(), "JWT signing algorithm: %s (active kid: %s)", keyManager.Algorithm(), keyManager.SigningKey().ID)
+
 	appValidator := validator.New()
 
 	r := mux.NewRouter()
@@ -108,37 +149,278 @@ func main() {
 		utils.RespondJSON(w, http.StatusOK, info)
 	}).Methods("GET")
 
+	// Public keys for verifying access tokens, for use by other services.
+	r.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		utils.RespondJSON(w, http.StatusOK, keyManager.JWKS())
+	}).Methods("GET")
+
 	// --- Dependency Injection (DI) & Feature Module Registration ---
 
+	// Mailer used by the auth flows. "file" stores messages as .eml files in MAILER_FILE_DIR,
+	// anything else logs them.
+	var appMailer mailer.Mailer = mailer.NewLogMailer()
+	if os.Getenv("MAILER_DRIVER") == "file" {
+		mailDir := os.Getenv("MAILER_FILE_DIR")
+		if mailDir == "" {
+			mailDir = "./tmp/mail"
+		}
+		appMailer, err = mailer.NewFileMailer(mailDir)
+		if err != nil {
+			log.Fatalf(context.Background(), "Failed to initialize file mailer: %v", err)
+		}
+	}
+	frontendURL := os.Getenv("APP_FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:3000"
+	}
+	passwordResetTTL := 30 * time.Minute
+	if minutes, _ := strconv.Atoi(os.Getenv("PASSWORD_RESET_TTL_MINUTES")); minutes > 0 {
+		passwordResetTTL = time.Duration(minutes) * time.Minute
+	}
+	emailVerificationTTL := 48 * time.Hour
+	if hours, _ := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_TTL_HOURS")); hours > 0 {
+		emailVerificationTTL = time.Duration(hours) * time.Hour
+	}
+	invitationTTL := 7 * 24 * time.Hour
+	if hours, _ := strconv.Atoi(os.Getenv("INVITATION_TTL_HOURS")); hours > 0 {
+		invitationTTL = time.Duration(hours) * time.Hour
+	}
+	requireEmailVerification, _ := strconv.ParseBool(os.Getenv("AUTH_REQUIRE_EMAIL_VERIFICATION"))
+	mfaIssuer := os.Getenv("MFA_ISSUER")
+	if mfaIssuer == "" {
+		mfaIssuer = os.Getenv("APP_NAME")
+	}
+	maxFailedLogins := 10
+	if n, err := strconv.Atoi(os.Getenv("LOGIN_MAX_FAILED_ATTEMPTS")); err == nil {
+		maxFailedLogins = n
+	}
+	maxFailedLoginsPerIP := 100
+	if n, err := strconv.Atoi(os.Getenv("LOGIN_MAX_FAILED_ATTEMPTS_PER_IP")); err == nil {
+		maxFailedLoginsPerIP = n
+	}
+	loginLockoutDuration := 15 * time.Minute
+	if minutes, _ := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_MINUTES")); minutes > 0 {
+		loginLockoutDuration = time.Duration(minutes) * time.Minute
+	}
+	trustProxyHeaders, _ := strconv.ParseBool(os.Getenv("TRUST_PROXY_HEADERS"))
+	utils.SetTrustProxyHeaders(trustProxyHeaders)
+	mfaChallengeTTL := 5 * time.Minute
+	if minutes, _ := strconv.Atoi(os.Getenv("MFA_CHALLENGE_TTL_MINUTES")); minutes > 0 {
+		mfaChallengeTTL = time.Duration(minutes) * time.Minute
+	}
+	// TOTP secrets are stored encrypted; secrets from before encryption are encrypted on start.
+	mfaSecretBox, err := secretbox.NewFromBase64(os.Getenv("MFA_SECRET_KEY"))
+	if err != nil {
+		log.Fatalf(context.Background(), "MFA_SECRET_KEY must be a base64-encoded 32-byte key: %v", err)
+	}
+	if n, err := repository.EncryptMFASecrets(context.Background(), db, mfaSecretBox); err != nil {
+		log.Fatalf(context.Background(), "Failed to encrypt stored MFA secrets: %v", err)
+	} else if n > 0 {
+		log.Infof(context.Background(), "Encrypted %d stored MFA secret(s)", n)
+	}
+
+	// Services run writes that must succeed or fail together in one unit of work.
+	transactor := repository.NewTransactor(db)
+
 	// Auth Module Wiring
 	userRepo := repository.NewPostgreSQLUserRepository(db)
-	authService := auth.NewAuthService(userRepo)
+	refreshTokenRepo := repository.NewPostgreSQLRefreshTokenRepository(db)
+	// Revoked access tokens are kept in memory; swap in a shared implementation when running
+	// more than one instance.
+	tokenDenylist := repository.NewInMemoryTokenDenylist()
+
+	membershipRepo := repository.NewPostgreSQLTenantMembershipRepository(db)
+
+	// RBAC Module Wiring. The RBAC service also resolves permissions for RequirePermission.
+	roleRepo := repository.NewPostgreSQLRoleRepository(db)
+	rbacService := rbac.NewRBACService(roleRepo, membershipRepo)
+	rbacHandler := rbac.NewRBACHandler(rbacService, appValidator)
+
+	// Tenant Module Wiring. The tenant service also lets AuthMiddleware reject suspended tenants.
+	tenantRepo := repository.NewPostgreSQLTenantRepository(db)
+	tenantService := tenant.NewTenantService(tenantRepo)
+	tenantHandler := tenant.NewTenantHandler(tenantService, appValidator)
+
+	// API Key Module Wiring. Keys authenticate machine clients in AuthMiddleware.
+	apiKeyRepo := repository.NewPostgreSQLAPIKeyRepository(db)
+	apiKeyService := apikey.NewAPIKeyService(apiKeyRepo, rbacService)
+	apiKeyHandler := apikey.NewAPIKeyHandler(apiKeyService, appValidator)
+
+	authMiddleware := middleware.AuthMiddleware(middleware.AuthConfig{
+		Denylist:    tokenDenylist,
+		Permissions: rbacService,
+		APIKeys:     apiKeyService,
+		Tenants:     tenantService,
+	})
+
+	// Password Policy Module Wiring. Tenants set their own rules; passwords are checked against the
+	// breached-password list in BREACHED_PASSWORDS_DIR when one is configured.
+	var breachedList passwordpolicy.BreachedList
+	if breachedDir := os.Getenv("BREACHED_PASSWORDS_DIR"); breachedDir != "" {
+		rangeList, err := breached.OpenRangeList(breachedDir)
+		if err != nil {
+			log.Fatalf(context.Background(), "Failed to open breached-password list: %v", err)
+		}
+		breachedList = rangeList
+	}
+	passwordPolicyService := passwordpolicy.NewPasswordPolicyService(
+		repository.NewPostgreSQLPasswordPolicyRepository(db),
+		repository.NewPostgreSQLPasswordHistoryRepository(db),
+		breachedList,
+	)
+	passwordPolicyHandler := passwordpolicy.NewPasswordPolicyHandler(passwordPolicyService, appValidator)
+
+	actionTokenRepo := repository.NewPostgreSQLActionTokenRepository(db)
+	mfaRepo := repository.NewPostgreSQLMFARepository(db, mfaSecretBox)
+	loginThrottleRepo := repository.NewPostgreSQLLoginThrottleRepository(db)
+	authService := auth.NewAuthService(auth.Dependencies{
+		UserRepo:          userRepo,
+		RefreshTokenRepo:  refreshTokenRepo,
+		RoleRepo:          roleRepo,
+		ActionTokenRepo:   actionTokenRepo,
+		MFARepo:           mfaRepo,
+		LoginThrottleRepo: loginThrottleRepo,
+		TenantRepo:        tenantRepo,
+		MembershipRepo:    membershipRepo,
+		Denylist:          tokenDenylist,
+		Mailer:            appMailer,
+		PasswordPolicy:    passwordPolicyService,
+		Transactor:        transactor,
+	}, auth.Config{
+		FrontendURL:              frontendURL,
+		PasswordResetTTL:         passwordResetTTL,
+		EmailVerificationTTL:     emailVerificationTTL,
+		RequireEmailVerification: requireEmailVerification,
+		MFAIssuer:                mfaIssuer,
+		MFAChallengeTTL:          mfaChallengeTTL,
+		MaxFailedLogins:          maxFailedLogins,
+		MaxFailedLoginsPerIP:     maxFailedLoginsPerIP,
+		LoginLockoutDuration:     loginLockoutDuration,
+	})
 	authHandler := auth.NewAuthHandler(authService, appValidator)
 	// Auth routes (login/register/refresh) usually don't need authentication middleware,
-	// so register them directly on the main router 'r'.
-	authHandler.RegisterRoutes(r)
+	// so register them directly on the main router 'r'. Logout routes wrap themselves with authMiddleware.
+	authHandler.RegisterRoutes(r, authMiddleware)
+
+	// Profile Module Wiring. Users read and update their own account.
+	profileService := profile.NewProfileService(userRepo, membershipRepo, authService, passwordPolicyService, transactor)
+	profileHandler := profile.NewProfileHandler(profileService, appValidator)
+
+	// Users Module Wiring. Tenant admins manage the members of their tenant.
+	userService := users.NewUserService(userRepo, membershipRepo, roleRepo, authService, transactor)
+	userHandler := users.NewUserHandler(userService, appValidator)
+
+	// Employee Module Wiring. Employees are records a tenant keeps about its staff, stored in the
+	// 'employees' table; they are not user accounts.
+	employeeRepo := repository.NewPostgreSQLEmployeeRepository(db)
+	departmentRepo := repository.NewPostgreSQLDepartmentRepository(db)
+	employeeService := employee.NewEmployeeService(employeeRepo, departmentRepo, transactor)
+	employeeHandler := employee.NewEmployeeHandler(employeeService, appValidator)
+
+	// Department Module Wiring. The department hierarchy employees are organized in.
+	departmentService := department.NewDepartmentService(departmentRepo, transactor)
+	departmentHandler := department.NewDepartmentHandler(departmentService, appValidator)
+
+	// Privacy Module Wiring. Data exports and erasures are queued and processed in the background;
+	// export bundles are written to PRIVACY_EXPORT_DIR and deleted after PRIVACY_EXPORT_TTL_HOURS.
+	// Requests a worker hasn't finished within PRIVACY_CLAIM_TIMEOUT_MINUTES are picked up again.
+	privacyExportDir := os.Getenv("PRIVACY_EXPORT_DIR")
+	if privacyExportDir == "" {
+		privacyExportDir = "./tmp/exports"
+	}
+	privacyExportTTL := 7 * 24 * time.Hour
+	if hours, _ := strconv.Atoi(os.Getenv("PRIVACY_EXPORT_TTL_HOURS")); hours > 0 {
+		privacyExportTTL = time.Duration(hours) * time.Hour
+	}
+	privacyWorkerInterval := 30 * time.Second
+	if seconds, err := strconv.Atoi(os.Getenv("PRIVACY_WORKER_INTERVAL_SECONDS")); err == nil {
+		privacyWorkerInterval = time.Duration(seconds) * time.Second
+	}
+	privacyClaimTimeout := 30 * time.Minute
+	if minutes, _ := strconv.Atoi(os.Getenv("PRIVACY_CLAIM_TIMEOUT_MINUTES")); minutes > 0 {
+		privacyClaimTimeout = time.Duration(minutes) * time.Minute
+	}
+	privacyService := privacy.NewPrivacyService(privacy.Dependencies{
+		PrivacyRequestRepo: repository.NewPostgreSQLPrivacyRequestRepository(db),
+		PersonalDataRepo:   repository.NewPostgreSQLPersonalDataRepository(db),
+		AuditLogRepo:       repository.NewPostgreSQLAuditLogRepository(db),
+		UserRepo:           userRepo,
+		MembershipRepo:     membershipRepo,
+		AuthService:        authService,
+		Transactor:         transactor,
+	}, privacy.Config{
+		ExportDir:    privacyExportDir,
+		ExportTTL:    privacyExportTTL,
+		ClaimTimeout: privacyClaimTimeout,
+	})
+	privacyService.StartWorker(appCtx, privacyWorkerInterval)
+	privacyHandler := privacy.NewPrivacyHandler(privacyService)
+
+	// Invitation Module Wiring. Accepting an invitation is public; managing them needs users:write.
+	invitationRepo := repository.NewPostgreSQLInvitationRepository(db)
+	invitationService := invitation.NewInvitationService(invitation.Dependencies{
+		InvitationRepo: invitationRepo,
+		UserRepo:       userRepo,
+		RoleRepo:       roleRepo,
+		TenantRepo:     tenantRepo,
+		MembershipRepo: membershipRepo,
+		Mailer:         appMailer,
+		AuthService:    authService,
+		PasswordPolicy: passwordPolicyService,
+		Transactor:     transactor,
+	}, invitation.Config{
+		FrontendURL:   frontendURL,
+		InvitationTTL: invitationTTL,
+	})
+	invitationHandler := invitation.NewInvitationHandler(invitationService, appValidator)
+	invitationHandler.RegisterRoutes(r)
+
+	// SSO Module Wiring. OpenID Connect providers are configured in the JSON file named by
+	// OIDC_PROVIDERS_FILE; without it, SSO is disabled.
+	if providersFile := os.Getenv("OIDC_PROVIDERS_FILE"); providersFile != "" {
+		providerConfigs, err := sso.LoadProviderConfigs(providersFile)
+		if err != nil {
+			log.Fatalf(context.Background(), "Failed to load OIDC providers: %v", err)
+		}
+		identityRepo := repository.NewPostgreSQLUserIdentityRepository(db)
+		ssoService := sso.NewSSOService(providerConfigs, userRepo, identityRepo, authService)
+		ssoHandler := sso.NewSSOHandler(ssoService)
+		ssoHandler.RegisterRoutes(r)
+		log.Infof(context.Background(), "SSO enabled with %d OIDC provider(s)", len(providerConfigs))
+	}
 
 	// Create a Sub-Router for Authenticated Routes
 	// All routes registered on this sub-router will have the specified middlewares applied.
 	authenticatedRouter := r.PathPrefix("/api/v1").Subrouter() // All authenticated API endpoints will start with /api/v1
 	authenticatedRouter.Use(middleware.RecoveryMiddleware)
 	authenticatedRouter.Use(middleware.LoggingMiddleware)
-	authenticatedRouter.Use(middleware.AuthMiddleware)
-
-	// Example of an authenticated endpoint (user info)
-	authenticatedRouter.HandleFunc("/user/me", func(w http.ResponseWriter, r *http.Request) {
-		userID := r.Context().Value(middleware.ContextKeyUserID).(string)
-		tenantID := r.Context().Value(middleware.ContextKeyTenantID).(string)
-		userRole := r.Context().Value(middleware.ContextKeyUserRole).(string)
-
-		resp := map[string]string{
-			"message":  "You accessed an authenticated endpoint!",
-			"userID":   userID,
-			"tenantID": tenantID,
-			"role":     userRole,
-		}
-		utils.RespondJSON(w, http.StatusOK, resp)
-	}).Methods("GET")
+	authenticatedRouter.Use(authMiddleware)
+
+	// The caller's own profile and password.
+	profileHandler.RegisterRoutes(authenticatedRouter)
+	// Role administration endpoints; each route declares the permission it requires.
+	rbacHandler.RegisterRoutes(authenticatedRouter)
+	// The caller's own account (e.g. the tenants they can switch to).
+	authHandler.RegisterUserRoutes(authenticatedRouter)
+	// Account administration (e.g. lifting login lockouts).
+	authHandler.RegisterAdminRoutes(authenticatedRouter)
+	// Administration of the users of the caller's tenant.
+	userHandler.RegisterRoutes(authenticatedRouter)
+	// Data exports and erasure (GDPR data subject requests).
+	privacyHandler.RegisterRoutes(authenticatedRouter)
+	// Invitations of new users into the caller's tenant.
+	invitationHandler.RegisterAdminRoutes(authenticatedRouter)
+	// The tenant's password policy.
+	passwordPolicyHandler.RegisterRoutes(authenticatedRouter)
+	// Tenant API key management.
+	apiKeyHandler.RegisterRoutes(authenticatedRouter)
+	// Platform administration of tenants.
+	tenantHandler.RegisterRoutes(authenticatedRouter)
+	// The tenant's employee records.
+	employeeHandler.RegisterRoutes(authenticatedRouter)
+	// The tenant's departments.
+	departmentHandler.RegisterRoutes(authenticatedRouter)
 
 	// --- Placeholder for future authenticated modules (e.g., Client, Project, Tax Report) ---
 	/*
@@ -149,20 +431,6 @@ func main() {
 		projectHandler.RegisterRoutes(authenticatedRouter) // Register Project routes on authenticated sub-router
 	*/
 
-	// --- Employee Module Demo (DISABLED BY DEFAULT) ---
-	// This module is kept for architectural demonstration purposes but is not wired
-	// by default as its database schema is not compatible with the new 'users' table.
-	/*
-		// If you want to enable the employee demo, you would need to:
-		// 1. Revert your database migration to the old 'users' table schema (or create a new migration for it).
-		// 2. Uncomment the following lines:
-		// employeeRepo := repository.NewPostgreSQLEmployeeRepository(db)
-		// employeeService := employee.NewEmployeeService(employeeRepo)
-		// employeeHandler := employee.NewEmployeeHandler(employeeService, appValidator)
-		// employeeHandler.RegisterRoutes(authenticatedRouter)
-	*/
-	// --- END Employee Module Demo ---
-
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -187,6 +455,7 @@ func main() {
 	<-quit
 
 	log.Info(context.Background(), "Shutting down server...")
+	stopBackgroundJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
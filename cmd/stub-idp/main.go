@@ -0,0 +1,232 @@
+// Command stub-idp is a minimal OpenID Connect provider for trying out and testing the SSO
+// login locally. It signs in anyone: the authorize endpoint shows a form asking for an email and
+// name (or uses the login_hint parameter) and issues an authorization code for it.
+//
+// It is not a real identity provider and must never be exposed publicly.
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"flag"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/platform/oidc"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/jwtkeys"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type authorization struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	email         string
+	name          string
+	expiresAt     time.Time
+}
+
+type stubIdP struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	keys         *jwtkeys.Manager
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+var loginForm = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html><head><title>Stub IdP</title></head>
+<body>
+<h1>Stub IdP sign in</h1>
+<form method="post" action="/authorize">
+{{range $k, $v := .Params}}<input type="hidden" name="{{$k}}" value="{{index $v 0}}">
+{{end}}<p><label>Email <input name="email" type="email" required></label></p>
+<p><label>Name <input name="name"></label></p>
+<p><button type="submit">Sign in</button></p>
+</form>
+</body></html>`))
+
+func main() {
+	addr := flag.String("addr", ":9999", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9999", "issuer URL, as configured in the relying party")
+	clientID := flag.String("client-id", "starterpack", "accepted client ID")
+	clientSecret := flag.String("client-secret", "stub-secret", "accepted client secret; empty accepts public clients")
+	flag.Parse()
+
+	log.InitLogger("development")
+	defer log.Sync()
+	ctx := context.Background()
+
+	keys, err := jwtkeys.NewManager(jwtkeys.Config{Algorithm: jwtkeys.AlgRS256})
+	if err != nil {
+		log.Fatalf(ctx, "Failed to create signing key: %v", err)
+	}
+	idp := &stubIdP{
+		issuer:       strings.TrimSuffix(*issuer, "/"),
+		clientID:     *clientID,
+		clientSecret: *clientSecret,
+		keys:         keys,
+		codes:        make(map[string]authorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		utils.RespondJSON(w, http.StatusOK, keys.JWKS())
+	})
+	mux.HandleFunc("/authorize", idp.authorize)
+	mux.HandleFunc("/token", idp.token)
+
+	log.Infof(ctx, "Stub IdP listening on %s (issuer %s, client %s)", *addr, idp.issuer, idp.clientID)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		log.Fatalf(ctx, "Stub IdP stopped: %v", err)
+	}
+}
+
+func (p *stubIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	utils.RespondJSON(w, http.StatusOK, oidc.Discovery{
+		Issuer:                p.issuer,
+		AuthorizationEndpoint: p.issuer + "/authorize",
+		TokenEndpoint:         p.issuer + "/token",
+		JWKSURI:               p.issuer + "/jwks",
+		SigningAlgs:           []string{jwtkeys.AlgRS256},
+	})
+}
+
+// authorize shows the login form on GET and issues a code on POST, or directly on GET when a
+// login_hint is given.
+func (p *stubIdP) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	params := r.Form
+	if params.Get("response_type") != "code" || params.Get("client_id") != p.clientID || params.Get("redirect_uri") == "" {
+		http.Error(w, "unsupported response_type, unknown client_id or missing redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if params.Get("code_challenge_method") != "S256" || params.Get("code_challenge") == "" {
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+
+	email := params.Get("email")
+	if r.Method == http.MethodGet {
+		email = params.Get("login_hint")
+	}
+	if email == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		formParams := url.Values{}
+		for _, k := range []string{"response_type", "client_id", "redirect_uri", "scope", "state", "nonce", "code_challenge", "code_challenge_method"} {
+			formParams.Set(k, params.Get(k))
+		}
+		_ = loginForm.Execute(w, map[string]interface{}{"Params": formParams})
+		return
+	}
+
+	code, err := oidc.RandomString()
+	if err != nil {
+		http.Error(w, "failed to generate code", http.StatusInternalServerError)
+		return
+	}
+	p.mu.Lock()
+	p.codes[code] = authorization{
+		clientID:      params.Get("client_id"),
+		redirectURI:   params.Get("redirect_uri"),
+		codeChallenge: params.Get("code_challenge"),
+		nonce:         params.Get("nonce"),
+		email:         email,
+		name:          params.Get("name"),
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	p.mu.Unlock()
+
+	redirect, err := url.Parse(params.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	q := redirect.Query()
+	q.Set("code", code)
+	q.Set("state", params.Get("state"))
+	redirect.RawQuery = q.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token redeems an authorization code for an ID token.
+func (p *stubIdP) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != p.clientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(p.clientSecret)) != 1 {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	auth, found := p.codes[code]
+	delete(p.codes, code) // Codes are single-use
+	p.mu.Unlock()
+	if !found || time.Now().After(auth.expiresAt) || auth.clientID != clientID || auth.redirectURI != r.PostForm.Get("redirect_uri") {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	if oidc.CodeChallengeS256(r.PostForm.Get("code_verifier")) != auth.codeChallenge {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	// A stable subject per email, as a real provider would keep per account.
+	sum := sha256.Sum256([]byte(strings.ToLower(auth.email)))
+	subject := hex.EncodeToString(sum[:8])
+	idToken, err := p.keys.Sign(jwt.MapClaims{
+		"iss":            p.issuer,
+		"sub":            subject,
+		"aud":            clientID,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+		"nonce":          auth.nonce,
+		"email":          auth.email,
+		"email_verified": true,
+		"name":           auth.name,
+	})
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+	accessToken, _ := oidc.RandomString()
+
+	w.Header().Set("Cache-Control", "no-store")
+	utils.RespondJSON(w, http.StatusOK, oidc.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		IDToken:     idToken,
+		ExpiresIn:   300,
+	})
+}
+
+func tokenError(w http.ResponseWriter, status int, code string) {
+	utils.RespondJSON(w, status, map[string]string{"error": code})
+}
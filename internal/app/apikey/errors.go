@@ -0,0 +1,15 @@
+package apikey
+
+import (
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/utils/errors"
+)
+
+// Module-specific custom errors for API keys.
+var (
+	ErrAPIKeyNotFound    = errors.New("API_KEY_NOT_FOUND", "API key with given ID not found", http.StatusNotFound, nil, nil)
+	ErrScopeNotGrantable = errors.New("API_KEY_SCOPE_NOT_ALLOWED", "API keys can only be granted permissions you hold yourself", http.StatusForbidden, nil, nil)
+	ErrInvalidExpiry     = errors.New("API_KEY_INVALID_EXPIRY", "Expiry must be in the future", http.StatusBadRequest, nil, nil)
+	ErrKeyCannotManage   = errors.New("API_KEY_NOT_ALLOWED", "API keys can't be managed with an API key", http.StatusForbidden, nil, nil)
+)
@@ -0,0 +1,111 @@
+package apikey
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/http/middleware"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type APIKeyHandler struct {
+	service   *APIKeyService
+	validator *validator.Validate
+}
+
+// NewAPIKeyHandler creates a new instance of APIKeyHandler.
+func NewAPIKeyHandler(s *APIKeyService, v *validator.Validate) *APIKeyHandler {
+	return &APIKeyHandler{service: s, validator: v}
+}
+
+// RegisterRoutes registers the API key management routes on the authenticated router. Keys are
+// managed by users only; a request authenticated with an API key is refused.
+func (h *APIKeyHandler) RegisterRoutes(router *mux.Router) {
+	manage := func(handler http.HandlerFunc) http.Handler {
+		return middleware.RequirePermission(domain.PermissionAPIKeysManage)(rejectAPIKeyCallers(handler))
+	}
+	router.Handle("/api-keys", manage(h.GetAPIKeys)).Methods("GET")
+	router.Handle("/api-keys", manage(h.CreateAPIKey)).Methods("POST")
+	router.Handle("/api-keys/{id}", manage(h.GetAPIKeyByID)).Methods("GET")
+	router.Handle("/api-keys/{id}", manage(h.RevokeAPIKey)).Methods("DELETE")
+}
+
+// CreateAPIKey handles the request to create an API key.
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	tenantID, _ := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	userID, _ := r.Context().Value(middleware.ContextKeyUserID).(string)
+
+	keyResp, err := h.service.CreateAPIKey(r.Context(), tenantID, userID, req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, keyResp)
+}
+
+// GetAPIKeys handles the request to list the tenant's API keys.
+func (h *APIKeyHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := r.Context().Value(middleware.ContextKeyTenantID).(string)
+
+	keys, err := h.service.GetAPIKeys(r.Context(), tenantID)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, keys)
+}
+
+// GetAPIKeyByID handles the request to get an API key.
+func (h *APIKeyHandler) GetAPIKeyByID(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := r.Context().Value(middleware.ContextKeyTenantID).(string)
+
+	keyResp, err := h.service.GetAPIKeyByID(r.Context(), tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, keyResp)
+}
+
+// RevokeAPIKey handles the request to revoke an API key.
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := r.Context().Value(middleware.ContextKeyTenantID).(string)
+
+	if err := h.service.RevokeAPIKey(r.Context(), tenantID, mux.Vars(r)["id"]); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// rejectAPIKeyCallers refuses requests authenticated with an API key, so a leaked key can't be
+// used to mint further keys.
+func rejectAPIKeyCallers(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, isKey := r.Context().Value(middleware.ContextKeyAPIKeyID).(string); isKey {
+			utils.HandleHTTPError(w, ErrKeyCannotManage, r)
+			return
+		}
+		next(w, r)
+	})
+}
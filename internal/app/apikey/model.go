@@ -0,0 +1,29 @@
+package apikey
+
+import "time"
+
+// CreateAPIKeyRequest is the DTO for creating an API key.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required,max=100"` // Permissions granted to the key
+	ExpiresAt *time.Time `json:"expires_at"`                                             // Optional; the key never expires when omitted
+}
+
+// APIKeyResponse is the DTO for responding with API key details. It never contains the key itself.
+type APIKeyResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	CreatedBy  string   `json:"created_by,omitempty"`
+	ExpiresAt  *string  `json:"expires_at,omitempty"`
+	LastUsedAt *string  `json:"last_used_at,omitempty"`
+	RevokedAt  *string  `json:"revoked_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse is returned once, on creation, and is the only time the key is shown.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
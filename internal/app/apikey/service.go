@@ -0,0 +1,206 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/google/uuid"
+)
+
+// keyPrefix marks the application's API keys, so they are easy to spot, e.g. by secret scanners.
+const keyPrefix = "sk_"
+
+// lastUsedResolution limits how often last_used_at is written for a busy key.
+const lastUsedResolution = time.Minute
+
+// PermissionResolver loads the permissions a user holds within a tenant.
+type PermissionResolver interface {
+	ResolvePermissions(ctx context.Context, tenantID, userID string) ([]string, error)
+}
+
+type APIKeyService struct {
+	apiKeyRepo  domain.APIKeyRepository
+	permissions PermissionResolver
+}
+
+// NewAPIKeyService creates a new instance of APIKeyService.
+func NewAPIKeyService(apiKeyRepo domain.APIKeyRepository, permissions PermissionResolver) *APIKeyService {
+	return &APIKeyService{apiKeyRepo: apiKeyRepo, permissions: permissions}
+}
+
+// CreateAPIKey creates a key for the tenant. The creator can only grant permissions they hold.
+// The returned response is the only place the full key appears.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, tenantID, userID string, req CreateAPIKeyRequest) (*CreatedAPIKeyResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	held, err := s.permissions.ResolvePermissions(ctx, tenantID, userID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to resolve permissions: %w", err), "Internal error creating API key.")
+	}
+	heldSet := make(map[string]struct{}, len(held))
+	for _, perm := range held {
+		heldSet[perm] = struct{}{}
+	}
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]struct{}, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if _, ok := heldSet[scope]; !ok {
+			return nil, errors.New(ErrScopeNotGrantable.Code(), ErrScopeNotGrantable.Message(), ErrScopeNotGrantable.Status(), nil, map[string]interface{}{"scope": scope})
+		}
+		if _, dup := seen[scope]; !dup {
+			seen[scope] = struct{}{}
+			scopes = append(scopes, scope)
+		}
+	}
+
+	rawKey, prefix, err := generateKey()
+	if err != nil {
+		return nil, errors.NewInternalServerError(err, "Internal error creating API key.")
+	}
+	key := &domain.APIKey{
+		ID:        uuid.New(),
+		TenantID:  parsedTenantID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   utils.HashToken(rawKey),
+		Scopes:    scopes,
+		CreatedBy: uuid.NullUUID{UUID: parsedUserID, Valid: true},
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := s.apiKeyRepo.Save(ctx, key); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to save API key: %w", err), "Internal error creating API key.")
+	}
+	log.Infof(ctx, "APIKey: Key %s (%s) created by user %s in tenant %s", key.ID, key.Prefix, userID, tenantID)
+
+	return &CreatedAPIKeyResponse{APIKeyResponse: newAPIKeyResponse(key), Key: rawKey}, nil
+}
+
+// GetAPIKeys lists the tenant's keys, including revoked and expired ones.
+func (s *APIKeyService) GetAPIKeys(ctx context.Context, tenantID string) ([]APIKeyResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	keys, err := s.apiKeyRepo.FindAll(ctx, parsedTenantID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to fetch API keys: %w", err), "Internal error fetching API keys.")
+	}
+	resp := make([]APIKeyResponse, len(keys))
+	for i := range keys {
+		resp[i] = newAPIKeyResponse(&keys[i])
+	}
+	return resp, nil
+}
+
+// GetAPIKeyByID returns a key of the tenant.
+func (s *APIKeyService) GetAPIKeyByID(ctx context.Context, tenantID, id string) (*APIKeyResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid API key ID format", nil)
+	}
+	key, err := s.apiKeyRepo.FindByID(ctx, parsedTenantID, parsedID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to fetch API key: %w", err), "Internal error fetching API key.")
+	}
+	if key == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	resp := newAPIKeyResponse(key)
+	return &resp, nil
+}
+
+// RevokeAPIKey revokes a key of the tenant; requests using it are rejected right away.
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, tenantID, id string) error {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return errors.ErrUnauthorized
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return errors.NewBadRequest("Invalid API key ID format", nil)
+	}
+	revoked, err := s.apiKeyRepo.Revoke(ctx, parsedTenantID, parsedID)
+	if err != nil {
+		return errors.NewInternalServerError(fmt.Errorf("failed to revoke API key: %w", err), "Internal error revoking API key.")
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	log.Infof(ctx, "APIKey: Key %s revoked in tenant %s", parsedID, tenantID)
+	return nil
+}
+
+// AuthenticateAPIKey implements middleware.APIKeyAuthenticator. It returns nil for unknown,
+// revoked and expired keys, and records when the key was last used.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+	key, err := s.apiKeyRepo.FindByHash(ctx, utils.HashToken(rawKey))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if key == nil || !key.IsActive(now) {
+		return nil, nil
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			// Tracking is best effort and must not block the request.
+			log.Warnf(ctx, "APIKey: Failed to record last use of key %s: %v", key.ID, err)
+		}
+	}
+	return key, nil
+}
+
+// generateKey returns a new key of the form sk_<8 hex chars>_<secret> and its public prefix.
+func generateKey() (string, string, error) {
+	id := make([]byte, 4)
+	secret := make([]byte, 24)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	prefix := keyPrefix + hex.EncodeToString(id)
+	return prefix + "_" + base64.RawURLEncoding.EncodeToString(secret), prefix, nil
+}
+
+func newAPIKeyResponse(key *domain.APIKey) APIKeyResponse {
+	resp := APIKeyResponse{
+		ID:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  utils.FormatOptionalTime(key.ExpiresAt),
+		LastUsedAt: utils.FormatOptionalTime(key.LastUsedAt),
+		RevokedAt:  utils.FormatOptionalTime(key.RevokedAt),
+		CreatedAt:  key.CreatedAt.Format(utils.ISO8601TimeFormat),
+	}
+	if key.CreatedBy.Valid {
+		resp.CreatedBy = key.CreatedBy.UUID.String()
+	}
+	return resp
+}
@@ -7,9 +7,26 @@ import (
 )
 
 var (
-	ErrUserNotFound        = errors.New("USER_NOT_FOUND", "User with given email not found", http.StatusNotFound, nil, nil)
-	ErrUserAlreadyExists   = errors.New("USER_ALREADY_EXISTS", "User with this email already exists", http.StatusConflict, nil, nil)
-	ErrInvalidCredentials  = errors.New("INVALID_CREDENTIALS", "Invalid email or password", http.StatusUnauthorized, nil, nil)
-	ErrInvalidToken        = errors.New("INVALID_TOKEN", "Invalid or expired token", http.StatusUnauthorized, nil, nil)
-	ErrRefreshTokenExpired = errors.New("REFRESH_TOKEN_EXPIRED", "Refresh token has expired, please login again", http.StatusUnauthorized, nil, nil)
+	ErrUserNotFound             = errors.New("USER_NOT_FOUND", "User with given email not found", http.StatusNotFound, nil, nil)
+	ErrUserAlreadyExists        = errors.New("USER_ALREADY_EXISTS", "User with this email already exists in this tenant", http.StatusConflict, nil, nil)
+	ErrInvalidCredentials       = errors.New("INVALID_CREDENTIALS", "Invalid email or password", http.StatusUnauthorized, nil, nil)
+	ErrInvalidToken             = errors.New("INVALID_TOKEN", "Invalid or expired token", http.StatusUnauthorized, nil, nil)
+	ErrRefreshTokenExpired      = errors.New("REFRESH_TOKEN_EXPIRED", "Refresh token has expired, please login again", http.StatusUnauthorized, nil, nil)
+	ErrInvalidResetToken        = errors.New("INVALID_RESET_TOKEN", "Password reset token is invalid, expired or already used", http.StatusBadRequest, nil, nil)
+	ErrInvalidVerificationToken = errors.New("INVALID_VERIFICATION_TOKEN", "Email verification token is invalid, expired or already used", http.StatusBadRequest, nil, nil)
+	ErrEmailNotVerified         = errors.New("EMAIL_NOT_VERIFIED", "Please verify your email address before logging in", http.StatusForbidden, nil, nil)
+	ErrInvalidMFAToken          = errors.New("INVALID_MFA_TOKEN", "MFA challenge is invalid or expired, please login again", http.StatusUnauthorized, nil, nil)
+	ErrInvalidMFACode           = errors.New("INVALID_MFA_CODE", "Invalid or already used authentication code", http.StatusUnauthorized, nil, nil)
+	ErrMFANotEnrolled           = errors.New("MFA_NOT_ENROLLED", "Start MFA enrollment before confirming it", http.StatusBadRequest, nil, nil)
+	ErrMFANotEnabled            = errors.New("MFA_NOT_ENABLED", "MFA is not enabled for this account", http.StatusBadRequest, nil, nil)
+	ErrMFAAlreadyEnabled        = errors.New("MFA_ALREADY_ENABLED", "MFA is already enabled for this account", http.StatusConflict, nil, nil)
+	ErrAccountLocked            = errors.New("ACCOUNT_LOCKED", "Account is temporarily locked after too many failed login attempts", http.StatusLocked, nil, nil)
+	ErrAccountDisabled          = errors.New("ACCOUNT_DISABLED", "This account has been disabled", http.StatusForbidden, nil, nil)
+	ErrLoginThrottled           = errors.New("LOGIN_THROTTLED", "Too many failed login attempts, please wait before trying again", http.StatusTooManyRequests, nil, nil)
+	ErrTenantUserNotFound       = errors.New("USER_NOT_FOUND", "User with given ID not found in this tenant", http.StatusNotFound, nil, nil)
+	ErrInvalidTenant            = errors.New("INVALID_TENANT", "Tenant with given ID does not exist", http.StatusBadRequest, nil, nil)
+	ErrSelfRegistrationDisabled = errors.New("REGISTRATION_DISABLED", "This tenant only accepts new users by invitation", http.StatusForbidden, nil, nil)
+	ErrNotTenantMember          = errors.New("NOT_TENANT_MEMBER", "You are not a member of this tenant", http.StatusForbidden, nil, nil)
+	ErrMembershipDeactivated    = errors.New("MEMBERSHIP_DEACTIVATED", "Your access to this tenant has been deactivated", http.StatusForbidden, nil, nil)
+	ErrRefreshTokenReused       = errors.New("REFRESH_TOKEN_REUSED", "Refresh token has already been used, all sessions from this login were revoked", http.StatusUnauthorized, nil, nil)
 )
@@ -2,8 +2,12 @@ package auth
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"time"
 
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/http/middleware"
 	"starterpack-golang-cleanarch/internal/utils"
 	globalErrors "starterpack-golang-cleanarch/internal/utils/errors"
 
@@ -20,10 +24,35 @@ func NewAuthHandler(s *AuthService, v *validator.Validate) *AuthHandler {
 	return &AuthHandler{service: s, validator: v}
 }
 
-func (h *AuthHandler) RegisterRoutes(router *mux.Router) {
+// RegisterRoutes registers the auth routes. Most of them are public; the ones acting on the
+// caller's own session are wrapped with authMiddleware.
+func (h *AuthHandler) RegisterRoutes(router *mux.Router, authMiddleware mux.MiddlewareFunc) {
 	router.HandleFunc("/auth/register", h.Register).Methods("POST")
 	router.HandleFunc("/auth/login", h.Login).Methods("POST")
 	router.HandleFunc("/auth/refresh", h.Refresh).Methods("POST")
+	router.HandleFunc("/auth/password/forgot", h.ForgotPassword).Methods("POST")
+	router.HandleFunc("/auth/password/reset", h.ResetPassword).Methods("POST")
+	router.HandleFunc("/auth/verify-email", h.VerifyEmail).Methods("POST")
+	router.HandleFunc("/auth/verify-email/resend", h.ResendVerificationEmail).Methods("POST")
+	router.Handle("/auth/logout", authMiddleware(http.HandlerFunc(h.Logout))).Methods("POST")
+	router.Handle("/auth/logout-all", authMiddleware(http.HandlerFunc(h.LogoutAll))).Methods("POST")
+	router.HandleFunc("/auth/mfa/verify", h.VerifyMFA).Methods("POST")
+	router.Handle("/auth/mfa/enroll", authMiddleware(http.HandlerFunc(h.EnrollMFA))).Methods("POST")
+	router.Handle("/auth/mfa/confirm", authMiddleware(http.HandlerFunc(h.ConfirmMFA))).Methods("POST")
+	router.Handle("/auth/mfa/disable", authMiddleware(http.HandlerFunc(h.DisableMFA))).Methods("POST")
+	router.Handle("/auth/mfa/recovery-codes", authMiddleware(http.HandlerFunc(h.RegenerateRecoveryCodes))).Methods("POST")
+	router.Handle("/auth/switch-tenant", authMiddleware(http.HandlerFunc(h.SwitchTenant))).Methods("POST")
+}
+
+// RegisterUserRoutes registers the routes about the caller's own account on the authenticated router.
+func (h *AuthHandler) RegisterUserRoutes(router *mux.Router) {
+	router.HandleFunc("/user/tenants", h.GetUserTenants).Methods("GET")
+}
+
+// RegisterAdminRoutes registers the account administration routes. They must be registered on
+// the authenticated router, as each route declares the permission it needs.
+func (h *AuthHandler) RegisterAdminRoutes(router *mux.Router) {
+	router.Handle("/users/{id}/unlock", middleware.RequirePermission(domain.PermissionUsersWrite)(http.HandlerFunc(h.UnlockUser))).Methods("POST")
 }
 
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
@@ -59,7 +88,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	authResp, err := h.service.LoginUser(r.Context(), req)
+	authResp, err := h.service.LoginUser(r.Context(), req, utils.ClientIP(r))
 	if err != nil {
 		utils.HandleHTTPError(w, err, r)
 		return
@@ -88,3 +117,298 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 
 	utils.RespondJSON(w, http.StatusOK, authResp)
 }
+
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	if err := h.service.ForgotPassword(r.Context(), req); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusAccepted, MessageResponse{Message: "If an account with that email exists, a password reset link has been sent."})
+}
+
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	if err := h.service.ResetPassword(r.Context(), req); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, MessageResponse{Message: "Password has been reset. Please log in again."})
+}
+
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	userResp, err := h.service.VerifyEmail(r.Context(), req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, userResp)
+}
+
+func (h *AuthHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	var req ResendVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	if err := h.service.ResendVerificationEmail(r.Context(), req); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusAccepted, MessageResponse{Message: "If an unverified account with that email exists, a verification link has been sent."})
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	session, ok := SessionFromContext(r)
+	if !ok {
+		utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+		return
+	}
+
+	// The body is optional: clients may call logout without sending their refresh token.
+	var req LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.service.Logout(r.Context(), session, req); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SwitchTenant handles the request to continue the session in another tenant of the user.
+func (h *AuthHandler) SwitchTenant(w http.ResponseWriter, r *http.Request) {
+	session, ok := SessionFromContext(r)
+	if !ok {
+		utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+		return
+	}
+
+	var req SwitchTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	authResp, err := h.service.SwitchTenant(r.Context(), session, req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, authResp)
+}
+
+// GetUserTenants handles the request to list the tenants the caller is a member of.
+func (h *AuthHandler) GetUserTenants(w http.ResponseWriter, r *http.Request) {
+	session, ok := SessionFromContext(r)
+	if !ok {
+		utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+		return
+	}
+
+	tenants, err := h.service.GetUserTenants(r.Context(), session)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, tenants)
+}
+
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	session, ok := SessionFromContext(r)
+	if !ok {
+		utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+		return
+	}
+
+	if err := h.service.LogoutAll(r.Context(), session); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var req MFAVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	authResp, err := h.service.VerifyMFA(r.Context(), req, utils.ClientIP(r))
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, authResp)
+}
+
+func (h *AuthHandler) EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	session, ok := SessionFromContext(r)
+	if !ok {
+		utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+		return
+	}
+
+	enrollResp, err := h.service.EnrollMFA(r.Context(), session)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, enrollResp)
+}
+
+func (h *AuthHandler) ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	session, req, ok := h.decodeMFACodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	codesResp, err := h.service.ConfirmMFA(r.Context(), session, req, utils.ClientIP(r))
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, codesResp)
+}
+
+func (h *AuthHandler) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	session, req, ok := h.decodeMFACodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DisableMFA(r.Context(), session, req, utils.ClientIP(r)); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	session, req, ok := h.decodeMFACodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	codesResp, err := h.service.RegenerateRecoveryCodes(r.Context(), session, req, utils.ClientIP(r))
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, codesResp)
+}
+
+// UnlockUser handles the request to lift a login lockout of a user in the caller's tenant.
+func (h *AuthHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+		return
+	}
+
+	if err := h.service.UnlockUser(r.Context(), tenantID, mux.Vars(r)["id"]); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeMFACodeRequest reads the session and the MFACodeRequest body shared by the MFA management
+// endpoints. It writes the error response itself and returns false on failure.
+func (h *AuthHandler) decodeMFACodeRequest(w http.ResponseWriter, r *http.Request) (SessionInfo, MFACodeRequest, bool) {
+	var req MFACodeRequest
+	session, ok := SessionFromContext(r)
+	if !ok {
+		utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+		return session, req, false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid request payload", nil), r)
+		return session, req, false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewBadRequest(err.Error(), nil), r)
+		return session, req, false
+	}
+	return session, req, true
+}
+
+// SessionFromContext reads the access token details stored by middleware.AuthMiddleware. It
+// reports false for requests authenticated with an API key, which have no token.
+func SessionFromContext(r *http.Request) (SessionInfo, bool) {
+	userID, _ := r.Context().Value(middleware.ContextKeyUserID).(string)
+	tenantID, _ := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	tokenID, _ := r.Context().Value(middleware.ContextKeyTokenID).(string)
+	expiresAt, _ := r.Context().Value(middleware.ContextKeyTokenExpiresAt).(time.Time)
+	if userID == "" || tokenID == "" {
+		return SessionInfo{}, false
+	}
+	return SessionInfo{UserID: userID, TenantID: tenantID, TokenID: tokenID, ExpiresAt: expiresAt}, true
+}
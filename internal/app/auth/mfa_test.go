@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/log"
+	"starterpack-golang-cleanarch/internal/utils/totp"
+
+	"github.com/google/uuid"
+)
+
+// fakeMFARepo keeps one enrollment in memory, with the same step and recovery code rules as the
+// PostgreSQL repository.
+type fakeMFARepo struct {
+	domain.MFARepository
+	lastUsedStep  int64
+	recoveryCodes map[string]bool // hash -> used
+}
+
+func (r *fakeMFARepo) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	if step <= r.lastUsedStep {
+		return false, nil
+	}
+	r.lastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeMFARepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	used, found := r.recoveryCodes[codeHash]
+	if !found || used {
+		return false, nil
+	}
+	r.recoveryCodes[codeHash] = true
+	return true, nil
+}
+
+func TestCheckMFACode(t *testing.T) {
+	log.InitLogger("testing")
+	ctx := context.Background()
+	// Secret of the RFC 6238 test vectors; see the totp package tests.
+	mfa := &domain.UserMFA{UserID: uuid.New(), TOTPSecret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"}
+	repo := &fakeMFARepo{recoveryCodes: map[string]bool{utils.HashToken("k3vq7mzp2xdrq4ta"): false}}
+	service := &AuthService{mfaRepo: repo}
+
+	now := time.Now()
+	code := currentCode(t, mfa.TOTPSecret, now)
+	if err := service.checkMFACode(ctx, mfa, code, false); err != nil {
+		t.Fatalf("checkMFACode() error = %v for a fresh code", err)
+	}
+	if err := service.checkMFACode(ctx, mfa, code, false); err != ErrInvalidMFACode {
+		t.Errorf("checkMFACode() error = %v for a reused code, want ErrInvalidMFACode", err)
+	}
+	// The code of the previous step is still within the skew, but older than the used step.
+	if previous := currentCode(t, mfa.TOTPSecret, now.Add(-totp.Period)); previous != code {
+		if err := service.checkMFACode(ctx, mfa, previous, false); err != ErrInvalidMFACode {
+			t.Errorf("checkMFACode() error = %v for the code of an earlier step, want ErrInvalidMFACode", err)
+		}
+	}
+	if err := service.checkMFACode(ctx, mfa, "000000x", true); err != ErrInvalidMFACode {
+		t.Errorf("checkMFACode() error = %v for a malformed code, want ErrInvalidMFACode", err)
+	}
+
+	if err := service.checkMFACode(ctx, mfa, "K3VQ-7MZP-2XDR-Q4TA", false); err != ErrInvalidMFACode {
+		t.Errorf("checkMFACode() error = %v for a recovery code where only TOTP is allowed, want ErrInvalidMFACode", err)
+	}
+	if err := service.checkMFACode(ctx, mfa, " K3VQ-7MZP-2XDR-Q4TA ", true); err != nil {
+		t.Errorf("checkMFACode() error = %v for an unused recovery code", err)
+	}
+	if err := service.checkMFACode(ctx, mfa, "k3vq7mzp2xdrq4ta", true); err != ErrInvalidMFACode {
+		t.Errorf("checkMFACode() error = %v for a used recovery code, want ErrInvalidMFACode", err)
+	}
+}
+
+// currentCode computes the TOTP code (RFC 6238) of the secret at time at.
+func currentCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(at.Unix()/int64(totp.Period.Seconds())))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff%1000000)
+}
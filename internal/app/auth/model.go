@@ -1,34 +1,124 @@
 package auth
 
+import "time"
+
 type RegisterRequest struct {
 	Name        string `json:"name" validate:"required"`
 	Email       string `json:"email" validate:"required,email"`
-	Password    string `json:"password" validate:"required,min=8"`
+	Password    string `json:"password" validate:"required"` // Checked against the tenant's password policy
 	PhoneNumber string `json:"phone_number" validate:"required"`
 	TenantID    string `json:"tenant_id" validate:"required,uuid"`
 }
 
+// LoginRequest optionally names the tenant to log in to. Without it, the tenant is found from the
+// email; when the credentials match accounts in several tenants, the client has to choose one.
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password" validate:"required"`
+	TenantSlug string `json:"tenant_slug"`
 }
 
+// AuthResponse is returned by login and refresh. When the user has MFA enabled, login only
+// returns MFARequired and the MFAToken to exchange at /auth/mfa/verify. When the credentials match
+// accounts in several tenants, login only returns TenantSelectionRequired and the Tenants to pick
+// from; the client repeats the login with the chosen tenant_slug.
 type AuthResponse struct {
-	AccessToken  string       `json:"access_token"`
-	RefreshToken string       `json:"refresh_token"`
-	User         UserResponse `json:"user"`
+	AccessToken             string         `json:"access_token,omitempty"`
+	RefreshToken            string         `json:"refresh_token,omitempty"`
+	User                    *UserResponse  `json:"user,omitempty"`
+	MFARequired             bool           `json:"mfa_required,omitempty"`
+	MFAToken                string         `json:"mfa_token,omitempty"`
+	TenantSelectionRequired bool           `json:"tenant_selection_required,omitempty"`
+	Tenants                 []TenantChoice `json:"tenants,omitempty"`
+}
+
+// TenantChoice is a tenant the user can log in to.
+type TenantChoice struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"` // Checked against the password policy
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"` // TOTP code or recovery code
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MessageResponse is a generic acknowledgement for endpoints without a resource to return.
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// LogoutRequest optionally carries the refresh token held by the client, so it is revoked
+// together with the access token used to call the endpoint.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// SwitchTenantRequest names the tenant to switch the session to.
+type SwitchTenantRequest struct {
+	TenantID string `json:"tenant_id" validate:"required,uuid"`
+}
+
+// UserTenantResponse is a tenant the user is a member of.
+type UserTenantResponse struct {
+	ID      string `json:"id"`
+	Slug    string `json:"slug"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Role    string `json:"role"`    // The user's role in the tenant
+	Current bool   `json:"current"` // Whether the access token of the request is for this tenant
+}
+
+// SessionInfo describes the authenticated access token of the current request.
+type SessionInfo struct {
+	UserID    string
+	TenantID  string
+	TokenID   string
+	ExpiresAt time.Time
+}
+
 type UserResponse struct {
-	ID          string `json:"id"`
-	TenantID    string `json:"tenant_id"`
-	Email       string `json:"email"`
-	Name        string `json:"name"`
-	PhoneNumber string `json:"phone_number"`
-	Role        string `json:"role"`
-	CreatedAt   string `json:"created_at"`
+	ID            string `json:"id"`
+	TenantID      string `json:"tenant_id"`
+	Email         string `json:"email"`
+	Name          string `json:"name"`
+	PhoneNumber   string `json:"phone_number"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
+	CreatedAt     string `json:"created_at"`
 }
@@ -2,33 +2,106 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base32"
 	"fmt"
+	"strings"
 	"time"
 
+	"starterpack-golang-cleanarch/internal/app/passwordpolicy"
 	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/mailer"
 	"starterpack-golang-cleanarch/internal/utils"
 	globalErrors "starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+	"starterpack-golang-cleanarch/internal/utils/totp"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Dependencies are the collaborators AuthService needs.
+type Dependencies struct {
+	UserRepo          domain.UserRepository
+	RefreshTokenRepo  domain.RefreshTokenRepository
+	RoleRepo          domain.RoleRepository
+	ActionTokenRepo   domain.ActionTokenRepository
+	MFARepo           domain.MFARepository
+	LoginThrottleRepo domain.LoginThrottleRepository
+	TenantRepo        domain.TenantRepository
+	MembershipRepo    domain.TenantMembershipRepository
+	Denylist          domain.TokenDenylist
+	Mailer            mailer.Mailer
+	PasswordPolicy    *passwordpolicy.PasswordPolicyService
+	Transactor        domain.Transactor
+}
+
+// Config holds the tunable settings of AuthService.
+type Config struct {
+	FrontendURL              string        // Base URL of the web app, used for links sent by email
+	PasswordResetTTL         time.Duration // Lifetime of password reset tokens
+	EmailVerificationTTL     time.Duration // Lifetime of email verification tokens
+	RequireEmailVerification bool          // Refuse logins until the user has verified their email
+	MFAIssuer                string        // Issuer shown in authenticator apps
+	MFAChallengeTTL          time.Duration // Time the user has to enter the MFA code after the password step
+	MaxFailedLogins          int           // Failed attempts per account before it is locked out
+	MaxFailedLoginsPerIP     int           // Failed attempts per client IP before it is blocked
+	LoginLockoutDuration     time.Duration // Lockout length; failures older than this are forgotten
+}
+
 type AuthService struct {
-	userRepo domain.UserRepository
+	userRepo          domain.UserRepository
+	refreshTokenRepo  domain.RefreshTokenRepository
+	roleRepo          domain.RoleRepository
+	actionTokenRepo   domain.ActionTokenRepository
+	mfaRepo           domain.MFARepository
+	loginThrottleRepo domain.LoginThrottleRepository
+	tenantRepo        domain.TenantRepository
+	membershipRepo    domain.TenantMembershipRepository
+	denylist          domain.TokenDenylist
+	mailer            mailer.Mailer
+	passwordPolicy    *passwordpolicy.PasswordPolicyService
+	transactor        domain.Transactor
+	cfg               Config
 }
 
-func NewAuthService(repo domain.UserRepository) *AuthService {
-	return &AuthService{userRepo: repo}
+func NewAuthService(deps Dependencies, cfg Config) *AuthService {
+	return &AuthService{
+		userRepo:          deps.UserRepo,
+		refreshTokenRepo:  deps.RefreshTokenRepo,
+		roleRepo:          deps.RoleRepo,
+		actionTokenRepo:   deps.ActionTokenRepo,
+		mfaRepo:           deps.MFARepo,
+		loginThrottleRepo: deps.LoginThrottleRepo,
+		tenantRepo:        deps.TenantRepo,
+		membershipRepo:    deps.MembershipRepo,
+		denylist:          deps.Denylist,
+		mailer:            deps.Mailer,
+		passwordPolicy:    deps.PasswordPolicy,
+		transactor:        deps.Transactor,
+		cfg:               cfg,
+	}
 }
 
 func (s *AuthService) RegisterUser(ctx context.Context, req RegisterRequest) (*UserResponse, error) {
-	existingUser, err := s.userRepo.FindByEmail(ctx, req.Email)
+	tenant, err := s.findActiveTenant(ctx, uuid.MustParse(req.TenantID))
+	if err != nil {
+		return nil, err
+	}
+	if !tenant.AllowsSelfRegistration() {
+		return nil, ErrSelfRegistrationDisabled
+	}
+
+	existingUser, err := s.userRepo.FindByEmail(ctx, tenant.ID, req.Email)
 	if err != nil {
 		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to check existing user: %w", err), "Internal error during user registration check.")
 	}
 	if existingUser != nil {
 		return nil, ErrUserAlreadyExists
 	}
+	if err := s.passwordPolicy.Check(ctx, tenant.ID, uuid.Nil, req.Password); err != nil {
+		return nil, err
+	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -41,106 +114,1217 @@ func (s *AuthService) RegisterUser(ctx context.Context, req RegisterRequest) (*U
 		Name:         req.Name,
 		PhoneNumber:  req.PhoneNumber,
 		TenantID:     uuid.MustParse(req.TenantID),
-		Role:         "user",
+		Role:         domain.RoleUser,
 	}
 	user.GenerateID()
 
-	if err := s.userRepo.Save(ctx, user); err != nil {
-		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to save user: %w", err), "Internal error saving user.")
+	// The password history is written in the same transaction, so a failed registration leaves
+	// nothing behind.
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ProvisionUser(ctx, user); err != nil {
+			return err
+		}
+		if err := s.passwordPolicy.RecordPassword(ctx, user.ID, user.PasswordHash); err != nil {
+			return globalErrors.NewInternalServerError(err, "Internal error during registration.")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	resp := &UserResponse{
-		ID:          user.ID.String(),
-		TenantID:    user.TenantID.String(),
-		Email:       user.Email,
-		Name:        user.Name,
-		PhoneNumber: user.PhoneNumber,
-		Role:        user.Role,
-		CreatedAt:   user.CreatedAt.Format(utils.ISO8601TimeFormat),
+	// The account exists at this point, so a mail failure must not fail the registration; the
+	// user can ask for a new link through the resend endpoint.
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		log.Errorf(ctx, "Auth: Failed to send verification email to user %s: %v", user.ID, err)
 	}
 
-	return resp, nil
+	resp := newUserResponse(user)
+	return &resp, nil
 }
 
-func (s *AuthService) LoginUser(ctx context.Context, req LoginRequest) (*AuthResponse, error) {
-	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+// LoginUser checks the credentials and issues tokens, or an MFA challenge when the user has MFA
+// enabled. The same email can have an account in several tenants: the request may name the tenant
+// with its slug, otherwise every account with the email is tried in its home tenant and the client
+// is asked to choose when the password matches more than one. Failed attempts are counted per
+// email and per client IP; see checkLoginThrottle.
+func (s *AuthService) LoginUser(ctx context.Context, req LoginRequest, clientIP string) (*AuthResponse, error) {
+	if err := s.checkLoginThrottle(ctx, req.Email, clientIP); err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.findLoginAccounts(ctx, req.Email, req.TenantSlug)
 	if err != nil {
-		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find user by email: %w", err), "Internal error during login.")
+		return nil, err
 	}
-	if user == nil {
-		return nil, ErrInvalidCredentials
+	var matches []loginAccount
+	for _, account := range accounts {
+		if bcrypt.CompareHashAndPassword([]byte(account.user.PasswordHash), []byte(req.Password)) == nil {
+			matches = append(matches, account)
+		}
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		return nil, ErrInvalidCredentials
+	switch len(matches) {
+	case 0:
+		return nil, s.recordLoginFailure(ctx, req.Email, clientIP, ErrInvalidCredentials)
+	case 1:
+		return s.CompleteLogin(ctx, &matches[0].user, matches[0].tenantID)
 	}
+	return s.selectTenant(ctx, matches)
+}
+
+// loginAccount is an account a login may be for, together with the tenant to log in to.
+type loginAccount struct {
+	user     domain.User
+	tenantID uuid.UUID
+}
 
-	accessToken, err := utils.GenerateAccessToken(user.ID.String(), user.TenantID.String(), user.Role)
+// findLoginAccounts returns the accounts with the email. With a tenant slug, these are the
+// accounts that are members of that tenant; otherwise every account in its home tenant.
+func (s *AuthService) findLoginAccounts(ctx context.Context, email, tenantSlug string) ([]loginAccount, error) {
+	users, err := s.userRepo.FindAllByEmail(ctx, email)
 	if err != nil {
-		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to generate access token: %w", err), "Internal error generating token.")
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find users by email: %w", err), "Internal error during login.")
 	}
-	refreshToken, err := utils.GenerateRefreshToken(user.ID.String())
+	if tenantSlug == "" {
+		accounts := make([]loginAccount, len(users))
+		for i := range users {
+			accounts[i] = loginAccount{user: users[i], tenantID: users[i].TenantID}
+		}
+		return accounts, nil
+	}
+
+	tenant, err := s.tenantRepo.FindBySlug(ctx, tenantSlug)
 	if err != nil {
-		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to generate refresh token: %w", err), "Internal error generating token.")
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find tenant by slug: %w", err), "Internal error during login.")
+	}
+	if tenant == nil {
+		return nil, nil
 	}
+	var accounts []loginAccount
+	for i := range users {
+		membership, err := s.membershipRepo.Find(ctx, users[i].ID, tenant.ID)
+		if err != nil {
+			return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find tenant membership: %w", err), "Internal error during login.")
+		}
+		if membership != nil {
+			accounts = append(accounts, loginAccount{user: users[i], tenantID: tenant.ID})
+		}
+	}
+	return accounts, nil
+}
 
-	return &AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User: UserResponse{
-			ID:          user.ID.String(),
-			TenantID:    user.TenantID.String(),
-			Email:       user.Email,
-			Name:        user.Name,
-			PhoneNumber: user.PhoneNumber,
-			Role:        user.Role,
-			CreatedAt:   user.CreatedAt.Format(utils.ISO8601TimeFormat),
-		},
-	}, nil
+// selectTenant handles credentials that match accounts in several tenants. Accounts of suspended
+// tenants are left out; when a single account remains, the user is logged in to it right away.
+// Otherwise the response lists the tenants to choose from.
+func (s *AuthService) selectTenant(ctx context.Context, accounts []loginAccount) (*AuthResponse, error) {
+	var active []loginAccount
+	var choices []TenantChoice
+	for _, account := range accounts {
+		tenant, err := s.tenantRepo.FindByID(ctx, account.tenantID)
+		if err != nil {
+			return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find tenant: %w", err), "Internal error during login.")
+		}
+		if tenant == nil || !tenant.IsActive() {
+			continue
+		}
+		active = append(active, account)
+		choices = append(choices, TenantChoice{ID: tenant.ID.String(), Slug: tenant.Slug, Name: tenant.Name})
+	}
+
+	switch len(active) {
+	case 0:
+		return nil, globalErrors.ErrTenantSuspended
+	case 1:
+		return s.CompleteLogin(ctx, &active[0].user, active[0].tenantID)
+	}
+	return &AuthResponse{TenantSelectionRequired: true, Tenants: choices}, nil
+}
+
+// CompleteLogin finishes the login of an authenticated user into a tenant they are a member of:
+// it enforces the email verification policy and returns either the token pair or, for users with
+// MFA enabled, an MFA challenge. It is used after the password check and by external login flows
+// such as SSO. Disabled accounts are refused.
+func (s *AuthService) CompleteLogin(ctx context.Context, user *domain.User, tenantID uuid.UUID) (*AuthResponse, error) {
+	if user.IsDisabled() {
+		return nil, ErrAccountDisabled
+	}
+	if s.cfg.RequireEmailVerification && !user.IsEmailVerified() {
+		return nil, ErrEmailNotVerified
+	}
+
+	mfa, err := s.mfaRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find MFA enrollment: %w", err), "Internal error during login.")
+	}
+	if mfa != nil && mfa.IsEnabled() {
+		// Second step: the client exchanges the challenge token and a code at /auth/mfa/verify.
+		challenge, _, err := utils.GenerateMFAChallengeToken(user.ID.String(), tenantID.String(), s.cfg.MFAChallengeTTL)
+		if err != nil {
+			return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to generate MFA challenge token: %w", err), "Internal error generating token.")
+		}
+		return &AuthResponse{MFARequired: true, MFAToken: challenge}, nil
+	}
+
+	if err := s.resetLoginThrottle(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	resp, _, err := s.issueTokens(ctx, user, tenantID, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
+// RefreshTokens exchanges a refresh token for a new token pair in the session's tenant. The
+// presented token is rotated: it can't be used again, and presenting it a second time revokes
+// every token in its family. Users who are no longer members of the tenant get no new tokens.
 func (s *AuthService) RefreshTokens(ctx context.Context, req RefreshTokenRequest) (*AuthResponse, error) {
 	claims, err := utils.ValidateToken(req.RefreshToken)
 	if err != nil {
 		return nil, ErrInvalidToken
 	}
+	if claims.TokenType != "" && claims.TokenType != utils.TokenTypeRefresh {
+		return nil, ErrInvalidToken
+	}
 
 	if claims.ExpiresAt.Time.Before(time.Now()) {
 		return nil, ErrRefreshTokenExpired
 	}
 
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	stored, err := s.refreshTokenRepo.FindByID(ctx, tokenID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find refresh token: %w", err), "Internal error during token refresh.")
+	}
+	if stored == nil || stored.UserID.String() != claims.UserID || stored.IsRevoked() {
+		return nil, ErrInvalidToken
+	}
+	if stored.IsRotated() {
+		return nil, s.handleRefreshTokenReuse(ctx, stored)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find user for refresh token: %w", err), "Internal error during token refresh.")
+	}
+	if user == nil {
+		return nil, ErrInvalidToken
+	}
+
+	tenantID := user.TenantID
+	if stored.TenantID.Valid {
+		tenantID = stored.TenantID.UUID
+	}
+	resp, newToken, err := s.issueTokens(ctx, user, tenantID, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new token is saved before the old one is rotated. If a concurrent request rotated the
+	// old token first, revoking the family also covers the token saved above.
+	rotated, err := s.refreshTokenRepo.Rotate(ctx, stored.ID, newToken.ID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to rotate refresh token: %w", err), "Internal error during token refresh.")
+	}
+	if !rotated {
+		return nil, s.handleRefreshTokenReuse(ctx, stored)
+	}
+
+	return resp, nil
+}
+
+// UnlockUser lifts the lockout of a member of the tenant and clears their failed login attempts.
+func (s *AuthService) UnlockUser(ctx context.Context, tenantID, userID string) error {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return globalErrors.ErrUnauthorized
+	}
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return globalErrors.NewBadRequest("Invalid user ID format", nil)
+	}
+	membership, err := s.membershipRepo.Find(ctx, parsedUserID, parsedTenantID)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find tenant membership: %w", err), "Internal error unlocking user.")
+	}
+	if membership == nil {
+		return ErrTenantUserNotFound
+	}
+	user, err := s.userRepo.FindByID(ctx, parsedUserID)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find user: %w", err), "Internal error unlocking user.")
+	}
+	if user == nil {
+		return ErrTenantUserNotFound
+	}
+	if err := s.resetLoginThrottle(ctx, user.Email); err != nil {
+		return err
+	}
+	log.Infof(ctx, "Auth: User %s unlocked", user.ID)
+	return nil
+}
+
+// Login throttling. Failures are counted per account (by email, whether or not the account
+// exists) and per client IP. After a few free attempts each further failure doubles the wait
+// before the next attempt; reaching the limit locks the account or blocks the IP for
+// LoginLockoutDuration.
+const (
+	accountFreeLoginAttempts = 3
+	ipFreeLoginAttempts      = 10
+	maxLoginDelay            = 30 * time.Second
+)
+
+// checkLoginThrottle refuses a login attempt while the account or client IP is locked out or
+// still has to wait after its last failure.
+func (s *AuthService) checkLoginThrottle(ctx context.Context, email, clientIP string) error {
+	now := time.Now()
+	account, err := s.loginThrottleRepo.Find(ctx, domain.LoginThrottleScopeAccount, normalizeEmail(email))
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find account login throttle: %w", err), "Internal error during login.")
+	}
+	if account != nil {
+		if account.IsLocked(now) {
+			return globalErrors.WithRetryAfter(ErrAccountLocked, account.LockedUntil.Sub(now))
+		}
+		if wait := account.LastFailureAt.Add(loginDelay(account.Failures, accountFreeLoginAttempts)).Sub(now); wait > 0 {
+			return globalErrors.WithRetryAfter(ErrLoginThrottled, wait)
+		}
+	}
+
+	if clientIP == "" {
+		return nil
+	}
+	ip, err := s.loginThrottleRepo.Find(ctx, domain.LoginThrottleScopeIP, clientIP)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find IP login throttle: %w", err), "Internal error during login.")
+	}
+	if ip != nil {
+		if ip.IsLocked(now) {
+			return globalErrors.WithRetryAfter(ErrLoginThrottled, ip.LockedUntil.Sub(now))
+		}
+		if wait := ip.LastFailureAt.Add(loginDelay(ip.Failures, ipFreeLoginAttempts)).Sub(now); wait > 0 {
+			return globalErrors.WithRetryAfter(ErrLoginThrottled, wait)
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt for the account and the client IP, locking them out
+// when they reach their limit. It returns the error to report for the attempt: cause, or the
+// lockout error if this attempt triggered the lockout.
+func (s *AuthService) recordLoginFailure(ctx context.Context, email, clientIP string, cause error) error {
+	result := cause
+
+	account, err := s.loginThrottleRepo.RecordFailure(ctx, domain.LoginThrottleScopeAccount, normalizeEmail(email), s.cfg.LoginLockoutDuration)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to record failed login: %w", err), "Internal error during login.")
+	}
+	if s.cfg.MaxFailedLogins > 0 && account.Failures >= s.cfg.MaxFailedLogins {
+		if err := s.loginThrottleRepo.Lock(ctx, account.Scope, account.Key, time.Now().Add(s.cfg.LoginLockoutDuration)); err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to lock account: %w", err), "Internal error during login.")
+		}
+		log.Warnf(ctx, "Auth: Account %s locked after %d failed login attempts", account.Key, account.Failures)
+		result = globalErrors.WithRetryAfter(ErrAccountLocked, s.cfg.LoginLockoutDuration)
+	}
+
+	if clientIP == "" {
+		return result
+	}
+	ip, err := s.loginThrottleRepo.RecordFailure(ctx, domain.LoginThrottleScopeIP, clientIP, s.cfg.LoginLockoutDuration)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to record failed login: %w", err), "Internal error during login.")
+	}
+	if s.cfg.MaxFailedLoginsPerIP > 0 && ip.Failures >= s.cfg.MaxFailedLoginsPerIP {
+		if err := s.loginThrottleRepo.Lock(ctx, ip.Scope, ip.Key, time.Now().Add(s.cfg.LoginLockoutDuration)); err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to block IP: %w", err), "Internal error during login.")
+		}
+		log.Warnf(ctx, "Auth: IP %s blocked after %d failed login attempts", ip.Key, ip.Failures)
+		if result == cause {
+			result = globalErrors.WithRetryAfter(ErrLoginThrottled, s.cfg.LoginLockoutDuration)
+		}
+	}
+	return result
+}
+
+// resetLoginThrottle clears the failed attempts of an account after a successful login. The
+// client IP keeps its count, which expires with the failure window.
+func (s *AuthService) resetLoginThrottle(ctx context.Context, email string) error {
+	if err := s.loginThrottleRepo.Reset(ctx, domain.LoginThrottleScopeAccount, normalizeEmail(email)); err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to reset login throttle: %w", err), "Internal error during login.")
+	}
+	return nil
+}
+
+// VerifyPassword checks the password of a signed-in user before a sensitive change, returning
+// wrongPassword when it doesn't match. Attempts count towards the login throttle of the user's
+// email and the client IP, so a hijacked session can't be used to guess the password faster than
+// the login endpoint allows.
+func (s *AuthService) VerifyPassword(ctx context.Context, user *domain.User, password, clientIP string, wrongPassword error) error {
+	if err := s.checkLoginThrottle(ctx, user.Email, clientIP); err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return s.recordLoginFailure(ctx, user.Email, clientIP, wrongPassword)
+	}
+	return nil
+}
+
+// loginDelay returns the wait required after the given number of consecutive failures.
+func loginDelay(failures, freeAttempts int) time.Duration {
+	if failures <= freeAttempts {
+		return 0
+	}
+	delay := time.Second
+	for i := freeAttempts + 1; i < failures && delay < maxLoginDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxLoginDelay {
+		return maxLoginDelay
+	}
+	return delay
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ProvisionUser stores a new user, makes them a member of their home tenant and grants them the
+// RBAC role named by user.Role there. It is used by registration and by flows that create users
+// on first login, such as SSO. The user's tenant must exist and be active. The user is stored
+// with their membership and role or not at all; called inside a unit of work, it joins it.
+func (s *AuthService) ProvisionUser(ctx context.Context, user *domain.User) error {
+	if _, err := s.findActiveTenant(ctx, user.TenantID); err != nil {
+		return err
+	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Save(ctx, user); err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to save user: %w", err), "Internal error saving user.")
+		}
+		return s.addMembership(ctx, user.ID, user.TenantID, user.Role)
+	})
+}
+
+// AddMembership makes an existing user a member of another tenant with the role, e.g. when they
+// accept an invitation. The tenant must exist and be active.
+func (s *AuthService) AddMembership(ctx context.Context, user *domain.User, tenantID uuid.UUID, roleName string) error {
+	if _, err := s.findActiveTenant(ctx, tenantID); err != nil {
+		return err
+	}
+	return s.addMembership(ctx, user.ID, tenantID, roleName)
+}
+
+// addMembership stores the membership and grants the user the RBAC role of the same name in the
+// tenant, in one transaction.
+func (s *AuthService) addMembership(ctx context.Context, userID, tenantID uuid.UUID, roleName string) error {
+	membership := &domain.TenantMembership{
+		UserID:    userID,
+		TenantID:  tenantID,
+		Role:      roleName,
+		CreatedAt: time.Now(),
+	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.membershipRepo.Save(ctx, membership); err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to save tenant membership: %w", err), "Internal error saving user.")
+		}
+		return s.assignRole(ctx, userID, tenantID, roleName)
+	})
+}
+
+// findActiveTenant returns the tenant, making sure it exists and is not suspended.
+func (s *AuthService) findActiveTenant(ctx context.Context, tenantID uuid.UUID) (*domain.Tenant, error) {
+	tenant, err := s.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find tenant: %w", err), "Internal error checking tenant.")
+	}
+	if tenant == nil {
+		return nil, ErrInvalidTenant
+	}
+	if !tenant.IsActive() {
+		return nil, globalErrors.ErrTenantSuspended
+	}
+	return tenant, nil
+}
+
+// assignRole grants the user the RBAC role with the given name in the tenant.
+func (s *AuthService) assignRole(ctx context.Context, userID, tenantID uuid.UUID, roleName string) error {
+	role, err := s.roleRepo.FindByName(ctx, tenantID, roleName)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find role %q: %w", roleName, err), "Internal error assigning user role.")
+	}
+	if role == nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("role %q does not exist", roleName), "Internal error assigning user role.")
+	}
+	if err := s.roleRepo.AssignToUser(ctx, tenantID, userID, role.ID); err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to assign role: %w", err), "Internal error assigning user role.")
+	}
+	return nil
+}
+
+// ForgotPassword emails a password reset link when an account with the email exists. When the
+// email has accounts in several tenants, each account gets its own link. It never reports whether
+// an account exists, so the endpoint can't be used to enumerate users.
+func (s *AuthService) ForgotPassword(ctx context.Context, req ForgotPasswordRequest) error {
+	users, err := s.userRepo.FindAllByEmail(ctx, req.Email)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find users by email: %w", err), "Internal error during password reset.")
+	}
+	if len(users) == 0 {
+		log.Debugf(ctx, "Auth: Password reset requested for unknown email")
+		return nil
+	}
+
+	for i := range users {
+		if err := s.sendPasswordResetEmail(ctx, &users[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendPasswordResetEmail replaces any outstanding reset token of the user and emails a new link
+// naming the account's tenant.
+func (s *AuthService) sendPasswordResetEmail(ctx context.Context, user *domain.User) error {
+	tenant, err := s.tenantRepo.FindByID(ctx, user.TenantID)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find tenant: %w", err), "Internal error during password reset.")
+	}
+	if tenant == nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("tenant %s of user %s does not exist", user.TenantID, user.ID), "Internal error during password reset.")
+	}
+
+	// Only the most recent link stays valid.
+	if err := s.actionTokenRepo.InvalidateForUser(ctx, user.ID, domain.ActionTokenPasswordReset); err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to invalidate reset tokens: %w", err), "Internal error during password reset.")
+	}
+	token, err := s.createActionToken(ctx, user.ID, domain.ActionTokenPasswordReset, s.cfg.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: fmt.Sprintf("Reset your %s password", tenant.Name),
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password for your %s account. It expires in %s and can only be used once.\n\n%s/reset-password?token=%s\n\nIf you did not ask for a password reset, you can ignore this email.\n",
+			user.Name, tenant.Name, s.cfg.PasswordResetTTL, s.cfg.FrontendURL, token),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		// Not surfaced to the client, which would reveal that the account exists.
+		log.Errorf(ctx, "Auth: Failed to send password reset email to user %s: %v", user.ID, err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token. The token is single-use, and all
+// existing sessions of the user are revoked afterwards.
+func (s *AuthService) ResetPassword(ctx context.Context, req ResetPasswordRequest) error {
+	token, err := s.actionTokenRepo.FindValid(ctx, domain.ActionTokenPasswordReset, utils.HashToken(req.Token))
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find reset token: %w", err), "Internal error during password reset.")
+	}
+	if token == nil {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find user for reset token: %w", err), "Internal error during password reset.")
+	}
+	if user == nil {
+		return ErrInvalidResetToken
+	}
+	// Checked before the token is consumed, so the user can try another password with the same link.
+	if err := s.passwordPolicy.Check(ctx, user.TenantID, user.ID, req.NewPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to hash password: %w", err), "Internal error during password hashing.")
+	}
+	user.PasswordHash = string(hashedPassword)
+	if !user.IsEmailVerified() {
+		// Opening the emailed reset link proves ownership of the address as well.
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	// The token is only used up when the new password is stored, so a failed update leaves the
+	// link valid for another attempt.
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		consumed, err := s.actionTokenRepo.Consume(ctx, token.ID)
+		if err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to consume reset token: %w", err), "Internal error during password reset.")
+		}
+		if !consumed {
+			return ErrInvalidResetToken
+		}
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to update password: %w", err), "Internal error during password reset.")
+		}
+		if err := s.passwordPolicy.RecordPassword(ctx, user.ID, user.PasswordHash); err != nil {
+			return globalErrors.NewInternalServerError(err, "Internal error during password reset.")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.RevokeAllSessions(ctx, user.ID); err != nil {
+		return globalErrors.NewInternalServerError(err, "Internal error during password reset.")
+	}
+	log.Infof(ctx, "Auth: Password reset for user %s, all sessions revoked", user.ID)
+	return nil
+}
+
+// InvalidatePasswordResets invalidates the user's outstanding password reset links, e.g. after
+// they changed their password, which the links would otherwise override.
+func (s *AuthService) InvalidatePasswordResets(ctx context.Context, userID uuid.UUID) error {
+	if err := s.actionTokenRepo.InvalidateForUser(ctx, userID, domain.ActionTokenPasswordReset); err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to invalidate reset tokens: %w", err), "Internal error invalidating password reset links.")
+	}
+	return nil
+}
+
+// VerifyEmail marks the user's email as verified using the token from the verification email.
+func (s *AuthService) VerifyEmail(ctx context.Context, req VerifyEmailRequest) (*UserResponse, error) {
+	token, err := s.actionTokenRepo.FindValid(ctx, domain.ActionTokenEmailVerification, utils.HashToken(req.Token))
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find verification token: %w", err), "Internal error during email verification.")
+	}
+	if token == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find user for verification token: %w", err), "Internal error during email verification.")
+	}
+	if user == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	// Like in ResetPassword, the token is only used up when the verification is stored.
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		consumed, err := s.actionTokenRepo.Consume(ctx, token.ID)
+		if err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to consume verification token: %w", err), "Internal error during email verification.")
+		}
+		if !consumed {
+			return ErrInvalidVerificationToken
+		}
+		if user.IsEmailVerified() {
+			return nil
+		}
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to mark email as verified: %w", err), "Internal error during email verification.")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := newUserResponse(user)
+	return &resp, nil
+}
+
+// ResendVerificationEmail sends a new verification link to each unverified account with the
+// email. Like ForgotPassword, it does not reveal whether an account exists.
+func (s *AuthService) ResendVerificationEmail(ctx context.Context, req ResendVerificationRequest) error {
+	users, err := s.userRepo.FindAllByEmail(ctx, req.Email)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find users by email: %w", err), "Internal error during email verification.")
+	}
+	for i := range users {
+		if users[i].IsEmailVerified() {
+			continue
+		}
+		if err := s.sendVerificationEmail(ctx, &users[i]); err != nil {
+			log.Errorf(ctx, "Auth: Failed to send verification email to user %s: %v", users[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// sendVerificationEmail replaces any outstanding verification token of the user and emails a new link.
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *domain.User) error {
+	if err := s.actionTokenRepo.InvalidateForUser(ctx, user.ID, domain.ActionTokenEmailVerification); err != nil {
+		return fmt.Errorf("failed to invalidate verification tokens: %w", err)
+	}
+	token, err := s.createActionToken(ctx, user.ID, domain.ActionTokenEmailVerification, s.cfg.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %s.\n\n%s/verify-email?token=%s\n",
+			user.Name, s.cfg.EmailVerificationTTL, s.cfg.FrontendURL, token),
+	})
+}
+
+// createActionToken stores a new single-use token and returns the raw token to send to the user.
+func (s *AuthService) createActionToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	raw, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", globalErrors.NewInternalServerError(err, "Internal error generating token.")
+	}
+	token := &domain.ActionToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+	}
+	if err := s.actionTokenRepo.Save(ctx, token); err != nil {
+		return "", globalErrors.NewInternalServerError(fmt.Errorf("failed to save %s token: %w", purpose, err), "Internal error generating token.")
+	}
+	return raw, nil
+}
+
+// VerifyMFA completes a login of a user with MFA enabled. It exchanges the challenge token from
+// LoginUser and a TOTP or recovery code for the normal token pair. A challenge token can only
+// be used once.
+func (s *AuthService) VerifyMFA(ctx context.Context, req MFAVerifyRequest, clientIP string) (*AuthResponse, error) {
+	claims, err := utils.ValidateToken(req.MFAToken)
+	if err != nil || claims.TokenType != utils.TokenTypeMFAChallenge {
+		return nil, ErrInvalidMFAToken
+	}
+	revoked, err := s.denylist.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to check MFA challenge token: %w", err), "Internal error during MFA verification.")
+	}
+	if revoked {
+		return nil, ErrInvalidMFAToken
+	}
+
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		return nil, globalErrors.NewBadRequest("Invalid user ID in refresh token claims", nil)
+		return nil, ErrInvalidMFAToken
 	}
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find user for refresh token: %w", err), "Internal error during token refresh.")
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find user for MFA challenge: %w", err), "Internal error during MFA verification.")
+	}
+	if user == nil {
+		return nil, ErrInvalidMFAToken
+	}
+	mfa, err := s.findEnabledMFA(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyMFACode(ctx, user, mfa, req.Code, clientIP, true); err != nil {
+		return nil, err
+	}
+	if err := s.denylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to revoke MFA challenge token: %w", err), "Internal error during MFA verification.")
+	}
+
+	// Challenges issued before tenant switching existed carry no tenant.
+	tenantID := user.TenantID
+	if parsedTenantID, err := uuid.Parse(claims.TenantID); err == nil {
+		tenantID = parsedTenantID
+	}
+	resp, _, err := s.issueTokens(ctx, user, tenantID, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// EnrollMFA starts a TOTP enrollment for the user. MFA is not enforced until the enrollment is
+// confirmed with ConfirmMFA; enrolling again before that replaces the secret.
+func (s *AuthService) EnrollMFA(ctx context.Context, session SessionInfo) (*MFAEnrollResponse, error) {
+	user, err := s.findSessionUser(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.mfaRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find MFA enrollment: %w", err), "Internal error during MFA enrollment.")
+	}
+	if existing != nil && existing.IsEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(err, "Internal error during MFA enrollment.")
+	}
+	mfa := &domain.UserMFA{
+		UserID:     user.ID,
+		TOTPSecret: secret,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.mfaRepo.SavePending(ctx, mfa); err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to save MFA enrollment: %w", err), "Internal error during MFA enrollment.")
+	}
+
+	return &MFAEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.cfg.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA enables MFA after the user proved with a first code that the authenticator app was
+// set up correctly. It returns the recovery codes, which are only shown this once.
+func (s *AuthService) ConfirmMFA(ctx context.Context, session SessionInfo, req MFACodeRequest, clientIP string) (*MFARecoveryCodesResponse, error) {
+	user, err := s.findSessionUser(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	mfa, err := s.mfaRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find MFA enrollment: %w", err), "Internal error during MFA enrollment.")
+	}
+	if mfa == nil {
+		return nil, ErrMFANotEnrolled
+	}
+	if mfa.IsEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	if err := s.verifyMFACode(ctx, user, mfa, req.Code, clientIP, false); err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.Enable(ctx, user.ID); err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to enable MFA: %w", err), "Internal error during MFA enrollment.")
+	}
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof(ctx, "Auth: MFA enabled for user %s", user.ID)
+	return &MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes. It requires a current TOTP code.
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, session SessionInfo, req MFACodeRequest, clientIP string) (*MFARecoveryCodesResponse, error) {
+	user, err := s.findSessionUser(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	mfa, err := s.findEnabledMFA(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyMFACode(ctx, user, mfa, req.Code, clientIP, false); err != nil {
+		return nil, err
+	}
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableMFA turns MFA off for the user after checking a TOTP or recovery code.
+func (s *AuthService) DisableMFA(ctx context.Context, session SessionInfo, req MFACodeRequest, clientIP string) error {
+	user, err := s.findSessionUser(ctx, session)
+	if err != nil {
+		return err
+	}
+	mfa, err := s.findEnabledMFA(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.verifyMFACode(ctx, user, mfa, req.Code, clientIP, true); err != nil {
+		return err
+	}
+	if err := s.mfaRepo.Delete(ctx, user.ID); err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to delete MFA enrollment: %w", err), "Internal error disabling MFA.")
+	}
+	log.Infof(ctx, "Auth: MFA disabled for user %s", user.ID)
+	return nil
+}
+
+// findSessionUser loads the user the access token of the current request belongs to.
+func (s *AuthService) findSessionUser(ctx context.Context, session SessionInfo) (*domain.User, error) {
+	userID, err := uuid.Parse(session.UserID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find user: %w", err), "Internal error finding user.")
 	}
 	if user == nil {
 		return nil, ErrInvalidToken
 	}
+	return user, nil
+}
+
+// findEnabledMFA returns the user's confirmed MFA enrollment, or ErrMFANotEnabled.
+func (s *AuthService) findEnabledMFA(ctx context.Context, userID uuid.UUID) (*domain.UserMFA, error) {
+	mfa, err := s.mfaRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find MFA enrollment: %w", err), "Internal error during MFA verification.")
+	}
+	if mfa == nil || !mfa.IsEnabled() {
+		return nil, ErrMFANotEnabled
+	}
+	return mfa, nil
+}
+
+// verifyMFACode checks a code of the user like checkMFACode. Wrong codes count as failed logins of
+// the user's email and the client IP, so the 6-digit code can't be brute-forced, whether at login
+// or with a hijacked session; a correct code clears the account's failures.
+func (s *AuthService) verifyMFACode(ctx context.Context, user *domain.User, mfa *domain.UserMFA, code, clientIP string, allowRecovery bool) error {
+	if err := s.checkLoginThrottle(ctx, user.Email, clientIP); err != nil {
+		return err
+	}
+	if err := s.checkMFACode(ctx, mfa, code, allowRecovery); err != nil {
+		if err == ErrInvalidMFACode {
+			return s.recordLoginFailure(ctx, user.Email, clientIP, err)
+		}
+		return err
+	}
+	return s.resetLoginThrottle(ctx, user.Email)
+}
+
+// checkMFACode accepts a TOTP code whose time step was not used before and, when allowRecovery is
+// set, an unused recovery code. Both are single-use.
+func (s *AuthService) checkMFACode(ctx context.Context, mfa *domain.UserMFA, code string, allowRecovery bool) error {
+	if step, ok := totp.Validate(mfa.TOTPSecret, code, time.Now()); ok {
+		fresh, err := s.mfaRepo.UseStep(ctx, mfa.UserID, step)
+		if err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to record TOTP step: %w", err), "Internal error during MFA verification.")
+		}
+		if !fresh {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	if allowRecovery {
+		used, err := s.mfaRepo.UseRecoveryCode(ctx, mfa.UserID, utils.HashToken(normalizeRecoveryCode(code)))
+		if err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to use recovery code: %w", err), "Internal error during MFA verification.")
+		}
+		if used {
+			log.Infof(ctx, "Auth: Recovery code used by user %s", mfa.UserID)
+			return nil
+		}
+	}
+	return ErrInvalidMFACode
+}
+
+// replaceRecoveryCodes generates a new set of recovery codes, stores their hashes and returns
+// the codes in plain text. The codes carry 80 random bits, which keeps a leaked SHA-256 hash out of
+// reach of brute force without a slow hash.
+func (s *AuthService) replaceRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to generate recovery code: %w", err), "Internal error generating recovery codes.")
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		codes[i] = raw[:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:]
+		hashes[i] = utils.HashToken(raw)
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to save recovery codes: %w", err), "Internal error generating recovery codes.")
+	}
+	return codes, nil
+}
+
+// recoveryCodeCount is the number of recovery codes handed out per set.
+const recoveryCodeCount = 10
+
+// recoveryCodeEncoding writes recovery codes in base32, 16 characters for 10 bytes.
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// normalizeRecoveryCode strips the separator and spacing users may type with a recovery code.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// Logout ends the session the access token belongs to. The access token is denied right away and
+// the refresh tokens of its session are revoked. A refresh token passed in the request is
+// revoked too, which covers tokens issued before the session was linked to an access token.
+func (s *AuthService) Logout(ctx context.Context, session SessionInfo, req LogoutRequest) error {
+	if err := s.denylist.Revoke(ctx, session.TokenID, session.ExpiresAt); err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to revoke access token: %w", err), "Internal error during logout.")
+	}
+
+	if accessTokenID, err := uuid.Parse(session.TokenID); err == nil {
+		stored, err := s.refreshTokenRepo.FindByAccessTokenID(ctx, accessTokenID)
+		if err != nil {
+			return globalErrors.NewInternalServerError(fmt.Errorf("failed to find session for access token: %w", err), "Internal error during logout.")
+		}
+		if stored != nil {
+			if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+				return globalErrors.NewInternalServerError(fmt.Errorf("failed to revoke session: %w", err), "Internal error during logout.")
+			}
+		}
+	}
+
+	if req.RefreshToken == "" {
+		return nil
+	}
+	claims, err := utils.ValidateToken(req.RefreshToken)
+	if err != nil || claims.UserID != session.UserID {
+		return ErrInvalidToken
+	}
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	stored, err := s.refreshTokenRepo.FindByID(ctx, tokenID)
+	if err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to find refresh token: %w", err), "Internal error during logout.")
+	}
+	if stored == nil {
+		return ErrInvalidToken
+	}
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to revoke refresh token family: %w", err), "Internal error during logout.")
+	}
+	return nil
+}
+
+// LogoutAll ends every session of the user, including the one making the request.
+func (s *AuthService) LogoutAll(ctx context.Context, session SessionInfo) error {
+	userID, err := uuid.Parse(session.UserID)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	if err := s.denylist.Revoke(ctx, session.TokenID, session.ExpiresAt); err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to revoke access token: %w", err), "Internal error during logout.")
+	}
+	if err := s.RevokeAllSessions(ctx, userID); err != nil {
+		return globalErrors.NewInternalServerError(err, "Internal error during logout.")
+	}
+	return nil
+}
+
+// RevokeAllSessions revokes every refresh token of the user and denies the access tokens that
+// were issued with them and have not expired yet.
+func (s *AuthService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	live, err := s.refreshTokenRepo.FindWithLiveAccessToken(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list live access tokens: %w", err)
+	}
+	for _, token := range live {
+		if err := s.denylist.Revoke(ctx, token.AccessTokenID.UUID.String(), *token.AccessExpiresAt); err != nil {
+			return fmt.Errorf("failed to revoke access token: %w", err)
+		}
+	}
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// RevokeOtherSessions ends every session of the user except the one making the request, e.g.
+// after a password change. The current session is the token family of the presented access token.
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, session SessionInfo) error {
+	userID, err := uuid.Parse(session.UserID)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	// Access tokens issued before sessions were linked to them have no family to keep, so only
+	// the presented token survives.
+	var currentFamilyID uuid.UUID
+	if accessTokenID, err := uuid.Parse(session.TokenID); err == nil {
+		current, err := s.refreshTokenRepo.FindByAccessTokenID(ctx, accessTokenID)
+		if err != nil {
+			return fmt.Errorf("failed to find session for access token: %w", err)
+		}
+		if current != nil {
+			currentFamilyID = current.FamilyID
+		}
+	}
+
+	live, err := s.refreshTokenRepo.FindWithLiveAccessToken(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list live access tokens: %w", err)
+	}
+	for _, token := range live {
+		if token.FamilyID == currentFamilyID || token.AccessTokenID.UUID.String() == session.TokenID {
+			continue
+		}
+		if err := s.denylist.Revoke(ctx, token.AccessTokenID.UUID.String(), *token.AccessExpiresAt); err != nil {
+			return fmt.Errorf("failed to revoke access token: %w", err)
+		}
+	}
+	if err := s.refreshTokenRepo.RevokeAllForUserExceptFamily(ctx, userID, currentFamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// RevokeTenantSessions revokes the user's sessions in the tenant and denies the access tokens
+// issued with them, e.g. when the user is removed from the tenant. Sessions in other tenants are
+// left alone.
+func (s *AuthService) RevokeTenantSessions(ctx context.Context, user *domain.User, tenantID uuid.UUID) error {
+	live, err := s.refreshTokenRepo.FindWithLiveAccessToken(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list live access tokens: %w", err)
+	}
+	for _, token := range live {
+		sessionTenantID := user.TenantID
+		if token.TenantID.Valid {
+			sessionTenantID = token.TenantID.UUID
+		}
+		if sessionTenantID != tenantID {
+			continue
+		}
+		if err := s.denylist.Revoke(ctx, token.AccessTokenID.UUID.String(), *token.AccessExpiresAt); err != nil {
+			return fmt.Errorf("failed to revoke access token: %w", err)
+		}
+	}
+	if err := s.refreshTokenRepo.RevokeAllForUserInTenant(ctx, user.ID, tenantID, user.TenantID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// GetUserTenants lists the tenants the user is an active member of and marks the tenant of the
+// current session.
+func (s *AuthService) GetUserTenants(ctx context.Context, session SessionInfo) ([]UserTenantResponse, error) {
+	userID, err := uuid.Parse(session.UserID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	memberships, err := s.membershipRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find tenant memberships: %w", err), "Internal error fetching tenants.")
+	}
+
+	resp := make([]UserTenantResponse, 0, len(memberships))
+	for _, membership := range memberships {
+		if !membership.IsActive() {
+			continue
+		}
+		tenant, err := s.tenantRepo.FindByID(ctx, membership.TenantID)
+		if err != nil {
+			return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find tenant: %w", err), "Internal error fetching tenants.")
+		}
+		if tenant == nil {
+			continue
+		}
+		resp = append(resp, UserTenantResponse{
+			ID:      tenant.ID.String(),
+			Slug:    tenant.Slug,
+			Name:    tenant.Name,
+			Status:  tenant.Status,
+			Role:    membership.Role,
+			Current: tenant.ID.String() == session.TenantID,
+		})
+	}
+	return resp, nil
+}
 
-	newAccessToken, err := utils.GenerateAccessToken(user.ID.String(), user.TenantID.String(), user.Role)
+// SwitchTenant issues a token pair for another tenant the user is a member of and ends the
+// current session, so the client only holds tokens for one tenant at a time.
+func (s *AuthService) SwitchTenant(ctx context.Context, session SessionInfo, req SwitchTenantRequest) (*AuthResponse, error) {
+	user, err := s.findSessionUser(ctx, session)
 	if err != nil {
-		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to generate new access token: %w", err), "Internal error generating token.")
+		return nil, err
 	}
-	newRefreshToken, err := utils.GenerateRefreshToken(user.ID.String())
+	tenantID, err := uuid.Parse(req.TenantID)
 	if err != nil {
-		return nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to generate new refresh token: %w", err), "Internal error generating token.")
+		return nil, globalErrors.NewBadRequest("Invalid tenant ID format (must be UUID)", nil)
 	}
 
+	resp, _, err := s.issueTokens(ctx, user, tenantID, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Logout(ctx, session, LogoutRequest{}); err != nil {
+		return nil, err
+	}
+	log.Infof(ctx, "Auth: User %s switched from tenant %s to %s", user.ID, session.TenantID, tenantID)
+	return resp, nil
+}
+
+// handleRefreshTokenReuse revokes the family of a refresh token that was presented after it had
+// already been rotated. Either the client or an attacker holds a stale copy, so all sessions
+// derived from the same login are ended.
+func (s *AuthService) handleRefreshTokenReuse(ctx context.Context, token *domain.RefreshToken) error {
+	log.Warnf(ctx, "Auth: Refresh token reuse detected for user %s (family %s), revoking family", token.UserID, token.FamilyID)
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+		return globalErrors.NewInternalServerError(fmt.Errorf("failed to revoke refresh token family: %w", err), "Internal error during token refresh.")
+	}
+	return ErrRefreshTokenReused
+}
+
+// issueTokens generates an access/refresh token pair for the user in the tenant and records the
+// refresh token as a member of the given token family. The access token carries the tenant and
+// the user's role there. Only enabled accounts that are active members of an active tenant get
+// tokens, which also makes refreshing fail once the account is disabled.
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, tenantID, familyID uuid.UUID) (*AuthResponse, *domain.RefreshToken, error) {
+	if user.IsDisabled() {
+		return nil, nil, ErrAccountDisabled
+	}
+	if _, err := s.findActiveTenant(ctx, tenantID); err != nil {
+		return nil, nil, err
+	}
+	membership, err := s.membershipRepo.Find(ctx, user.ID, tenantID)
+	if err != nil {
+		return nil, nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to find tenant membership: %w", err), "Internal error generating token.")
+	}
+	if membership == nil {
+		return nil, nil, ErrNotTenantMember
+	}
+	if !membership.IsActive() {
+		return nil, nil, ErrMembershipDeactivated
+	}
+	accessToken, accessClaims, err := utils.GenerateAccessToken(user.ID.String(), tenantID.String(), membership.Role)
+	if err != nil {
+		return nil, nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to generate access token: %w", err), "Internal error generating token.")
+	}
+	refreshToken, refreshClaims, err := utils.GenerateRefreshToken(user.ID.String())
+	if err != nil {
+		return nil, nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to generate refresh token: %w", err), "Internal error generating token.")
+	}
+
+	stored := &domain.RefreshToken{
+		ID:              uuid.MustParse(refreshClaims.ID),
+		FamilyID:        familyID,
+		UserID:          user.ID,
+		TenantID:        uuid.NullUUID{UUID: tenantID, Valid: true},
+		ExpiresAt:       refreshClaims.ExpiresAt.Time,
+		AccessTokenID:   uuid.NullUUID{UUID: uuid.MustParse(accessClaims.ID), Valid: true},
+		AccessExpiresAt: &accessClaims.ExpiresAt.Time,
+		CreatedAt:       time.Now(),
+	}
+	if err := s.refreshTokenRepo.Save(ctx, stored); err != nil {
+		return nil, nil, globalErrors.NewInternalServerError(fmt.Errorf("failed to save refresh token: %w", err), "Internal error generating token.")
+	}
+
+	userResp := newUserResponse(user)
+	userResp.TenantID = tenantID.String()
+	userResp.Role = membership.Role
 	return &AuthResponse{
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
-		User: UserResponse{
-			ID:          user.ID.String(),
-			TenantID:    user.TenantID.String(),
-			Email:       user.Email,
-			Name:        user.Name,
-			PhoneNumber: user.PhoneNumber,
-			Role:        user.Role,
-			CreatedAt:   user.CreatedAt.Format(utils.ISO8601TimeFormat),
-		},
-	}, nil
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		User:         &userResp,
+	}, stored, nil
+}
+
+func newUserResponse(user *domain.User) UserResponse {
+	return UserResponse{
+		ID:            user.ID.String(),
+		TenantID:      user.TenantID.String(),
+		Email:         user.Email,
+		Name:          user.Name,
+		PhoneNumber:   user.PhoneNumber,
+		Role:          user.Role,
+		EmailVerified: user.IsEmailVerified(),
+		CreatedAt:     user.CreatedAt.Format(utils.ISO8601TimeFormat),
+	}
 }
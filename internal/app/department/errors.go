@@ -0,0 +1,16 @@
+package department
+
+import (
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/utils/errors"
+)
+
+// Module-specific custom errors for the Department domain.
+var (
+	ErrDepartmentNotFound      = errors.New("DEPARTMENT_NOT_FOUND", "Department with given ID not found", http.StatusNotFound, nil, nil)
+	ErrDepartmentAlreadyExists = errors.New("DEPARTMENT_ALREADY_EXISTS", "Department with the given name already exists", http.StatusConflict, nil, nil)
+	ErrParentNotFound          = errors.New("DEPARTMENT_PARENT_NOT_FOUND", "Parent must be a department of the tenant", http.StatusBadRequest, nil, nil)
+	ErrDepartmentCycle         = errors.New("DEPARTMENT_CYCLE", "A department can't be placed under itself or one of its subdepartments", http.StatusBadRequest, nil, nil)
+	ErrDepartmentHasChildren   = errors.New("DEPARTMENT_HAS_CHILDREN", "Move or delete the subdepartments of this department first", http.StatusConflict, nil, nil)
+)
@@ -0,0 +1,160 @@
+package department
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/http/middleware"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type DepartmentHandler struct {
+	service   *DepartmentService
+	validator *validator.Validate
+}
+
+// NewDepartmentHandler creates a new instance of DepartmentHandler.
+func NewDepartmentHandler(s *DepartmentService, v *validator.Validate) *DepartmentHandler {
+	return &DepartmentHandler{service: s, validator: v}
+}
+
+// RegisterRoutes registers the department routes on the authenticated router. Departments are
+// part of the employee records, so they use the employees:read and employees:write permissions.
+func (h *DepartmentHandler) RegisterRoutes(router *mux.Router) {
+	read := middleware.RequirePermission(domain.PermissionEmployeesRead)
+	write := middleware.RequirePermission(domain.PermissionEmployeesWrite)
+	router.Handle("/departments", write(http.HandlerFunc(h.CreateDepartment))).Methods("POST")
+	router.Handle("/departments", read(http.HandlerFunc(h.GetDepartments))).Methods("GET")
+	router.Handle("/departments/tree", read(http.HandlerFunc(h.GetDepartmentTree))).Methods("GET")
+	router.Handle("/departments/{id}", read(http.HandlerFunc(h.GetDepartmentByID))).Methods("GET")
+	router.Handle("/departments/{id}", write(http.HandlerFunc(h.UpdateDepartment))).Methods("PUT")
+	router.Handle("/departments/{id}", write(http.HandlerFunc(h.DeleteDepartment))).Methods("DELETE")
+}
+
+// CreateDepartment handles the request to create a new department.
+func (h *DepartmentHandler) CreateDepartment(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	var req CreateDepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	department, err := h.service.CreateDepartment(r.Context(), tenantID, req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, department)
+}
+
+// GetDepartments handles the request to list the tenant's departments.
+func (h *DepartmentHandler) GetDepartments(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	departments, err := h.service.GetDepartments(r.Context(), tenantID)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, departments)
+}
+
+// GetDepartmentTree handles the request to retrieve the tenant's department hierarchy.
+func (h *DepartmentHandler) GetDepartmentTree(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	tree, err := h.service.GetDepartmentTree(r.Context(), tenantID)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, tree)
+}
+
+// GetDepartmentByID handles the request to retrieve a department by ID.
+func (h *DepartmentHandler) GetDepartmentByID(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	department, err := h.service.GetDepartmentByID(r.Context(), tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, department)
+}
+
+// UpdateDepartment handles the request to rename or move a department.
+func (h *DepartmentHandler) UpdateDepartment(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	var req UpdateDepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	department, err := h.service.UpdateDepartment(r.Context(), tenantID, mux.Vars(r)["id"], req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, department)
+}
+
+// DeleteDepartment handles the request to delete a department.
+func (h *DepartmentHandler) DeleteDepartment(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	if err := h.service.DeleteDepartment(r.Context(), tenantID, mux.Vars(r)["id"]); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
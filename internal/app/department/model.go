@@ -0,0 +1,30 @@
+package department
+
+// CreateDepartmentRequest is the DTO for creating a new department. Without a parent, the
+// department is a top-level one.
+type CreateDepartmentRequest struct {
+	Name     string  `json:"name" validate:"required,max=255"`
+	ParentID *string `json:"parent_id" validate:"omitempty,uuid"`
+}
+
+// UpdateDepartmentRequest is the DTO for replacing a department's name and parent. Omitting the
+// parent moves the department to the top level.
+type UpdateDepartmentRequest struct {
+	Name     string  `json:"name" validate:"required,max=255"`
+	ParentID *string `json:"parent_id" validate:"omitempty,uuid"`
+}
+
+// DepartmentResponse is the DTO for responding with department details.
+type DepartmentResponse struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	ParentID  *string `json:"parent_id"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+// DepartmentNode is a department in the department tree, with its subdepartments.
+type DepartmentNode struct {
+	DepartmentResponse
+	Children []DepartmentNode `json:"children"`
+}
@@ -0,0 +1,256 @@
+package department
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/google/uuid"
+)
+
+// DepartmentService manages the department hierarchy of a tenant. Employees are assigned to
+// departments through the employee module.
+type DepartmentService struct {
+	departmentRepo domain.DepartmentRepository
+	transactor     domain.Transactor
+}
+
+// NewDepartmentService creates a new instance of DepartmentService.
+func NewDepartmentService(departmentRepo domain.DepartmentRepository, transactor domain.Transactor) *DepartmentService {
+	return &DepartmentService{departmentRepo: departmentRepo, transactor: transactor}
+}
+
+// CreateDepartment creates a department, optionally under a parent department.
+func (s *DepartmentService) CreateDepartment(ctx context.Context, tenantID string, req CreateDepartmentRequest) (*DepartmentResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+
+	department := &domain.Department{
+		TenantID: parsedTenantID,
+		Name:     req.Name,
+	}
+	department.GenerateID()
+	if err := s.checkName(ctx, department); err != nil {
+		return nil, err
+	}
+	if err := s.setParent(ctx, department, req.ParentID); err != nil {
+		return nil, err
+	}
+
+	if err := s.departmentRepo.Save(ctx, department); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to save department: %w", err), "Internal error saving department.")
+	}
+	resp := newDepartmentResponse(department)
+	return &resp, nil
+}
+
+// GetDepartments lists the tenant's departments by name.
+func (s *DepartmentService) GetDepartments(ctx context.Context, tenantID string) ([]DepartmentResponse, error) {
+	departments, err := s.findAll(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]DepartmentResponse, len(departments))
+	for i, department := range departments {
+		resp[i] = newDepartmentResponse(department)
+	}
+	return resp, nil
+}
+
+// GetDepartmentTree returns the tenant's top-level departments, each with its subdepartments.
+func (s *DepartmentService) GetDepartmentTree(ctx context.Context, tenantID string) ([]DepartmentNode, error) {
+	departments, err := s.findAll(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []*domain.Department
+	children := make(map[uuid.UUID][]*domain.Department)
+	for _, department := range departments {
+		if department.ParentID == nil {
+			roots = append(roots, department)
+		} else {
+			children[*department.ParentID] = append(children[*department.ParentID], department)
+		}
+	}
+	return buildTree(roots, children), nil
+}
+
+// GetDepartmentByID retrieves a single department by ID.
+func (s *DepartmentService) GetDepartmentByID(ctx context.Context, tenantID, departmentID string) (*DepartmentResponse, error) {
+	department, err := s.findDepartment(ctx, tenantID, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	resp := newDepartmentResponse(department)
+	return &resp, nil
+}
+
+// UpdateDepartment renames a department and moves it under another parent. A department can't be
+// moved under itself or one of its subdepartments.
+func (s *DepartmentService) UpdateDepartment(ctx context.Context, tenantID, departmentID string, req UpdateDepartmentRequest) (*DepartmentResponse, error) {
+	department, err := s.findDepartment(ctx, tenantID, departmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != department.Name {
+		department.Name = req.Name
+		if err := s.checkName(ctx, department); err != nil {
+			return nil, err
+		}
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The new parent is checked for cycles and stored under the tenant's hierarchy lock, so two
+		// concurrent moves can't form a cycle together.
+		if req.ParentID != nil && *req.ParentID != "" {
+			if err := s.departmentRepo.LockHierarchy(ctx, department.TenantID); err != nil {
+				return errors.NewInternalServerError(fmt.Errorf("failed to lock department hierarchy: %w", err), "Internal error updating department.")
+			}
+		}
+		if err := s.setParent(ctx, department, req.ParentID); err != nil {
+			return err
+		}
+		department.UpdateTimestamp()
+
+		if err := s.departmentRepo.Update(ctx, department); err != nil {
+			return errors.NewInternalServerError(fmt.Errorf("failed to update department: %w", err), "Internal error updating department.")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := newDepartmentResponse(department)
+	return &resp, nil
+}
+
+// DeleteDepartment deletes a department without subdepartments. Its employees are left without a
+// department. The database refuses to delete a department that still has subdepartments.
+func (s *DepartmentService) DeleteDepartment(ctx context.Context, tenantID, departmentID string) error {
+	department, err := s.findDepartment(ctx, tenantID, departmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.departmentRepo.Delete(ctx, department.TenantID, department.ID); err != nil {
+		if stderrors.Is(err, domain.ErrDepartmentHasChildren) {
+			return ErrDepartmentHasChildren
+		}
+		return errors.NewInternalServerError(fmt.Errorf("failed to delete department: %w", err), "Internal error deleting department.")
+	}
+	return nil
+}
+
+func (s *DepartmentService) findAll(ctx context.Context, tenantID string) ([]*domain.Department, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	departments, err := s.departmentRepo.FindAll(ctx, parsedTenantID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to fetch departments: %w", err), "Internal error fetching departments.")
+	}
+	return departments, nil
+}
+
+func (s *DepartmentService) findDepartment(ctx context.Context, tenantID, departmentID string) (*domain.Department, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	parsedDepartmentID, err := uuid.Parse(departmentID)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid department ID format (must be UUID)", nil)
+	}
+
+	department, err := s.departmentRepo.FindByID(ctx, parsedTenantID, parsedDepartmentID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to get department: %w", err), "Internal error fetching department.")
+	}
+	if department == nil {
+		return nil, ErrDepartmentNotFound
+	}
+	return department, nil
+}
+
+// checkName makes sure no other department of the tenant has the department's name, in any case.
+func (s *DepartmentService) checkName(ctx context.Context, department *domain.Department) error {
+	existing, err := s.departmentRepo.FindByName(ctx, department.TenantID, department.Name)
+	if err != nil {
+		return errors.NewInternalServerError(fmt.Errorf("failed to check existing department: %w", err), "Internal error checking department name.")
+	}
+	if existing != nil && existing.ID != department.ID {
+		return ErrDepartmentAlreadyExists
+	}
+	return nil
+}
+
+// setParent places the department under the parent, or at the top level when parentID is empty.
+// Walking up from the parent must not reach the department itself.
+func (s *DepartmentService) setParent(ctx context.Context, department *domain.Department, parentID *string) error {
+	department.ParentID = nil
+	if parentID == nil || *parentID == "" {
+		return nil
+	}
+	parsedParentID, err := uuid.Parse(*parentID)
+	if err != nil {
+		return errors.NewBadRequest("Invalid parent_id format (must be UUID)", nil)
+	}
+
+	departments, err := s.departmentRepo.FindAll(ctx, department.TenantID)
+	if err != nil {
+		return errors.NewInternalServerError(fmt.Errorf("failed to fetch departments: %w", err), "Internal error checking department parent.")
+	}
+	byID := make(map[uuid.UUID]*domain.Department, len(departments))
+	for _, other := range departments {
+		byID[other.ID] = other
+	}
+	if byID[parsedParentID] == nil {
+		return ErrParentNotFound
+	}
+	// The walk is bounded by the number of departments in case the stored data has a cycle.
+	for id, steps := &parsedParentID, 0; id != nil && steps <= len(departments); steps++ {
+		if *id == department.ID {
+			return ErrDepartmentCycle
+		}
+		ancestor := byID[*id]
+		if ancestor == nil {
+			break
+		}
+		id = ancestor.ParentID
+	}
+	department.ParentID = &parsedParentID
+	return nil
+}
+
+func buildTree(departments []*domain.Department, children map[uuid.UUID][]*domain.Department) []DepartmentNode {
+	nodes := make([]DepartmentNode, len(departments))
+	for i, department := range departments {
+		nodes[i] = DepartmentNode{
+			DepartmentResponse: newDepartmentResponse(department),
+			Children:           buildTree(children[department.ID], children),
+		}
+	}
+	return nodes
+}
+
+func newDepartmentResponse(department *domain.Department) DepartmentResponse {
+	resp := DepartmentResponse{
+		ID:        department.ID.String(),
+		Name:      department.Name,
+		CreatedAt: department.CreatedAt.Format(utils.ISO8601TimeFormat),
+		UpdatedAt: department.UpdatedAt.Format(utils.ISO8601TimeFormat),
+	}
+	if department.ParentID != nil {
+		parentID := department.ParentID.String()
+		resp.ParentID = &parentID
+	}
+	return resp
+}
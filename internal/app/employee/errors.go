@@ -10,5 +10,11 @@ import (
 var (
 	ErrEmployeeNotFound      = errors.New("EMPLOYEE_NOT_FOUND", "Employee with given ID not found", http.StatusNotFound, nil, nil)
 	ErrEmployeeAlreadyExists = errors.New("EMPLOYEE_ALREADY_EXISTS", "Employee with the given email or phone number already exists", http.StatusConflict, nil, nil)
+	ErrManagerNotFound       = errors.New("EMPLOYEE_MANAGER_NOT_FOUND", "Manager must be an employee of the tenant", http.StatusBadRequest, nil, nil)
+	ErrSelfManager           = errors.New("EMPLOYEE_SELF_MANAGER", "An employee can't be their own manager", http.StatusBadRequest, nil, nil)
+	ErrManagerCycle          = errors.New("EMPLOYEE_MANAGER_CYCLE", "The manager reports to the employee, directly or indirectly", http.StatusBadRequest, nil, nil)
+	ErrDepartmentNotFound    = errors.New("EMPLOYEE_DEPARTMENT_NOT_FOUND", "Department must be a department of the tenant", http.StatusBadRequest, nil, nil)
+	ErrEmployeeModified      = errors.New("EMPLOYEE_MODIFIED", "Employee was changed by another request, fetch it again and retry", http.StatusPreconditionFailed, nil, nil)
+	ErrUnsupportedPatch      = errors.New("UNSUPPORTED_MEDIA_TYPE", "PATCH requests must be sent as application/merge-patch+json", http.StatusUnsupportedMediaType, nil, nil)
 	// Add other specific errors related to employee operations
 )
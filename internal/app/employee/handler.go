@@ -2,10 +2,18 @@ package employee
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
+	"reflect"
 	"strconv"
+	"strings"
 
+	"starterpack-golang-cleanarch/internal/domain"
 	"starterpack-golang-cleanarch/internal/platform/http/middleware"
+	"starterpack-golang-cleanarch/internal/platform/spreadsheet"
 	"starterpack-golang-cleanarch/internal/utils"
 	"starterpack-golang-cleanarch/internal/utils/errors"
 
@@ -13,6 +21,16 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// Limits of employee import files.
+const (
+	maxImportFileSize = 10 << 20 // 10 MB
+	maxImportRows     = 5000
+)
+
+// importColumns are the columns an import file may have: the fields of CreateEmployeeRequest, plus
+// department (a department name) and manager_email.
+var importColumns = []string{"name", "email", "phone_number", "status", "job_title", "department_id", "department", "hire_date", "manager_id", "manager_email"}
+
 type EmployeeHandler struct {
 	service   *EmployeeService
 	validator *validator.Validate
@@ -23,11 +41,19 @@ func NewEmployeeHandler(s *EmployeeService, v *validator.Validate) *EmployeeHand
 	return &EmployeeHandler{service: s, validator: v}
 }
 
-// RegisterRoutes registers Employee-related API routes to the router.
+// RegisterRoutes registers Employee-related API routes on the authenticated router. Reading
+// employees needs employees:read, changing them employees:write.
 func (h *EmployeeHandler) RegisterRoutes(router *mux.Router) {
-	router.HandleFunc("/employees", h.CreateEmployee).Methods("POST")
-	router.HandleFunc("/employees", h.GetEmployees).Methods("GET")
-	router.HandleFunc("/employees/{id}", h.GetEmployeeByID).Methods("GET") // Path ID is string for mux
+	read := middleware.RequirePermission(domain.PermissionEmployeesRead)
+	write := middleware.RequirePermission(domain.PermissionEmployeesWrite)
+	router.Handle("/employees", write(http.HandlerFunc(h.CreateEmployee))).Methods("POST")
+	router.Handle("/employees", read(http.HandlerFunc(h.GetEmployees))).Methods("GET")
+	router.Handle("/employees/import", write(http.HandlerFunc(h.ImportEmployees))).Methods("POST")
+	router.Handle("/employees/{id}", read(http.HandlerFunc(h.GetEmployeeByID))).Methods("GET")
+	router.Handle("/employees/{id}/org-chart", read(http.HandlerFunc(h.GetOrgChart))).Methods("GET")
+	router.Handle("/employees/{id}", write(http.HandlerFunc(h.UpdateEmployee))).Methods("PUT")
+	router.Handle("/employees/{id}", write(http.HandlerFunc(h.PatchEmployee))).Methods("PATCH")
+	router.Handle("/employees/{id}", write(http.HandlerFunc(h.DeleteEmployee))).Methods("DELETE")
 }
 
 // CreateEmployee handles the request to create a new employee.
@@ -55,7 +81,7 @@ func (h *EmployeeHandler) CreateEmployee(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	utils.RespondJSON(w, http.StatusCreated, employee)
+	respondEmployee(w, http.StatusCreated, employee)
 }
 
 // GetEmployees handles the request to retrieve a list of employees with pagination.
@@ -73,6 +99,11 @@ func (h *EmployeeHandler) GetEmployees(w http.ResponseWriter, r *http.Request) {
 	}
 	req.Query = r.URL.Query().Get("query")
 	req.Status = r.URL.Query().Get("status")
+	req.JobTitle = r.URL.Query().Get("job_title")
+	req.DepartmentID = r.URL.Query().Get("department_id")
+	req.ManagerID = r.URL.Query().Get("manager_id")
+	req.HiredFrom = r.URL.Query().Get("hired_from")
+	req.HiredTo = r.URL.Query().Get("hired_to")
 
 	if err := h.validator.Struct(req); err != nil {
 		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
@@ -97,23 +128,326 @@ func (h *EmployeeHandler) GetEmployees(w http.ResponseWriter, r *http.Request) {
 // GetEmployeeByID handles the request to retrieve an employee by ID.
 func (h *EmployeeHandler) GetEmployeeByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	employeeIDStr := vars["id"] // ID from path is always string
+	employeeIDStr := vars["id"]
+
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	employee, err := h.service.GetEmployeeByID(r.Context(), tenantID, employeeIDStr)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	respondEmployee(w, http.StatusOK, employee)
+}
+
+// GetOrgChart handles the request to retrieve an employee's managers and reports.
+func (h *EmployeeHandler) GetOrgChart(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	chart, err := h.service.GetOrgChart(r.Context(), tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, chart)
+}
+
+// UpdateEmployee handles the request to replace an employee's details.
+func (h *EmployeeHandler) UpdateEmployee(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	var req UpdateEmployeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	employee, err := h.service.UpdateEmployee(r.Context(), tenantID, mux.Vars(r)["id"], req, ifMatch(r))
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	respondEmployee(w, http.StatusOK, employee)
+}
+
+// PatchEmployee handles a JSON Merge Patch (RFC 7396) of an employee. The patch is applied to the
+// employee as returned by GET, and the result must be a valid UpdateEmployeeRequest.
+func (h *EmployeeHandler) PatchEmployee(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/merge-patch+json" {
+		utils.HandleHTTPError(w, ErrUnsupportedPatch, r)
+		return
+	}
+	patch, err := io.ReadAll(r.Body)
+	if err != nil || !json.Valid(patch) {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	employee, err := h.service.PatchEmployee(r.Context(), tenantID, mux.Vars(r)["id"], patch, ifMatch(r), h.validator.Struct)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	respondEmployee(w, http.StatusOK, employee)
+}
+
+// respondEmployee writes the employee with its version as the ETag, which clients send back in
+// If-Match to update it only if nobody else has changed it since.
+func respondEmployee(w http.ResponseWriter, status int, employee *EmployeeResponse) {
+	w.Header().Set("ETag", `"`+employee.Version+`"`)
+	utils.RespondJSON(w, status, employee)
+}
 
-	// No need to parse UUID here, as DB ID is SERIAL (int).
-	// The service layer will handle string to int64 conversion.
-	// Basic validation for numeric ID can be added here if needed.
+// ifMatch returns the version in the If-Match header, or an empty string when there is none or it
+// matches any version.
+func ifMatch(r *http.Request) string {
+	version := strings.TrimPrefix(strings.TrimSpace(r.Header.Get("If-Match")), "W/")
+	if version == "*" {
+		return ""
+	}
+	return strings.Trim(version, `"`)
+}
 
+// DeleteEmployee handles the request to delete an employee.
+func (h *EmployeeHandler) DeleteEmployee(w http.ResponseWriter, r *http.Request) {
 	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
 	if !ok || tenantID == "" {
 		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
 		return
 	}
 
-	employee, err := h.service.GetEmployeeByID(r.Context(), tenantID, employeeIDStr) // Pass string ID to service
+	if err := h.service.DeleteEmployee(r.Context(), tenantID, mux.Vars(r)["id"]); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ImportEmployees handles the upload of a CSV or XLSX file of employees to create. The file is
+// sent as multipart/form-data in the "file" field; with dry_run=true it is only checked.
+func (h *EmployeeHandler) ImportEmployees(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	dryRun := false
+	if dryRunStr := r.URL.Query().Get("dry_run"); dryRunStr != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(dryRunStr); err != nil {
+			utils.HandleHTTPError(w, errors.NewBadRequest("Invalid dry_run parameter (must be true or false)", nil), r)
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	if err := r.ParseMultipartForm(maxImportFileSize); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Upload the file as multipart/form-data in the 'file' field (at most 10 MB)", nil), r)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Upload the file as multipart/form-data in the 'file' field (at most 10 MB)", nil), r)
+		return
+	}
+	defer file.Close()
+
+	var table []spreadsheet.Row
+	xlsx := false
+	switch strings.ToLower(filepath.Ext(header.Filename)) {
+	case ".csv":
+		table, err = spreadsheet.ReadCSV(file)
+	case ".xlsx":
+		xlsx = true
+		table, err = spreadsheet.ReadXLSX(file, header.Size)
+	default:
+		utils.HandleHTTPError(w, errors.NewBadRequest("Unsupported file type (must be .csv or .xlsx)", nil), r)
+		return
+	}
+	if err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid import file: "+err.Error(), nil), r)
+		return
+	}
+
+	rows, err := h.readImportRows(table, xlsx)
 	if err != nil {
 		utils.HandleHTTPError(w, err, r)
 		return
 	}
 
-	utils.RespondJSON(w, http.StatusOK, employee)
+	response, err := h.service.ImportEmployees(r.Context(), tenantID, rows, dryRun)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, response)
+}
+
+// readImportRows maps the rows of an import file to ImportRows and validates them like
+// CreateEmployee requests. The first row names the columns; empty rows are skipped. Problems of a
+// row are kept on the row, while a file that can't be imported at all is rejected.
+func (h *EmployeeHandler) readImportRows(table []spreadsheet.Row, xlsx bool) ([]ImportRow, error) {
+	if len(table) == 0 {
+		return nil, errors.NewBadRequest("The import file is empty; its first row must name the columns", nil)
+	}
+
+	columns := make(map[string]int)
+	for i, cell := range table[0].Cells {
+		name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(cell)), " ", "_")
+		if name == "" {
+			continue
+		}
+		if !isImportColumn(name) {
+			return nil, errors.NewBadRequest(fmt.Sprintf("Unknown column %q (columns: %s)", cell, strings.Join(importColumns, ", ")), nil)
+		}
+		if _, duplicate := columns[name]; duplicate {
+			return nil, errors.NewBadRequest(fmt.Sprintf("Column %q appears more than once", name), nil)
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"name", "email", "phone_number"} {
+		if _, found := columns[required]; !found {
+			return nil, errors.NewBadRequest(fmt.Sprintf("Missing column %q", required), nil)
+		}
+	}
+
+	var rows []ImportRow
+	for _, tableRow := range table[1:] {
+		value := func(column string) string {
+			i, found := columns[column]
+			if !found || i >= len(tableRow.Cells) {
+				return ""
+			}
+			return strings.TrimSpace(tableRow.Cells[i])
+		}
+		optional := func(column string) *string {
+			if v := value(column); v != "" {
+				return &v
+			}
+			return nil
+		}
+		if strings.TrimSpace(strings.Join(tableRow.Cells, "")) == "" {
+			continue
+		}
+		if len(rows) == maxImportRows {
+			return nil, errors.NewBadRequest(fmt.Sprintf("The import file has more than %d rows; split it into several files", maxImportRows), nil)
+		}
+
+		row := ImportRow{
+			Line: tableRow.Line,
+			Employee: CreateEmployeeRequest{
+				Name:         value("name"),
+				Email:        value("email"),
+				PhoneNumber:  value("phone_number"),
+				Status:       value("status"),
+				JobTitle:     value("job_title"),
+				DepartmentID: optional("department_id"),
+				HireDate:     optional("hire_date"),
+				ManagerID:    optional("manager_id"),
+			},
+			Department:   value("department"),
+			ManagerEmail: value("manager_email"),
+		}
+		// Workbooks store dates as serial numbers.
+		if hireDate := row.Employee.HireDate; xlsx && hireDate != nil {
+			if serial, err := strconv.ParseFloat(*hireDate, 64); err == nil {
+				date := spreadsheet.ExcelDate(serial).Format(utils.ISO8601DateFormat)
+				row.Employee.HireDate = &date
+			}
+		}
+
+		if err := h.validator.Struct(row.Employee); err != nil {
+			row.Errors = validationMessages(err)
+			if fieldErrors, ok := err.(validator.ValidationErrors); ok {
+				for _, fe := range fieldErrors {
+					row.InvalidEmail = row.InvalidEmail || fe.StructField() == "Email"
+				}
+			}
+		}
+		if row.Department != "" && row.Employee.DepartmentID != nil {
+			row.Errors = append(row.Errors, "department and department_id can't both be set")
+		}
+		if row.ManagerEmail != "" {
+			if row.Employee.ManagerID != nil {
+				row.Errors = append(row.Errors, "manager_email and manager_id can't both be set")
+			}
+			if err := h.validator.Var(row.ManagerEmail, "email"); err != nil {
+				row.Errors = append(row.Errors, "manager_email must be a valid email address")
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func isImportColumn(name string) bool {
+	for _, column := range importColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validationMessages describes the failed rules of a CreateEmployeeRequest for people fixing an
+// import file, naming the fields like the file's columns.
+func validationMessages(err error) []string {
+	fieldErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return []string{err.Error()}
+	}
+	messages := make([]string, len(fieldErrors))
+	for i, fe := range fieldErrors {
+		field := fe.Field()
+		if structField, found := reflect.TypeOf(CreateEmployeeRequest{}).FieldByName(fe.StructField()); found {
+			field = strings.Split(structField.Tag.Get("json"), ",")[0]
+		}
+		switch fe.Tag() {
+		case "required":
+			messages[i] = field + " is required"
+		case "email":
+			messages[i] = field + " must be a valid email address"
+		case "max":
+			messages[i] = fmt.Sprintf("%s must be at most %s characters", field, fe.Param())
+		case "oneof":
+			messages[i] = fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+		case "datetime":
+			messages[i] = field + " must be a date (YYYY-MM-DD)"
+		case "uuid":
+			messages[i] = field + " must be a UUID"
+		default:
+			messages[i] = field + " is invalid"
+		}
+	}
+	return messages
 }
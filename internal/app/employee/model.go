@@ -2,32 +2,99 @@ package employee
 
 import "starterpack-golang-cleanarch/internal/utils"
 
-// CreateEmployeeRequest is the DTO for creating a new employee.
+// CreateEmployeeRequest is the DTO for creating a new employee. New employees are active unless
+// a status is given.
 type CreateEmployeeRequest struct {
-	Name        string `json:"name" validate:"required"`
-	Email       string `json:"email" validate:"required,email"`
-	PhoneNumber string `json:"phone_number" validate:"required"`
-	// Add other fields relevant for employee creation
+	Name         string  `json:"name" validate:"required,max=255"`
+	Email        string  `json:"email" validate:"required,email,max=255"`
+	PhoneNumber  string  `json:"phone_number" validate:"required,max=50"`
+	Status       string  `json:"status" validate:"omitempty,oneof=active on_leave terminated"`
+	JobTitle     string  `json:"job_title" validate:"max=255"`
+	DepartmentID *string `json:"department_id" validate:"omitempty,uuid"`
+	HireDate     *string `json:"hire_date" validate:"omitempty,datetime=2006-01-02"`
+	ManagerID    *string `json:"manager_id" validate:"omitempty,uuid"`
+}
+
+// UpdateEmployeeRequest is the DTO for replacing an employee's details; omitted optional fields
+// are cleared. PATCH requests are merged into the current employee and validated as this DTO too.
+type UpdateEmployeeRequest struct {
+	Name         string  `json:"name" validate:"required,max=255"`
+	Email        string  `json:"email" validate:"required,email,max=255"`
+	PhoneNumber  string  `json:"phone_number" validate:"required,max=50"`
+	Status       string  `json:"status" validate:"required,oneof=active on_leave terminated"`
+	JobTitle     string  `json:"job_title" validate:"max=255"`
+	DepartmentID *string `json:"department_id" validate:"omitempty,uuid"`
+	HireDate     *string `json:"hire_date" validate:"omitempty,datetime=2006-01-02"`
+	ManagerID    *string `json:"manager_id" validate:"omitempty,uuid"`
 }
 
 // EmployeeResponse is the DTO for responding with employee details.
 type EmployeeResponse struct {
-	ID          int64  `json:"id"` // Changed to int64 to match DB SERIAL PRIMARY KEY
-	Name        string `json:"name"`
-	Email       string `json:"email"`
-	PhoneNumber string `json:"phone_number"`
-	CreatedAt   string `json:"created_at"` // Formatted as ISO8601 string
-	UpdatedAt   string `json:"updated_at"`
-	// Add other fields that should be exposed in the API response
-	PasswordHash string `json:"-"` // FIX: Don't expose password_hash in API response
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	Email        string  `json:"email"`
+	PhoneNumber  string  `json:"phone_number"`
+	Status       string  `json:"status"`
+	JobTitle     string  `json:"job_title"`
+	DepartmentID *string `json:"department_id"`
+	HireDate     *string `json:"hire_date"` // Formatted as YYYY-MM-DD
+	ManagerID    *string `json:"manager_id"`
+	CreatedAt    string  `json:"created_at"` // Formatted as ISO8601 string
+	UpdatedAt    string  `json:"updated_at"`
+	Version      string  `json:"-"` // Sent as the ETag header, for If-Match on updates
 }
 
 // GetEmployeesRequest is the DTO for querying employees with pagination and filters.
 type GetEmployeesRequest struct {
 	utils.PaginationRequest
-	Status string `query:"status"` // Example custom filter for employees
-	// Add other specific filter fields
+	Status       string `query:"status" validate:"omitempty,oneof=active on_leave terminated"`
+	JobTitle     string `query:"job_title"`
+	DepartmentID string `query:"department_id" validate:"omitempty,uuid"`
+	ManagerID    string `query:"manager_id" validate:"omitempty,uuid"`
+	HiredFrom    string `query:"hired_from" validate:"omitempty,datetime=2006-01-02"`
+	HiredTo      string `query:"hired_to" validate:"omitempty,datetime=2006-01-02"`
 }
 
 // GetEmployeesResponse is the DTO for responding with a paginated list of employees.
 type GetEmployeesResponse = utils.PaginationResponse[EmployeeResponse]
+
+// ReportResponse is an employee in a tree of reports. Reports holds the employees reporting
+// directly to this one.
+type ReportResponse struct {
+	EmployeeResponse
+	Reports []ReportResponse `json:"reports"`
+}
+
+// OrgChartResponse is the DTO for responding with an employee's place in the reporting hierarchy.
+type OrgChartResponse struct {
+	Employee EmployeeResponse   `json:"employee"`
+	Managers []EmployeeResponse `json:"managers"` // From the direct manager to the top
+	Reports  []ReportResponse   `json:"reports"`  // Direct reports, each with their own reports
+}
+
+// ImportRow is a data row of an employee import file: the employee to create, plus the columns
+// that refer to a department by name or to a manager by email instead of by ID.
+type ImportRow struct {
+	Line         int // Line of the row in the file; the header is line 1
+	Employee     CreateEmployeeRequest
+	Department   string
+	ManagerEmail string
+	Errors       []string // Problems found while reading the row
+	InvalidEmail bool     // The email is missing or malformed, so it can't identify the row
+}
+
+// ImportRowError lists the problems of a row that was not imported.
+type ImportRowError struct {
+	Line   int      `json:"line"`
+	Email  string   `json:"email,omitempty"`
+	Errors []string `json:"errors"`
+}
+
+// ImportEmployeesResponse is the DTO for responding with the result of an employee import.
+type ImportEmployeesResponse struct {
+	DryRun    bool             `json:"dry_run"`
+	TotalRows int              `json:"total_rows"`
+	ValidRows int              `json:"valid_rows"`
+	Imported  int              `json:"imported"` // 0 on a dry run
+	Errors    []ImportRowError `json:"errors"`
+}
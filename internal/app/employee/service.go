@@ -2,31 +2,40 @@ package employee
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
-	"strconv"
+	"strings"
+	"time"
 
 	"starterpack-golang-cleanarch/internal/domain"
 	"starterpack-golang-cleanarch/internal/utils"
 	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/google/uuid"
 )
 
 type EmployeeService struct {
-	employeeRepo domain.EmployeeRepository
+	employeeRepo   domain.EmployeeRepository
+	departmentRepo domain.DepartmentRepository
+	transactor     domain.Transactor
 }
 
 // NewEmployeeService creates a new instance of EmployeeService.
-func NewEmployeeService(repo domain.EmployeeRepository) *EmployeeService {
-	return &EmployeeService{repo}
+func NewEmployeeService(repo domain.EmployeeRepository, departmentRepo domain.DepartmentRepository, transactor domain.Transactor) *EmployeeService {
+	return &EmployeeService{employeeRepo: repo, departmentRepo: departmentRepo, transactor: transactor}
 }
 
 // CreateEmployee handles the business logic for creating a new employee.
 func (s *EmployeeService) CreateEmployee(ctx context.Context, tenantID string, req CreateEmployeeRequest) (*EmployeeResponse, error) {
-	// TenantID di service sekarang bertipe string.
-	// Tidak perlu parsing UUID di sini jika di domain/repo sudah VARCHAR.
-	// Jika tenantID dari token selalu UUID, mungkin perlu validasi format di sini.
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
 
 	// 1. Business Validation: Check if email already exists for this tenant
-	existingEmployee, err := s.employeeRepo.FindByEmail(ctx, tenantID, req.Email) // tenantID langsung string
+	existingEmployee, err := s.employeeRepo.FindByEmail(ctx, parsedTenantID, req.Email)
 	if err != nil {
 		return nil, errors.NewInternalServerError(fmt.Errorf("failed to check existing employee: %w", err), "Internal error during employee creation check.")
 	}
@@ -36,39 +45,60 @@ func (s *EmployeeService) CreateEmployee(ctx context.Context, tenantID string, r
 
 	// 2. Map Request DTO to Domain Model
 	employee := &domain.Employee{
-		TenantID:     tenantID, // tenantID langsung string
-		Name:         req.Name,
-		Email:        req.Email,
-		PhoneNumber:  req.PhoneNumber,
-		PasswordHash: "dummy-hashed-password", // FIX: Provide a dummy password hash
+		TenantID:    parsedTenantID,
+		Name:        req.Name,
+		Email:       req.Email,
+		PhoneNumber: req.PhoneNumber,
+		Status:      req.Status,
+	}
+	if employee.Status == "" {
+		employee.Status = domain.EmployeeStatusActive
+	}
+	employee.GenerateID()
+	if err := s.setEmploymentDetails(ctx, employee, req.JobTitle, req.DepartmentID, req.HireDate, req.ManagerID); err != nil {
+		return nil, err
 	}
-	employee.GenerateID() // Ini akan mengisi TenantID dan Created/Updated timestamps
 
 	// 3. Call Repository to persist data
 	if err := s.employeeRepo.Save(ctx, employee); err != nil {
+		if stderrors.As(err, new(*domain.EmployeeEmailTakenError)) {
+			return nil, ErrEmployeeAlreadyExists
+		}
 		return nil, errors.NewInternalServerError(fmt.Errorf("failed to save employee to database: %w", err), "Internal error saving employee.")
 	}
 
 	// 4. Map Domain Model to Response DTO
-	resp := &EmployeeResponse{
-		ID:          employee.ID, // ID sekarang int64
-		Name:        employee.Name,
-		Email:       employee.Email,
-		PhoneNumber: employee.PhoneNumber,
-		CreatedAt:   employee.CreatedAt.Format(utils.ISO8601TimeFormat),
-		UpdatedAt:   employee.UpdatedAt.Format(utils.ISO8601TimeFormat),
-		// PasswordHash field tidak perlu diisi di sini karena di EmployeeResponse sudah ada `json:"-"`
-	}
-
-	return resp, nil
+	resp := newEmployeeResponse(employee)
+	return &resp, nil
 }
 
 // GetEmployees retrieves a list of employees with pagination.
 func (s *EmployeeService) GetEmployees(ctx context.Context, tenantID string, req GetEmployeesRequest) (*GetEmployeesResponse, error) {
-	// TenantID di service sekarang bertipe string.
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+
+	filter := domain.EmployeeFilter{
+		Query:    req.Query,
+		Status:   req.Status,
+		JobTitle: req.JobTitle,
+	}
+	if filter.DepartmentID, err = parseID(req.DepartmentID, "department_id"); err != nil {
+		return nil, err
+	}
+	if filter.ManagerID, err = parseID(req.ManagerID, "manager_id"); err != nil {
+		return nil, err
+	}
+	if filter.HiredFrom, err = parseDate(req.HiredFrom, "hired_from"); err != nil {
+		return nil, err
+	}
+	if filter.HiredTo, err = parseDate(req.HiredTo, "hired_to"); err != nil {
+		return nil, err
+	}
 
 	// 1. Call repository for total count and paginated data
-	total, employees, err := s.employeeRepo.FindAll(ctx, tenantID, req.Page, req.Limit, req.Query) // tenantID langsung string
+	total, employees, err := s.employeeRepo.FindAll(ctx, parsedTenantID, req.Page, req.Limit, filter)
 	if err != nil {
 		return nil, errors.NewInternalServerError(fmt.Errorf("failed to fetch employees from repository: %w", err), "Internal error fetching employees.")
 	}
@@ -76,14 +106,7 @@ func (s *EmployeeService) GetEmployees(ctx context.Context, tenantID string, req
 	// 2. Map domain models to response DTOs
 	employeeResponses := make([]EmployeeResponse, len(employees))
 	for i, emp := range employees {
-		employeeResponses[i] = EmployeeResponse{
-			ID:          emp.ID, // ID sekarang int64
-			Name:        emp.Name,
-			Email:       emp.Email,
-			PhoneNumber: emp.PhoneNumber,
-			CreatedAt:   emp.CreatedAt.Format(utils.ISO8601TimeFormat),
-			UpdatedAt:   emp.UpdatedAt.Format(utils.ISO8601TimeFormat),
-		}
+		employeeResponses[i] = newEmployeeResponse(emp)
 	}
 
 	// 3. Build PaginationResponse using the helper from utils
@@ -111,28 +134,530 @@ func (s *EmployeeService) GetEmployees(ctx context.Context, tenantID string, req
 
 // GetEmployeeByID retrieves a single employee by ID.
 func (s *EmployeeService) GetEmployeeByID(ctx context.Context, tenantID string, employeeID string) (*EmployeeResponse, error) {
-	// employeeID sekarang string, tidak perlu parsing UUID di sini jika di domain/repo sudah SERIAL.
-	// Lakukan konversi ke int64 untuk FindByID repository
-	parsedEmployeeID, err := strconv.ParseInt(employeeID, 10, 64)
+	employee, err := s.findEmployee(ctx, tenantID, employeeID)
 	if err != nil {
-		return nil, errors.NewBadRequest("Invalid employee ID format (must be integer)", nil)
+		return nil, err
 	}
 
-	employee, err := s.employeeRepo.FindByID(ctx, tenantID, parsedEmployeeID) // tenantID string, employeeID int64
+	resp := newEmployeeResponse(employee)
+	return &resp, nil
+}
+
+// ImportEmployees creates employees from the rows of an import file. Rows are checked like
+// CreateEmployee requests; the rows without problems are inserted together in one transaction and
+// the others are reported with their problems. With dryRun nothing is inserted.
+//
+// Besides IDs, rows can name their department, and refer to their manager by email: either an
+// existing employee or another row of the file, which is inserted first.
+func (s *EmployeeService) ImportEmployees(ctx context.Context, tenantID string, rows []ImportRow, dryRun bool) (*ImportEmployeesResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+
+	// 1. Load what the rows refer to: departments, and existing employees by email
+	departments, err := s.departmentRepo.FindAll(ctx, parsedTenantID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to fetch departments: %w", err), "Internal error importing employees.")
+	}
+	departmentIDs := make(map[uuid.UUID]bool, len(departments))
+	departmentsByName := make(map[string]uuid.UUID, len(departments))
+	for _, department := range departments {
+		departmentIDs[department.ID] = true
+		departmentsByName[strings.ToLower(department.Name)] = department.ID
+	}
+
+	var emails []string
+	for _, row := range rows {
+		if !row.InvalidEmail {
+			emails = append(emails, row.Employee.Email)
+		}
+		if row.ManagerEmail != "" {
+			emails = append(emails, row.ManagerEmail)
+		}
+	}
+	existing, err := s.employeeRepo.FindByEmails(ctx, parsedTenantID, emails)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to check existing employees: %w", err), "Internal error importing employees.")
+	}
+	existingByEmail := make(map[string]*domain.Employee, len(existing))
+	for _, emp := range existing {
+		existingByEmail[emp.Email] = emp
+	}
+	managerExists := make(map[uuid.UUID]bool)
+
+	// 2. Check every row on its own and map it to an employee
+	entries := make([]importEntry, len(rows))
+	rowByEmail := make(map[string]int, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		entry := &entries[i]
+		entry.line = row.Line
+		entry.managerRow = -1
+		entry.errors = append(entry.errors, row.Errors...)
+
+		employee := &domain.Employee{
+			TenantID:    parsedTenantID,
+			Name:        row.Employee.Name,
+			Email:       row.Employee.Email,
+			PhoneNumber: row.Employee.PhoneNumber,
+			Status:      row.Employee.Status,
+			JobTitle:    row.Employee.JobTitle,
+		}
+		if employee.Status == "" {
+			employee.Status = domain.EmployeeStatusActive
+		}
+		employee.GenerateID()
+		entry.employee = employee
+
+		// Missing or malformed emails were reported when the row was read; such rows can't be
+		// anyone's manager either.
+		if !row.InvalidEmail {
+			if existingByEmail[employee.Email] != nil {
+				entry.errors = append(entry.errors, "email is already used by another employee")
+			} else if first, found := rowByEmail[employee.Email]; found {
+				entry.errors = append(entry.errors, fmt.Sprintf("email is also used on line %d", entries[first].line))
+			} else {
+				rowByEmail[employee.Email] = i
+			}
+		}
+
+		// Malformed IDs and dates were reported when the row was read, so they are skipped here.
+		if id, err := parseID(stringValue(row.Employee.DepartmentID), "department_id"); err == nil && id != nil {
+			if departmentIDs[*id] {
+				employee.DepartmentID = id
+			} else {
+				entry.errors = append(entry.errors, "department_id is not a department of the tenant")
+			}
+		}
+		if row.Department != "" {
+			if id, found := departmentsByName[strings.ToLower(row.Department)]; found {
+				employee.DepartmentID = &id
+			} else {
+				entry.errors = append(entry.errors, fmt.Sprintf("department %q doesn't exist", row.Department))
+			}
+		}
+		if date, err := parseDate(stringValue(row.Employee.HireDate), "hire_date"); err == nil {
+			employee.HireDate = date
+		}
+		if id, err := parseID(stringValue(row.Employee.ManagerID), "manager_id"); err == nil && id != nil {
+			exists, checked := managerExists[*id]
+			if !checked {
+				manager, err := s.employeeRepo.FindByID(ctx, parsedTenantID, *id)
+				if err != nil {
+					return nil, errors.NewInternalServerError(fmt.Errorf("failed to find manager: %w", err), "Internal error importing employees.")
+				}
+				exists = manager != nil
+				managerExists[*id] = exists
+			}
+			if exists {
+				employee.ManagerID = id
+			} else {
+				entry.errors = append(entry.errors, "manager_id is not an employee of the tenant")
+			}
+		}
+		if row.ManagerEmail != "" {
+			if row.ManagerEmail == employee.Email {
+				entry.errors = append(entry.errors, "an employee can't be their own manager")
+			} else if manager := existingByEmail[row.ManagerEmail]; manager != nil {
+				employee.ManagerID = &manager.ID
+			} else {
+				entry.managerEmail = row.ManagerEmail // Resolved once every row is known
+			}
+		}
+	}
+
+	// 3. Link rows to managers in the file, and order managers before their reports
+	for i := range entries {
+		if email := entries[i].managerEmail; email != "" {
+			if manager, found := rowByEmail[email]; found {
+				entries[i].managerRow = manager
+			} else {
+				entries[i].errors = append(entries[i].errors, "manager_email is not an employee of the tenant or in the file")
+			}
+		}
+	}
+	valid := orderImportEntries(entries)
+
+	// 4. Insert the valid rows together. An email taken by an employee added since the check fails
+	// its row, and the remaining rows are tried again.
+	imported := 0
+	for !dryRun && len(valid) > 0 {
+		err := s.employeeRepo.SaveAll(ctx, valid)
+		var taken *domain.EmployeeEmailTakenError
+		if stderrors.As(err, &taken) {
+			if i, found := rowByEmail[taken.Email]; found {
+				entries[i].errors = append(entries[i].errors, "email is already used by another employee")
+				valid = orderImportEntries(entries)
+				continue
+			}
+		}
+		if err != nil {
+			return nil, errors.NewInternalServerError(fmt.Errorf("failed to save imported employees: %w", err), "Internal error importing employees.")
+		}
+		imported = len(valid)
+		log.Infof(ctx, "Employees: Imported %d employees into tenant %s", imported, parsedTenantID)
+		break
+	}
+
+	resp := &ImportEmployeesResponse{
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		ValidRows: len(valid),
+		Imported:  imported,
+		Errors:    []ImportRowError{},
+	}
+	for _, entry := range entries {
+		if len(entry.errors) > 0 {
+			resp.Errors = append(resp.Errors, ImportRowError{Line: entry.line, Email: entry.employee.Email, Errors: entry.errors})
+		}
+	}
+	return resp, nil
+}
+
+// importEntry is a row of an import while it is checked.
+type importEntry struct {
+	line         int
+	employee     *domain.Employee
+	managerEmail string // Email of a manager that is not an existing employee
+	managerRow   int    // Index of the manager's row in the file; -1 for none
+	errors       []string
+}
+
+// addError records a problem of the entry once, as entries are visited again when a retried
+// insert fails another row.
+func (e *importEntry) addError(message string) {
+	for _, existing := range e.errors {
+		if existing == message {
+			return
+		}
+	}
+	e.errors = append(e.errors, message)
+}
+
+// Visit states of visitImportEntry.
+const (
+	importEntryUnvisited = iota
+	importEntryVisiting
+	importEntryVisited
+)
+
+// orderImportEntries returns the employees of the entries that can be imported, managers before
+// their reports. It can be called again after more entries got errors.
+func orderImportEntries(entries []importEntry) []*domain.Employee {
+	var valid []*domain.Employee
+	states := make([]int, len(entries))
+	for i := range entries {
+		valid = visitImportEntry(entries, states, i, valid)
+	}
+	return valid
+}
+
+// visitImportEntry appends the entry's employee to valid after its manager's, when neither has
+// errors. A row can't be imported when its manager's row can't, or when the managers in the file
+// form a cycle.
+func visitImportEntry(entries []importEntry, states []int, i int, valid []*domain.Employee) []*domain.Employee {
+	if states[i] != importEntryUnvisited {
+		return valid
+	}
+	states[i] = importEntryVisiting
+	entry := &entries[i]
+	if m := entry.managerRow; m >= 0 {
+		if states[m] == importEntryVisiting {
+			entry.addError("the managers in the file form a cycle")
+		} else {
+			valid = visitImportEntry(entries, states, m, valid)
+			if len(entries[m].errors) > 0 {
+				entry.addError(fmt.Sprintf("the manager on line %d can't be imported", entries[m].line))
+			} else {
+				entry.employee.ManagerID = &entries[m].employee.ID
+			}
+		}
+	}
+	states[i] = importEntryVisited
+	if len(entry.errors) == 0 {
+		valid = append(valid, entry.employee)
+	}
+	return valid
+}
+
+// GetOrgChart returns an employee's place in the reporting hierarchy: their managers up to the top
+// and everyone reporting to them, directly or indirectly, as a tree.
+func (s *EmployeeService) GetOrgChart(ctx context.Context, tenantID, employeeID string) (*OrgChartResponse, error) {
+	employee, err := s.findEmployee(ctx, tenantID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	managers, err := s.employeeRepo.FindManagementChain(ctx, employee.TenantID, employee.ID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find management chain: %w", err), "Internal error fetching org chart.")
+	}
+	reports, err := s.employeeRepo.FindReports(ctx, employee.TenantID, employee.ID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find reports: %w", err), "Internal error fetching org chart.")
+	}
+
+	resp := &OrgChartResponse{
+		Employee: newEmployeeResponse(employee),
+		Managers: make([]EmployeeResponse, len(managers)),
+	}
+	for i, manager := range managers {
+		resp.Managers[i] = newEmployeeResponse(manager)
+	}
+
+	// Reports come ordered by depth and name, so each manager's reports keep the name order.
+	reportsByManager := make(map[uuid.UUID][]*domain.EmployeeReport)
+	for _, report := range reports {
+		if report.ManagerID != nil {
+			reportsByManager[*report.ManagerID] = append(reportsByManager[*report.ManagerID], report)
+		}
+	}
+	resp.Reports = buildReportTree(reportsByManager, employee.ID)
+	return resp, nil
+}
+
+func buildReportTree(reportsByManager map[uuid.UUID][]*domain.EmployeeReport, managerID uuid.UUID) []ReportResponse {
+	reports := reportsByManager[managerID]
+	delete(reportsByManager, managerID) // Visit every manager once, even if the data has a cycle
+	tree := make([]ReportResponse, len(reports))
+	for i, report := range reports {
+		tree[i] = ReportResponse{
+			EmployeeResponse: newEmployeeResponse(&report.Employee),
+			Reports:          buildReportTree(reportsByManager, report.ID),
+		}
+	}
+	return tree
+}
+
+// UpdateEmployee replaces the details of an employee. The new email must not belong to another
+// employee of the tenant. version is the employee's version the change is based on, from the ETag
+// of an earlier response, or empty; the update fails when the employee has changed since.
+func (s *EmployeeService) UpdateEmployee(ctx context.Context, tenantID, employeeID string, req UpdateEmployeeRequest, version string) (*EmployeeResponse, error) {
+	employee, err := s.findEmployee(ctx, tenantID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateEmployee(ctx, employee, req, version)
+}
+
+// PatchEmployee applies a JSON Merge Patch (RFC 7396) to the employee as returned by
+// GetEmployeeByID. The result is checked with validate and stored like an UpdateEmployee request.
+func (s *EmployeeService) PatchEmployee(ctx context.Context, tenantID, employeeID string, patch []byte, version string, validate func(interface{}) error) (*EmployeeResponse, error) {
+	employee, err := s.findEmployee(ctx, tenantID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := json.Marshal(newEmployeeResponse(employee))
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to encode employee: %w", err), "Internal error patching employee.")
+	}
+	patched, err := utils.MergePatch(doc, patch)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid request payload", nil)
+	}
+	var req UpdateEmployeeRequest
+	if err := json.Unmarshal(patched, &req); err != nil {
+		return nil, errors.NewBadRequest("Invalid request payload", nil)
+	}
+	if err := validate(req); err != nil {
+		return nil, errors.NewBadRequest(err.Error(), nil)
+	}
+	return s.updateEmployee(ctx, employee, req, version)
+}
+
+// updateEmployee stores the details of req on the employee, as loaded by the caller. Changes made
+// in between by other requests are detected through updated_at.
+func (s *EmployeeService) updateEmployee(ctx context.Context, employee *domain.Employee, req UpdateEmployeeRequest, version string) (*EmployeeResponse, error) {
+	if version != "" && version != employee.Version() {
+		return nil, ErrEmployeeModified
+	}
+	previousUpdatedAt := employee.UpdatedAt
+
+	if req.Email != employee.Email {
+		existingEmployee, err := s.employeeRepo.FindByEmail(ctx, employee.TenantID, req.Email)
+		if err != nil {
+			return nil, errors.NewInternalServerError(fmt.Errorf("failed to check existing employee: %w", err), "Internal error during employee update check.")
+		}
+		if existingEmployee != nil && existingEmployee.ID != employee.ID {
+			return nil, ErrEmployeeAlreadyExists
+		}
+	}
+
+	employee.Name = req.Name
+	employee.Email = req.Email
+	employee.PhoneNumber = req.PhoneNumber
+	employee.Status = req.Status
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// A new manager is checked for cycles and stored under the tenant's hierarchy lock, so two
+		// concurrent manager changes can't form a cycle together.
+		if stringValue(req.ManagerID) != "" {
+			if err := s.employeeRepo.LockHierarchy(ctx, employee.TenantID); err != nil {
+				return errors.NewInternalServerError(fmt.Errorf("failed to lock reporting hierarchy: %w", err), "Internal error updating employee.")
+			}
+		}
+		if err := s.setEmploymentDetails(ctx, employee, req.JobTitle, req.DepartmentID, req.HireDate, req.ManagerID); err != nil {
+			return err
+		}
+		employee.UpdateTimestamp()
+
+		updated, err := s.employeeRepo.Update(ctx, employee, previousUpdatedAt)
+		if err != nil {
+			if stderrors.As(err, new(*domain.EmployeeEmailTakenError)) {
+				return ErrEmployeeAlreadyExists
+			}
+			return errors.NewInternalServerError(fmt.Errorf("failed to update employee: %w", err), "Internal error updating employee.")
+		}
+		if !updated {
+			return ErrEmployeeModified
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := newEmployeeResponse(employee)
+	return &resp, nil
+}
+
+// DeleteEmployee deletes an employee of the tenant.
+func (s *EmployeeService) DeleteEmployee(ctx context.Context, tenantID, employeeID string) error {
+	employee, err := s.findEmployee(ctx, tenantID, employeeID)
+	if err != nil {
+		return err
+	}
+	if err := s.employeeRepo.Delete(ctx, employee.TenantID, employee.ID); err != nil {
+		return errors.NewInternalServerError(fmt.Errorf("failed to delete employee: %w", err), "Internal error deleting employee.")
+	}
+	return nil
+}
+
+func (s *EmployeeService) findEmployee(ctx context.Context, tenantID, employeeID string) (*domain.Employee, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	parsedEmployeeID, err := uuid.Parse(employeeID)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid employee ID format (must be UUID)", nil)
+	}
+
+	employee, err := s.employeeRepo.FindByID(ctx, parsedTenantID, parsedEmployeeID)
 	if err != nil {
 		return nil, errors.NewInternalServerError(fmt.Errorf("failed to get employee from repository: %w", err), "Internal error fetching employee by ID.")
 	}
 	if employee == nil {
 		return nil, ErrEmployeeNotFound
 	}
+	return employee, nil
+}
+
+// setEmploymentDetails sets the job title, department, hire date and manager of the employee. The
+// department and the manager must belong to the same tenant, and the manager can't report to the
+// employee, which would make the hierarchy a cycle.
+func (s *EmployeeService) setEmploymentDetails(ctx context.Context, employee *domain.Employee, jobTitle string, departmentID, hireDate, managerID *string) error {
+	var err error
+	employee.JobTitle = jobTitle
+
+	if employee.DepartmentID, err = parseID(stringValue(departmentID), "department_id"); err != nil {
+		return err
+	}
+	if employee.DepartmentID != nil {
+		department, err := s.departmentRepo.FindByID(ctx, employee.TenantID, *employee.DepartmentID)
+		if err != nil {
+			return errors.NewInternalServerError(fmt.Errorf("failed to find department: %w", err), "Internal error checking employee department.")
+		}
+		if department == nil {
+			return ErrDepartmentNotFound
+		}
+	}
+
+	if employee.HireDate, err = parseDate(stringValue(hireDate), "hire_date"); err != nil {
+		return err
+	}
+
+	if employee.ManagerID, err = parseID(stringValue(managerID), "manager_id"); err != nil {
+		return err
+	}
+	if employee.ManagerID != nil {
+		if *employee.ManagerID == employee.ID {
+			return ErrSelfManager
+		}
+		manager, err := s.employeeRepo.FindByID(ctx, employee.TenantID, *employee.ManagerID)
+		if err != nil {
+			return errors.NewInternalServerError(fmt.Errorf("failed to find manager: %w", err), "Internal error checking employee manager.")
+		}
+		if manager == nil {
+			return ErrManagerNotFound
+		}
+		chain, err := s.employeeRepo.FindManagementChain(ctx, employee.TenantID, manager.ID)
+		if err != nil {
+			return errors.NewInternalServerError(fmt.Errorf("failed to find management chain: %w", err), "Internal error checking employee manager.")
+		}
+		for _, above := range chain {
+			if above.ID == employee.ID {
+				return ErrManagerCycle
+			}
+		}
+	}
+	return nil
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// parseID parses an optional UUID; an empty string is no ID.
+func parseID(value, field string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid "+field+" format (must be UUID)", nil)
+	}
+	return &id, nil
+}
 
-	resp := &EmployeeResponse{
-		ID:          employee.ID, // ID sekarang int64
+// parseDate parses a YYYY-MM-DD date; an empty string is no date.
+func parseDate(value, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(utils.ISO8601DateFormat, value)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid "+field+" format (must be YYYY-MM-DD)", nil)
+	}
+	return &date, nil
+}
+
+func newEmployeeResponse(employee *domain.Employee) EmployeeResponse {
+	resp := EmployeeResponse{
+		ID:          employee.ID.String(),
 		Name:        employee.Name,
 		Email:       employee.Email,
 		PhoneNumber: employee.PhoneNumber,
+		Status:      employee.Status,
+		JobTitle:    employee.JobTitle,
 		CreatedAt:   employee.CreatedAt.Format(utils.ISO8601TimeFormat),
 		UpdatedAt:   employee.UpdatedAt.Format(utils.ISO8601TimeFormat),
+		Version:     employee.Version(),
 	}
-	return resp, nil
+	if employee.HireDate != nil {
+		hireDate := employee.HireDate.Format(utils.ISO8601DateFormat)
+		resp.HireDate = &hireDate
+	}
+	if employee.DepartmentID != nil {
+		departmentID := employee.DepartmentID.String()
+		resp.DepartmentID = &departmentID
+	}
+	if employee.ManagerID != nil {
+		managerID := employee.ManagerID.String()
+		resp.ManagerID = &managerID
+	}
+	return resp
 }
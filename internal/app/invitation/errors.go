@@ -0,0 +1,18 @@
+package invitation
+
+import (
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/utils/errors"
+)
+
+// Module-specific custom errors for invitations.
+var (
+	ErrInvitationNotFound = errors.New("INVITATION_NOT_FOUND", "Pending invitation with given ID not found", http.StatusNotFound, nil, nil)
+	ErrInvalidInvitation  = errors.New("INVALID_INVITATION", "Invitation is invalid, expired or already used", http.StatusBadRequest, nil, nil)
+	ErrUserAlreadyExists  = errors.New("USER_ALREADY_EXISTS", "User with this email already exists in this tenant", http.StatusConflict, nil, nil)
+	ErrNameRequired       = errors.New("NAME_REQUIRED", "A name is required to create the account", http.StatusBadRequest, nil, nil)
+	ErrExistingAccount    = errors.New("INVALID_CREDENTIALS", "An account with this email already exists; enter its password to join the tenant", http.StatusUnauthorized, nil, nil)
+	ErrRoleNotFound       = errors.New("ROLE_NOT_FOUND", "Role with given name not found", http.StatusNotFound, nil, nil)
+	ErrRoleNotAssignable  = errors.New("ROLE_NOT_ASSIGNABLE", "This role can't be granted through an invitation", http.StatusForbidden, nil, nil)
+)
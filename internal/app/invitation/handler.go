@@ -0,0 +1,138 @@
+package invitation
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/http/middleware"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type InvitationHandler struct {
+	service   *InvitationService
+	validator *validator.Validate
+}
+
+// NewInvitationHandler creates a new instance of InvitationHandler.
+func NewInvitationHandler(s *InvitationService, v *validator.Validate) *InvitationHandler {
+	return &InvitationHandler{service: s, validator: v}
+}
+
+// RegisterRoutes registers the public route for accepting an invitation.
+func (h *InvitationHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/auth/accept-invite", h.AcceptInvitation).Methods("POST")
+}
+
+// RegisterAdminRoutes registers the invitation management routes on the authenticated router.
+func (h *InvitationHandler) RegisterAdminRoutes(router *mux.Router) {
+	manage := middleware.RequirePermission(domain.PermissionUsersWrite)
+	router.Handle("/invitations", manage(http.HandlerFunc(h.GetPendingInvitations))).Methods("GET")
+	router.Handle("/invitations", manage(http.HandlerFunc(h.CreateInvitation))).Methods("POST")
+	router.Handle("/invitations/{id}", manage(http.HandlerFunc(h.RevokeInvitation))).Methods("DELETE")
+}
+
+// CreateInvitation handles the request to invite a user into the caller's tenant. Inviting with
+// a role other than the default one grants that role, so it also requires roles:assign.
+func (h *InvitationHandler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
+	var req CreateInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	if req.Role != "" && req.Role != domain.RoleUser {
+		canAssign, err := middleware.HasPermission(r.Context(), domain.PermissionRolesAssign)
+		if err != nil {
+			utils.HandleHTTPError(w, errors.NewInternalServerError(err, "Failed to resolve permissions"), r)
+			return
+		}
+		if !canAssign {
+			utils.HandleHTTPError(w, errors.ErrForbidden, r)
+			return
+		}
+	}
+
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+	var userID string
+	if _, isKey := r.Context().Value(middleware.ContextKeyAPIKeyID).(string); !isKey {
+		userID, _ = r.Context().Value(middleware.ContextKeyUserID).(string)
+	}
+
+	invitation, err := h.service.CreateInvitation(r.Context(), tenantID, userID, req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, invitation)
+}
+
+// GetPendingInvitations handles the request to list the tenant's pending invitations.
+func (h *InvitationHandler) GetPendingInvitations(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	invitations, err := h.service.GetPendingInvitations(r.Context(), tenantID)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, invitations)
+}
+
+// RevokeInvitation handles the request to withdraw a pending invitation.
+func (h *InvitationHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	if err := h.service.RevokeInvitation(r.Context(), tenantID, mux.Vars(r)["id"]); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AcceptInvitation handles the request to accept an invitation. It creates the account and
+// responds like /auth/login.
+func (h *InvitationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	var req AcceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	authResp, err := h.service.AcceptInvitation(r.Context(), req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, authResp)
+}
@@ -0,0 +1,27 @@
+package invitation
+
+// CreateInvitationRequest is the DTO for inviting a user into the caller's tenant.
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,max=50"` // Defaults to the built-in "user" role
+}
+
+// AcceptInvitationRequest is the DTO for accepting an invitation. Invitees without an account
+// create one with the name and password; invitees who already have an account in another tenant
+// join with it by giving its password.
+type AcceptInvitationRequest struct {
+	Token       string `json:"token" validate:"required"`
+	Name        string `json:"name"`                         // Required when a new account is created
+	Password    string `json:"password" validate:"required"` // Checked against the tenant's password policy for new accounts
+	PhoneNumber string `json:"phone_number"`
+}
+
+// InvitationResponse is the DTO for responding with invitation details.
+type InvitationResponse struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	InvitedBy string `json:"invited_by,omitempty"`
+	ExpiresAt string `json:"expires_at"`
+	CreatedAt string `json:"created_at"`
+}
@@ -0,0 +1,337 @@
+package invitation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/app/auth"
+	"starterpack-golang-cleanarch/internal/app/passwordpolicy"
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/mailer"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Dependencies are the collaborators InvitationService needs.
+type Dependencies struct {
+	InvitationRepo domain.InvitationRepository
+	UserRepo       domain.UserRepository
+	RoleRepo       domain.RoleRepository
+	TenantRepo     domain.TenantRepository
+	MembershipRepo domain.TenantMembershipRepository
+	Mailer         mailer.Mailer
+	AuthService    *auth.AuthService
+	PasswordPolicy *passwordpolicy.PasswordPolicyService
+	Transactor     domain.Transactor
+}
+
+// Config holds the tunable settings of InvitationService.
+type Config struct {
+	FrontendURL   string        // Base URL of the web app, used for the link in the invitation email
+	InvitationTTL time.Duration // Time the invitee has to accept
+}
+
+type InvitationService struct {
+	invitationRepo domain.InvitationRepository
+	userRepo       domain.UserRepository
+	roleRepo       domain.RoleRepository
+	tenantRepo     domain.TenantRepository
+	membershipRepo domain.TenantMembershipRepository
+	mailer         mailer.Mailer
+	authService    *auth.AuthService
+	passwordPolicy *passwordpolicy.PasswordPolicyService
+	transactor     domain.Transactor
+	cfg            Config
+}
+
+// NewInvitationService creates a new instance of InvitationService.
+func NewInvitationService(deps Dependencies, cfg Config) *InvitationService {
+	return &InvitationService{
+		invitationRepo: deps.InvitationRepo,
+		userRepo:       deps.UserRepo,
+		roleRepo:       deps.RoleRepo,
+		tenantRepo:     deps.TenantRepo,
+		membershipRepo: deps.MembershipRepo,
+		mailer:         deps.Mailer,
+		authService:    deps.AuthService,
+		passwordPolicy: deps.PasswordPolicy,
+		transactor:     deps.Transactor,
+		cfg:            cfg,
+	}
+}
+
+// CreateInvitation invites an email address into the tenant with a role and emails the invitation
+// link. Pending invitations of the same address are replaced. inviterID is empty for API keys.
+func (s *InvitationService) CreateInvitation(ctx context.Context, tenantID, inviterID string, req CreateInvitationRequest) (*InvitationResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	// Invitations created with an API key have no inviting user.
+	var invitedBy uuid.NullUUID
+	if inviterID != "" {
+		parsedInviterID, err := uuid.Parse(inviterID)
+		if err != nil {
+			return nil, errors.ErrUnauthorized
+		}
+		invitedBy = uuid.NullUUID{UUID: parsedInviterID, Valid: true}
+	}
+
+	roleName := req.Role
+	if roleName == "" {
+		roleName = domain.RoleUser
+	}
+	if roleName == domain.RolePlatformAdmin {
+		return nil, ErrRoleNotAssignable
+	}
+	role, err := s.roleRepo.FindByName(ctx, parsedTenantID, roleName)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find role: %w", err), "Internal error creating invitation.")
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+
+	accounts, err := s.userRepo.FindAllByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to check existing user: %w", err), "Internal error creating invitation.")
+	}
+	member, err := s.findMember(ctx, parsedTenantID, accounts)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to check existing user: %w", err), "Internal error creating invitation.")
+	}
+	if member != nil {
+		return nil, ErrUserAlreadyExists
+	}
+
+	tenant, err := s.tenantRepo.FindByID(ctx, parsedTenantID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find tenant: %w", err), "Internal error creating invitation.")
+	}
+	if tenant == nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("tenant %s does not exist", tenantID), "Internal error creating invitation.")
+	}
+
+	if err := s.invitationRepo.RevokePendingForEmail(ctx, parsedTenantID, req.Email); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to revoke previous invitations: %w", err), "Internal error creating invitation.")
+	}
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, errors.NewInternalServerError(err, "Internal error generating token.")
+	}
+	invitation := &domain.Invitation{
+		ID:        uuid.New(),
+		TenantID:  parsedTenantID,
+		Email:     req.Email,
+		Role:      role.Name,
+		TokenHash: hash,
+		InvitedBy: invitedBy,
+		ExpiresAt: time.Now().Add(s.cfg.InvitationTTL),
+		CreatedAt: time.Now(),
+	}
+	if err := s.invitationRepo.Save(ctx, invitation); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to save invitation: %w", err), "Internal error creating invitation.")
+	}
+
+	// The email is the only way the invitee gets the token, so an invitation that could not be
+	// delivered is withdrawn again.
+	if err := s.sendInvitationEmail(ctx, tenant, invitation, token); err != nil {
+		if _, revokeErr := s.invitationRepo.Revoke(ctx, parsedTenantID, invitation.ID); revokeErr != nil {
+			log.Errorf(ctx, "Invitation: Failed to revoke undelivered invitation %s: %v", invitation.ID, revokeErr)
+		}
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to send invitation email: %w", err), "Failed to send the invitation email.")
+	}
+	log.Infof(ctx, "Invitation: Invited %s to tenant %s as %s", req.Email, tenantID, role.Name)
+
+	resp := newInvitationResponse(invitation)
+	return &resp, nil
+}
+
+// GetPendingInvitations lists the tenant's invitations that can still be accepted.
+func (s *InvitationService) GetPendingInvitations(ctx context.Context, tenantID string) ([]InvitationResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	invitations, err := s.invitationRepo.FindPending(ctx, parsedTenantID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to fetch invitations: %w", err), "Internal error fetching invitations.")
+	}
+	resp := make([]InvitationResponse, len(invitations))
+	for i := range invitations {
+		resp[i] = newInvitationResponse(&invitations[i])
+	}
+	return resp, nil
+}
+
+// RevokeInvitation withdraws a pending invitation of the tenant.
+func (s *InvitationService) RevokeInvitation(ctx context.Context, tenantID, id string) error {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return errors.ErrUnauthorized
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return errors.NewBadRequest("Invalid invitation ID format (must be UUID)", nil)
+	}
+	revoked, err := s.invitationRepo.Revoke(ctx, parsedTenantID, parsedID)
+	if err != nil {
+		return errors.NewInternalServerError(fmt.Errorf("failed to revoke invitation: %w", err), "Internal error revoking invitation.")
+	}
+	if !revoked {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation adds the invitee to the invitation's tenant with the invited role and logs them
+// in to it. An invitee who already has an account in another tenant joins with that account;
+// otherwise a new account is created. Opening the emailed link proves ownership of the address,
+// so the new account's email is verified.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, req AcceptInvitationRequest) (*auth.AuthResponse, error) {
+	invitation, err := s.invitationRepo.FindValidByHash(ctx, utils.HashToken(req.Token))
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find invitation: %w", err), "Internal error accepting invitation.")
+	}
+	if invitation == nil {
+		return nil, ErrInvalidInvitation
+	}
+
+	accounts, err := s.userRepo.FindAllByEmail(ctx, invitation.Email)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to check existing user: %w", err), "Internal error accepting invitation.")
+	}
+	member, err := s.findMember(ctx, invitation.TenantID, accounts)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to check existing user: %w", err), "Internal error accepting invitation.")
+	}
+	if member != nil {
+		return nil, ErrUserAlreadyExists
+	}
+	if len(accounts) > 0 {
+		return s.joinWithExistingAccount(ctx, invitation, accounts, req.Password)
+	}
+	if req.Name == "" {
+		return nil, ErrNameRequired
+	}
+	if err := s.passwordPolicy.Check(ctx, invitation.TenantID, uuid.Nil, req.Password); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to hash password: %w", err), "Internal error during password hashing.")
+	}
+
+	user := &domain.User{
+		Email:        invitation.Email,
+		PasswordHash: string(hashedPassword),
+		Name:         req.Name,
+		PhoneNumber:  req.PhoneNumber,
+		TenantID:     invitation.TenantID,
+		Role:         invitation.Role,
+	}
+	user.GenerateID()
+	user.EmailVerifiedAt = &user.CreatedAt
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.accept(ctx, invitation); err != nil {
+			return err
+		}
+		if err := s.authService.ProvisionUser(ctx, user); err != nil {
+			return err
+		}
+		if err := s.passwordPolicy.RecordPassword(ctx, user.ID, user.PasswordHash); err != nil {
+			return errors.NewInternalServerError(err, "Internal error accepting invitation.")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Infof(ctx, "Invitation: Invitation %s accepted, user %s created in tenant %s", invitation.ID, user.ID, user.TenantID)
+
+	return s.authService.CompleteLogin(ctx, user, user.TenantID)
+}
+
+// joinWithExistingAccount makes the invitee's existing account a member of the invitation's tenant
+// instead of creating a second account. The password proves that the invitee owns the account.
+func (s *InvitationService) joinWithExistingAccount(ctx context.Context, invitation *domain.Invitation, accounts []domain.User, password string) (*auth.AuthResponse, error) {
+	var user *domain.User
+	for i := range accounts {
+		if bcrypt.CompareHashAndPassword([]byte(accounts[i].PasswordHash), []byte(password)) == nil {
+			user = &accounts[i]
+			break
+		}
+	}
+	if user == nil {
+		return nil, ErrExistingAccount
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.accept(ctx, invitation); err != nil {
+			return err
+		}
+		return s.authService.AddMembership(ctx, user, invitation.TenantID, invitation.Role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Infof(ctx, "Invitation: Invitation %s accepted, user %s joined tenant %s", invitation.ID, user.ID, invitation.TenantID)
+
+	return s.authService.CompleteLogin(ctx, user, invitation.TenantID)
+}
+
+// accept marks the invitation as accepted. It is called in the transaction that adds the invitee
+// to the tenant, so a failure there leaves the invitation pending and the link usable again.
+func (s *InvitationService) accept(ctx context.Context, invitation *domain.Invitation) error {
+	accepted, err := s.invitationRepo.Accept(ctx, invitation.ID)
+	if err != nil {
+		return errors.NewInternalServerError(fmt.Errorf("failed to accept invitation: %w", err), "Internal error accepting invitation.")
+	}
+	if !accepted {
+		return ErrInvalidInvitation
+	}
+	return nil
+}
+
+// findMember returns the account among the accounts of an email that is a member of the tenant, if any.
+func (s *InvitationService) findMember(ctx context.Context, tenantID uuid.UUID, accounts []domain.User) (*domain.User, error) {
+	for i := range accounts {
+		membership, err := s.membershipRepo.Find(ctx, accounts[i].ID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if membership != nil {
+			return &accounts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *InvitationService) sendInvitationEmail(ctx context.Context, tenant *domain.Tenant, invitation *domain.Invitation, token string) error {
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("You have been invited to join %s", tenant.Name),
+		Body: fmt.Sprintf("Hi,\n\nYou have been invited to join %s. Open the link below to create your account. It expires in %s.\n\n%s/accept-invite?token=%s\n",
+			tenant.Name, s.cfg.InvitationTTL, s.cfg.FrontendURL, token),
+	})
+}
+
+func newInvitationResponse(invitation *domain.Invitation) InvitationResponse {
+	resp := InvitationResponse{
+		ID:        invitation.ID.String(),
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		ExpiresAt: invitation.ExpiresAt.Format(utils.ISO8601TimeFormat),
+		CreatedAt: invitation.CreatedAt.Format(utils.ISO8601TimeFormat),
+	}
+	if invitation.InvitedBy.Valid {
+		resp.InvitedBy = invitation.InvitedBy.UUID.String()
+	}
+	return resp
+}
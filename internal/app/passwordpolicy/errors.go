@@ -0,0 +1,24 @@
+package passwordpolicy
+
+import (
+	"net/http"
+	"strings"
+
+	"starterpack-golang-cleanarch/internal/utils/errors"
+)
+
+// Module-specific custom errors for password policies.
+var (
+	ErrPasswordBreached = errors.New("PASSWORD_BREACHED", "This password has appeared in a data breach, please choose another one", http.StatusBadRequest, nil, nil)
+	ErrPasswordReused   = errors.New("PASSWORD_REUSED", "This password was used recently, please choose another one", http.StatusBadRequest, nil, nil)
+)
+
+// newPolicyViolationError lists the rules of the policy the password doesn't meet, e.g.
+// "Password must be at least 12 characters long and contain a digit".
+func newPolicyViolationError(violations []string) errors.AppError {
+	message := violations[0]
+	if len(violations) > 1 {
+		message = strings.Join(violations[:len(violations)-1], ", ") + " and " + violations[len(violations)-1]
+	}
+	return errors.New("PASSWORD_POLICY_VIOLATION", "Password must "+message, http.StatusBadRequest, nil, nil)
+}
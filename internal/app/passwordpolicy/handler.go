@@ -0,0 +1,78 @@
+package passwordpolicy
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/http/middleware"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type PasswordPolicyHandler struct {
+	service   *PasswordPolicyService
+	validator *validator.Validate
+}
+
+// NewPasswordPolicyHandler creates a new instance of PasswordPolicyHandler.
+func NewPasswordPolicyHandler(s *PasswordPolicyService, v *validator.Validate) *PasswordPolicyHandler {
+	return &PasswordPolicyHandler{service: s, validator: v}
+}
+
+// RegisterRoutes registers the password policy routes on the authenticated router. Every member
+// can read the policy of the caller's tenant, e.g. to show it when changing a password; changing
+// it needs password_policy:manage.
+func (h *PasswordPolicyHandler) RegisterRoutes(router *mux.Router) {
+	manage := middleware.RequirePermission(domain.PermissionPasswordPolicyManage)
+	router.HandleFunc("/password-policy", h.GetPolicy).Methods("GET")
+	router.Handle("/password-policy", manage(http.HandlerFunc(h.UpdatePolicy))).Methods("PUT")
+}
+
+// GetPolicy handles the request to get the tenant's password policy.
+func (h *PasswordPolicyHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	policy, err := h.service.GetPolicy(r.Context(), tenantID)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, policy)
+}
+
+// UpdatePolicy handles the request to replace the tenant's password policy.
+func (h *PasswordPolicyHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	var req UpdatePasswordPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	policy, err := h.service.UpdatePolicy(r.Context(), tenantID, req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, policy)
+}
@@ -0,0 +1,27 @@
+package passwordpolicy
+
+// UpdatePasswordPolicyRequest is the DTO for replacing the tenant's password policy.
+type UpdatePasswordPolicyRequest struct {
+	MinLength        int  `json:"min_length"` // Between domain.PasswordMinLength and domain.PasswordMaxLength
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireDigit     bool `json:"require_digit"`
+	RequireSymbol    bool `json:"require_symbol"`
+	HistoryCount     int  `json:"history_count"` // Previous passwords that can't be reused, up to domain.PasswordHistoryMax; 0 allows reuse
+	CheckBreached    bool `json:"check_breached"`
+}
+
+// PasswordPolicyResponse is the DTO for responding with the tenant's password policy.
+type PasswordPolicyResponse struct {
+	MinLength        int  `json:"min_length"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireDigit     bool `json:"require_digit"`
+	RequireSymbol    bool `json:"require_symbol"`
+	HistoryCount     int  `json:"history_count"`
+	CheckBreached    bool `json:"check_breached"`
+	// BreachedListAvailable reports whether the server has a breached-password list; without one,
+	// check_breached has no effect.
+	BreachedListAvailable bool    `json:"breached_list_available"`
+	UpdatedAt             *string `json:"updated_at,omitempty"` // Unset while the tenant uses the default policy
+}
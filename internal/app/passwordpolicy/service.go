@@ -0,0 +1,198 @@
+package passwordpolicy
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/tenancy"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BreachedList tells whether a password is known from data breaches.
+type BreachedList interface {
+	Contains(password string) (bool, error)
+}
+
+// PasswordPolicyService manages the tenants' password policies and checks new passwords against
+// them. Services that set passwords call Check before hashing a new password and RecordPassword
+// once it is stored.
+type PasswordPolicyService struct {
+	policyRepo   domain.PasswordPolicyRepository
+	historyRepo  domain.PasswordHistoryRepository
+	breachedList BreachedList
+}
+
+// NewPasswordPolicyService creates a new instance of PasswordPolicyService. breachedList may be
+// nil, which disables the breached-password check.
+func NewPasswordPolicyService(policyRepo domain.PasswordPolicyRepository, historyRepo domain.PasswordHistoryRepository, breachedList BreachedList) *PasswordPolicyService {
+	return &PasswordPolicyService{
+		policyRepo:   policyRepo,
+		historyRepo:  historyRepo,
+		breachedList: breachedList,
+	}
+}
+
+// GetPolicy returns the tenant's password policy.
+func (s *PasswordPolicyService) GetPolicy(ctx context.Context, tenantID string) (*PasswordPolicyResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	policy, stored, err := s.findPolicy(ctx, parsedTenantID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err, "Internal error fetching password policy.")
+	}
+	return s.newPolicyResponse(policy, stored), nil
+}
+
+// UpdatePolicy replaces the tenant's password policy. It applies to passwords set from now on;
+// existing passwords stay valid.
+func (s *PasswordPolicyService) UpdatePolicy(ctx context.Context, tenantID string, req UpdatePasswordPolicyRequest) (*PasswordPolicyResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	if req.MinLength < domain.PasswordMinLength || req.MinLength > domain.PasswordMaxLength {
+		return nil, errors.NewBadRequest(fmt.Sprintf("min_length must be between %d and %d", domain.PasswordMinLength, domain.PasswordMaxLength), nil)
+	}
+	if req.HistoryCount < 0 || req.HistoryCount > domain.PasswordHistoryMax {
+		return nil, errors.NewBadRequest(fmt.Sprintf("history_count must be between 0 and %d", domain.PasswordHistoryMax), nil)
+	}
+	policy := &domain.PasswordPolicy{
+		TenantID:         parsedTenantID,
+		MinLength:        req.MinLength,
+		RequireUppercase: req.RequireUppercase,
+		RequireLowercase: req.RequireLowercase,
+		RequireDigit:     req.RequireDigit,
+		RequireSymbol:    req.RequireSymbol,
+		HistoryCount:     req.HistoryCount,
+		CheckBreached:    req.CheckBreached,
+		UpdatedAt:        time.Now(),
+	}
+	if err := s.policyRepo.Save(ctx, policy); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to save password policy: %w", err), "Internal error updating password policy.")
+	}
+	log.Infof(ctx, "PasswordPolicy: Policy of tenant %s updated", parsedTenantID)
+	return s.newPolicyResponse(policy, true), nil
+}
+
+// Check returns an error when the password doesn't meet the policy of the tenant. userID is the
+// account the password is for, whose previous passwords can't be reused; uuid.Nil for a new account.
+func (s *PasswordPolicyService) Check(ctx context.Context, tenantID, userID uuid.UUID, password string) error {
+	policy, _, err := s.findPolicy(ctx, tenantID)
+	if err != nil {
+		return errors.NewInternalServerError(err, "Internal error checking password.")
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r):
+			hasSymbol = true
+		}
+	}
+	var violations []string
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		violations = append(violations, fmt.Sprintf("be at least %d characters long", policy.MinLength))
+	}
+	if len(password) > domain.PasswordMaxLength {
+		violations = append(violations, fmt.Sprintf("be at most %d bytes long", domain.PasswordMaxLength))
+	}
+	if policy.RequireUppercase && !hasUpper {
+		violations = append(violations, "contain an uppercase letter")
+	}
+	if policy.RequireLowercase && !hasLower {
+		violations = append(violations, "contain a lowercase letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		violations = append(violations, "contain a digit")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		violations = append(violations, "contain a symbol")
+	}
+	if len(violations) > 0 {
+		return newPolicyViolationError(violations)
+	}
+
+	if policy.CheckBreached && s.breachedList != nil {
+		breached, err := s.breachedList.Contains(password)
+		if err != nil {
+			return errors.NewInternalServerError(fmt.Errorf("failed to check breached passwords: %w", err), "Internal error checking password.")
+		}
+		if breached {
+			return ErrPasswordBreached
+		}
+	}
+
+	if policy.HistoryCount > 0 && userID != uuid.Nil {
+		hashes, err := s.historyRepo.FindRecent(ctx, userID, policy.HistoryCount)
+		if err != nil {
+			return errors.NewInternalServerError(fmt.Errorf("failed to find password history: %w", err), "Internal error checking password.")
+		}
+		for _, hash := range hashes {
+			if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+				return ErrPasswordReused
+			}
+		}
+	}
+	return nil
+}
+
+// RecordPassword adds a password the user has set to their history. Callers store the password
+// and its history in one transaction, so a failure here must fail the password change as well.
+func (s *PasswordPolicyService) RecordPassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	if err := s.historyRepo.Add(ctx, userID, passwordHash, domain.PasswordHistoryMax); err != nil {
+		return fmt.Errorf("failed to record password history: %w", err)
+	}
+	return nil
+}
+
+// findPolicy returns the tenant's policy, or the default policy with stored set to false. A user
+// signed in to another tenant is still bound by their home tenant's policy, so the lookup is
+// scoped to the policy's tenant rather than the caller's.
+func (s *PasswordPolicyService) findPolicy(ctx context.Context, tenantID uuid.UUID) (*domain.PasswordPolicy, bool, error) {
+	if _, scoped := tenancy.TenantID(ctx); scoped {
+		ctx = tenancy.WithTenantID(ctx, tenantID.String())
+	}
+	policy, err := s.policyRepo.Find(ctx, tenantID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find password policy: %w", err)
+	}
+	if policy == nil {
+		return domain.DefaultPasswordPolicy(tenantID), false, nil
+	}
+	return policy, true, nil
+}
+
+func (s *PasswordPolicyService) newPolicyResponse(policy *domain.PasswordPolicy, stored bool) *PasswordPolicyResponse {
+	resp := &PasswordPolicyResponse{
+		MinLength:             policy.MinLength,
+		RequireUppercase:      policy.RequireUppercase,
+		RequireLowercase:      policy.RequireLowercase,
+		RequireDigit:          policy.RequireDigit,
+		RequireSymbol:         policy.RequireSymbol,
+		HistoryCount:          policy.HistoryCount,
+		CheckBreached:         policy.CheckBreached,
+		BreachedListAvailable: s.breachedList != nil,
+	}
+	if stored {
+		updatedAt := policy.UpdatedAt.Format(utils.ISO8601TimeFormat)
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
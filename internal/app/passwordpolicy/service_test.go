@@ -0,0 +1,131 @@
+package passwordpolicy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakePolicyRepo struct {
+	policy *domain.PasswordPolicy
+}
+
+func (r *fakePolicyRepo) Find(ctx context.Context, tenantID uuid.UUID) (*domain.PasswordPolicy, error) {
+	return r.policy, nil
+}
+
+func (r *fakePolicyRepo) Save(ctx context.Context, policy *domain.PasswordPolicy) error {
+	r.policy = policy
+	return nil
+}
+
+type fakeHistoryRepo struct {
+	hashes map[uuid.UUID][]string // Newest first
+}
+
+func (r *fakeHistoryRepo) Add(ctx context.Context, userID uuid.UUID, passwordHash string, keep int) error {
+	hashes := append([]string{passwordHash}, r.hashes[userID]...)
+	if len(hashes) > keep {
+		hashes = hashes[:keep]
+	}
+	r.hashes[userID] = hashes
+	return nil
+}
+
+func (r *fakeHistoryRepo) FindRecent(ctx context.Context, userID uuid.UUID, limit int) ([]string, error) {
+	hashes := r.hashes[userID]
+	if len(hashes) > limit {
+		hashes = hashes[:limit]
+	}
+	return hashes, nil
+}
+
+type fakeBreachedList map[string]bool
+
+func (l fakeBreachedList) Contains(password string) (bool, error) {
+	return l[password], nil
+}
+
+func TestCheck(t *testing.T) {
+	tenantID, userID := uuid.New(), uuid.New()
+	strict := &domain.PasswordPolicy{
+		TenantID:         tenantID,
+		MinLength:        12,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		HistoryCount:     2,
+		CheckBreached:    true,
+	}
+
+	tests := []struct {
+		name        string
+		policy      *domain.PasswordPolicy // nil for the default policy
+		userID      uuid.UUID
+		password    string
+		wantCode    string
+		wantMessage string
+	}{
+		{name: "default policy accepts 8 characters", password: "abcdefgh"},
+		{name: "default policy rejects 7 characters", password: "abcdefg", wantCode: "PASSWORD_POLICY_VIOLATION", wantMessage: "Password must be at least 8 characters long"},
+		{name: "length counts characters, not bytes", password: "ääääääää"},
+		{name: "longer than bcrypt accepts", password: strings.Repeat("a", 73), wantCode: "PASSWORD_POLICY_VIOLATION", wantMessage: "Password must be at most 72 bytes long"},
+		{name: "meets every rule", policy: strict, userID: userID, password: "Correct-Horse-9"},
+		{
+			name:        "lists every violated rule",
+			policy:      strict,
+			password:    "short",
+			wantCode:    "PASSWORD_POLICY_VIOLATION",
+			wantMessage: "Password must be at least 12 characters long, contain an uppercase letter, contain a digit and contain a symbol",
+		},
+		{name: "breached", policy: strict, password: "Password-123", wantCode: "PASSWORD_BREACHED"},
+		{name: "breached list ignored when the policy doesn't check it", password: "Password-123"},
+		{name: "reuses a recent password", policy: strict, userID: userID, password: "Previous-Pass-1", wantCode: "PASSWORD_REUSED"},
+		{name: "reuses a password older than the history count", policy: strict, userID: userID, password: "Oldest-Pass-3"},
+		{name: "history doesn't apply to new accounts", policy: strict, userID: uuid.Nil, password: "Previous-Pass-1"},
+	}
+
+	history := &fakeHistoryRepo{hashes: map[uuid.UUID][]string{}}
+	for _, password := range []string{"Oldest-Pass-3", "Older-Pass-2", "Previous-Pass-1"} {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		history.Add(context.Background(), userID, string(hash), domain.PasswordHistoryMax)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewPasswordPolicyService(&fakePolicyRepo{policy: tt.policy}, history, fakeBreachedList{"Password-123": true})
+			err := service.Check(context.Background(), tenantID, tt.userID, tt.password)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("Check(%q) error = %v, want nil", tt.password, err)
+				}
+				return
+			}
+			appErr, ok := err.(errors.AppError)
+			if !ok || appErr.Code() != tt.wantCode {
+				t.Fatalf("Check(%q) error = %v, want %s", tt.password, err, tt.wantCode)
+			}
+			if tt.wantMessage != "" && appErr.Message() != tt.wantMessage {
+				t.Errorf("Check(%q) message = %q, want %q", tt.password, appErr.Message(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestCheckWithoutBreachedList(t *testing.T) {
+	policy := &domain.PasswordPolicy{MinLength: domain.PasswordMinLength, CheckBreached: true}
+	service := NewPasswordPolicyService(&fakePolicyRepo{policy: policy}, &fakeHistoryRepo{}, nil)
+	if err := service.Check(context.Background(), uuid.New(), uuid.New(), "Password-123"); err != nil {
+		t.Errorf("Check() error = %v, want nil when no breached list is configured", err)
+	}
+}
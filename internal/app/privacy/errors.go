@@ -0,0 +1,20 @@
+package privacy
+
+import (
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/utils/errors"
+)
+
+// Module-specific custom errors for data subject requests.
+var (
+	ErrUserNotFound    = errors.New("USER_NOT_FOUND", "User with given ID not found in this tenant", http.StatusNotFound, nil, nil)
+	ErrRequestNotFound = errors.New("PRIVACY_REQUEST_NOT_FOUND", "Privacy request with given ID not found", http.StatusNotFound, nil, nil)
+	ErrExportNotReady  = errors.New("EXPORT_NOT_AVAILABLE", "The export is not ready yet, failed or has expired", http.StatusConflict, nil, nil)
+	ErrSelfErasure     = errors.New("USER_SELF_ACTION", "You can't erase your own account", http.StatusConflict, nil, nil)
+	ErrAlreadyErased   = errors.New("USER_ERASED", "This user's personal data has already been erased", http.StatusConflict, nil, nil)
+	ErrErasurePending  = errors.New("ERASURE_PENDING", "An erasure of this user is already in progress", http.StatusConflict, nil, nil)
+	// ErrManagedByHomeTenant is returned when an admin requests an export or erasure of a member from
+	// another tenant. The home tenant owns the account and everything stored about it.
+	ErrManagedByHomeTenant = errors.New("USER_MANAGED_ELSEWHERE", "Only this user's home tenant can export or erase their data", http.StatusConflict, nil, nil)
+)
@@ -0,0 +1,146 @@
+package privacy
+
+import (
+	"io"
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/app/auth"
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/http/middleware"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/gorilla/mux"
+)
+
+type PrivacyHandler struct {
+	service *PrivacyService
+}
+
+// NewPrivacyHandler creates a new instance of PrivacyHandler.
+func NewPrivacyHandler(s *PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{service: s}
+}
+
+// RegisterRoutes registers the data export and erasure routes on the authenticated router. Users
+// export their own data; exports and erasure of other users need users:write.
+func (h *PrivacyHandler) RegisterRoutes(router *mux.Router) {
+	write := middleware.RequirePermission(domain.PermissionUsersWrite)
+	router.HandleFunc("/user/me/data-export", h.RequestOwnExport).Methods("POST")
+	router.Handle("/users/{id}/data-export", write(http.HandlerFunc(h.RequestExport))).Methods("POST")
+	router.Handle("/users/{id}/erasure", write(http.HandlerFunc(h.RequestErasure))).Methods("POST")
+	router.HandleFunc("/privacy-requests/{id}", h.GetRequest).Methods("GET")
+	router.HandleFunc("/privacy-requests/{id}/download", h.DownloadExport).Methods("GET")
+}
+
+// RequestOwnExport handles the request to export the caller's own data.
+func (h *PrivacyHandler) RequestOwnExport(w http.ResponseWriter, r *http.Request) {
+	session, ok := auth.SessionFromContext(r)
+	if !ok {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	request, err := h.service.RequestOwnExport(r.Context(), session)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusAccepted, request)
+}
+
+// RequestExport handles the request to export a user's data.
+func (h *PrivacyHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	request, err := h.service.RequestExport(r.Context(), tenantID, callerID(r), mux.Vars(r)["id"])
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusAccepted, request)
+}
+
+// RequestErasure handles the request to erase a user's personal data.
+func (h *PrivacyHandler) RequestErasure(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	request, err := h.service.RequestErasure(r.Context(), tenantID, callerID(r), mux.Vars(r)["id"])
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusAccepted, request)
+}
+
+// GetRequest handles the request to check the progress of an export or erasure.
+func (h *PrivacyHandler) GetRequest(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+	canManage, err := middleware.HasPermission(r.Context(), domain.PermissionUsersWrite)
+	if err != nil {
+		utils.HandleHTTPError(w, errors.NewInternalServerError(err, "Failed to resolve permissions"), r)
+		return
+	}
+
+	request, err := h.service.GetRequest(r.Context(), tenantID, callerID(r), mux.Vars(r)["id"], canManage)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, request)
+}
+
+// DownloadExport handles the request to download a completed export as a ZIP file.
+func (h *PrivacyHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+	canManage, err := middleware.HasPermission(r.Context(), domain.PermissionUsersWrite)
+	if err != nil {
+		utils.HandleHTTPError(w, errors.NewInternalServerError(err, "Failed to resolve permissions"), r)
+		return
+	}
+
+	file, request, err := h.service.OpenExport(r.Context(), tenantID, callerID(r), mux.Vars(r)["id"], canManage)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="data-export-`+request.UserID.String()+`.zip"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, file); err != nil {
+		log.Warnf(r.Context(), "Privacy: Download of export %s interrupted: %v", request.ID, err)
+	}
+}
+
+// callerID returns the ID of the calling user, or "" for API keys, which act for no user.
+func callerID(r *http.Request) string {
+	if apiKeyID, _ := r.Context().Value(middleware.ContextKeyAPIKeyID).(string); apiKeyID != "" {
+		return ""
+	}
+	userID, _ := r.Context().Value(middleware.ContextKeyUserID).(string)
+	return userID
+}
@@ -0,0 +1,23 @@
+package privacy
+
+// PrivacyRequestResponse is the DTO for responding with a data export or erasure request.
+type PrivacyRequestResponse struct {
+	ID          string  `json:"id"`
+	UserID      string  `json:"user_id"` // Data subject
+	Type        string  `json:"type"`    // export or erasure
+	Status      string  `json:"status"`  // pending, processing, completed, failed or expired
+	RequestedBy *string `json:"requested_by,omitempty"`
+	DownloadURL *string `json:"download_url,omitempty"` // Set while a completed export can be downloaded
+	ExpiresAt   *string `json:"expires_at,omitempty"`   // When the export is deleted
+	CompletedAt *string `json:"completed_at,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+// exportManifest describes the files of an export bundle. It is written as manifest.json.
+type exportManifest struct {
+	RequestID   string   `json:"request_id"`
+	UserID      string   `json:"user_id"`
+	TenantID    string   `json:"tenant_id"`
+	GeneratedAt string   `json:"generated_at"`
+	Files       []string `json:"files"` // One JSON array of rows per source, e.g. user.json
+}
@@ -0,0 +1,463 @@
+package privacy
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/app/auth"
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/google/uuid"
+)
+
+// Dependencies are the collaborators PrivacyService needs.
+type Dependencies struct {
+	PrivacyRequestRepo domain.PrivacyRequestRepository
+	PersonalDataRepo   domain.PersonalDataRepository
+	AuditLogRepo       domain.AuditLogRepository
+	UserRepo           domain.UserRepository
+	MembershipRepo     domain.TenantMembershipRepository
+	AuthService        *auth.AuthService
+	Transactor         domain.Transactor
+}
+
+// Config holds the tunable settings of PrivacyService.
+type Config struct {
+	ExportDir    string        // Directory the export bundles are written to
+	ExportTTL    time.Duration // Time an export can be downloaded before its bundle is deleted
+	ClaimTimeout time.Duration // Time after which a request still processing is claimed again
+}
+
+// PrivacyService handles data subject requests: exports of everything stored about a user and
+// erasure of a user's personal data. Requests are queued and processed by the worker started
+// with StartWorker.
+type PrivacyService struct {
+	requestRepo      domain.PrivacyRequestRepository
+	personalDataRepo domain.PersonalDataRepository
+	auditLogRepo     domain.AuditLogRepository
+	userRepo         domain.UserRepository
+	membershipRepo   domain.TenantMembershipRepository
+	authService      *auth.AuthService
+	transactor       domain.Transactor
+	cfg              Config
+}
+
+// NewPrivacyService creates a new instance of PrivacyService.
+func NewPrivacyService(deps Dependencies, cfg Config) *PrivacyService {
+	return &PrivacyService{
+		requestRepo:      deps.PrivacyRequestRepo,
+		personalDataRepo: deps.PersonalDataRepo,
+		auditLogRepo:     deps.AuditLogRepo,
+		userRepo:         deps.UserRepo,
+		membershipRepo:   deps.MembershipRepo,
+		authService:      deps.AuthService,
+		transactor:       deps.Transactor,
+		cfg:              cfg,
+	}
+}
+
+// RequestOwnExport queues an export of the caller's own data.
+func (s *PrivacyService) RequestOwnExport(ctx context.Context, session auth.SessionInfo) (*PrivacyRequestResponse, error) {
+	return s.queue(ctx, session.TenantID, session.UserID, session.UserID, domain.PrivacyRequestExport)
+}
+
+// RequestExport queues an export of a home user's data on behalf of the tenant.
+func (s *PrivacyService) RequestExport(ctx context.Context, tenantID, callerID, id string) (*PrivacyRequestResponse, error) {
+	user, err := s.findHomeUser(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.queue(ctx, tenantID, callerID, user.ID.String(), domain.PrivacyRequestExport)
+}
+
+// RequestErasure queues the erasure of a home user's personal data. Erasure can't be undone: the
+// account is deleted for good and its name, email and phone number are replaced. Deleted users can
+// be erased too. Callers can't erase themselves.
+func (s *PrivacyService) RequestErasure(ctx context.Context, tenantID, callerID, id string) (*PrivacyRequestResponse, error) {
+	user, err := s.findHomeUser(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID.String() == callerID {
+		return nil, ErrSelfErasure
+	}
+	if user.IsErased() {
+		return nil, ErrAlreadyErased
+	}
+	requests, err := s.requestRepo.FindByUser(ctx, user.ID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find privacy requests: %w", err), "Internal error requesting erasure.")
+	}
+	for _, request := range requests {
+		if request.Type == domain.PrivacyRequestErasure && (request.Status == domain.PrivacyRequestPending || request.Status == domain.PrivacyRequestProcessing) {
+			return nil, ErrErasurePending
+		}
+	}
+	return s.queue(ctx, tenantID, callerID, user.ID.String(), domain.PrivacyRequestErasure)
+}
+
+// GetRequest returns a request of the tenant. Only its subject, whoever made it and, with canManage,
+// administrators of the tenant's users can see it.
+func (s *PrivacyService) GetRequest(ctx context.Context, tenantID, callerID, id string, canManage bool) (*PrivacyRequestResponse, error) {
+	request, err := s.findRequest(ctx, tenantID, callerID, id, canManage)
+	if err != nil {
+		return nil, err
+	}
+	resp := newPrivacyRequestResponse(request)
+	return &resp, nil
+}
+
+// OpenExport opens the bundle of a completed export for download, with the same access rules as
+// GetRequest. The caller must close the file.
+func (s *PrivacyService) OpenExport(ctx context.Context, tenantID, callerID, id string, canManage bool) (*os.File, *domain.PrivacyRequest, error) {
+	request, err := s.findRequest(ctx, tenantID, callerID, id, canManage)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !request.IsDownloadable(time.Now()) {
+		return nil, nil, ErrExportNotReady
+	}
+	file, err := os.Open(*request.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrExportNotReady
+		}
+		return nil, nil, errors.NewInternalServerError(fmt.Errorf("failed to open export: %w", err), "Internal error downloading export.")
+	}
+	log.Infof(ctx, "Privacy: Export %s of user %s downloaded by %s", request.ID, request.UserID, callerID)
+	return file, request, nil
+}
+
+// StartWorker processes queued requests and deletes expired exports every interval until ctx is
+// cancelled. Several instances can run workers; each request is claimed by one of them.
+func (s *PrivacyService) StartWorker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.processPending(ctx)
+				s.purgeExpiredExports(ctx)
+			}
+		}
+	}()
+}
+
+// processPending processes queued requests until none is left.
+func (s *PrivacyService) processPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		request, err := s.requestRepo.ClaimNext(ctx, time.Now().Add(-s.cfg.ClaimTimeout))
+		if err != nil {
+			log.Errorf(ctx, "Privacy: Failed to claim request: %v", err)
+			return
+		}
+		if request == nil {
+			return
+		}
+
+		switch request.Type {
+		case domain.PrivacyRequestExport:
+			err = s.export(ctx, request)
+		case domain.PrivacyRequestErasure:
+			err = s.erase(ctx, request)
+		default:
+			err = fmt.Errorf("unknown request type %q", request.Type)
+		}
+		if err != nil {
+			log.Errorf(ctx, "Privacy: %s request %s failed: %v", request.Type, request.ID, err)
+			// Recorded even when the failure is the worker being stopped.
+			if err := s.requestRepo.Fail(context.WithoutCancel(ctx), request.ID, err.Error()); err != nil {
+				log.Errorf(ctx, "Privacy: Failed to mark request %s as failed: %v", request.ID, err)
+			}
+			continue
+		}
+		log.Infof(ctx, "Privacy: Completed %s request %s for user %s", request.Type, request.ID, request.UserID)
+	}
+}
+
+// export writes a ZIP bundle with a JSON file per source of personal data and a manifest.
+func (s *PrivacyService) export(ctx context.Context, request *domain.PrivacyRequest) error {
+	user, err := s.userRepo.FindByIDIncludingDeleted(ctx, request.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return fmt.Errorf("user %s not found", request.UserID)
+	}
+	data, err := s.personalDataRepo.Collect(ctx, user)
+	if err != nil {
+		return fmt.Errorf("failed to collect personal data: %w", err)
+	}
+
+	if err := os.MkdirAll(s.cfg.ExportDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	path := filepath.Join(s.cfg.ExportDir, request.ID.String()+".zip")
+	// A claim taken over from a stopped worker may have left a partial bundle behind.
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete partial export: %w", err)
+	}
+	if err := writeBundle(path, request, data); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	expiresAt := time.Now().Add(s.cfg.ExportTTL)
+	if err := s.requestRepo.Complete(ctx, request.ID, &path, &expiresAt); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to complete request: %w", err)
+	}
+	return nil
+}
+
+func writeBundle(path string, request *domain.PrivacyRequest, data map[string]json.RawMessage) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer file.Close()
+
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifest := exportManifest{
+		RequestID:   request.ID.String(),
+		UserID:      request.UserID.String(),
+		TenantID:    request.TenantID.String(),
+		GeneratedAt: time.Now().Format(utils.ISO8601TimeFormat),
+	}
+	files := make(map[string]interface{}, len(names)+1)
+	for _, name := range names {
+		manifest.Files = append(manifest.Files, name+".json")
+		files[name+".json"] = data[name]
+	}
+	files["manifest.json"] = manifest
+
+	archive := zip.NewWriter(file)
+	for _, name := range append([]string{"manifest.json"}, manifest.Files...) {
+		content, err := json.MarshalIndent(files[name], "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		w, err := archive.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to export: %w", name, err)
+		}
+		if _, err := w.Write(content); err != nil {
+			return fmt.Errorf("failed to write %s to export: %w", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finish export: %w", err)
+	}
+	return file.Close()
+}
+
+// erase ends the user's sessions, deletes their export bundles, then anonymizes their data and
+// records the erasure in the audit log in one transaction, so every erasure has its audit entry.
+func (s *PrivacyService) erase(ctx context.Context, request *domain.PrivacyRequest) error {
+	user, err := s.userRepo.FindByIDIncludingDeleted(ctx, request.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return fmt.Errorf("user %s not found", request.UserID)
+	}
+
+	if !user.IsErased() {
+		if err := s.authService.RevokeAllSessions(ctx, user.ID); err != nil {
+			return err
+		}
+		if err := s.deleteExports(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	entry := &domain.AuditLog{
+		ID:         uuid.New(),
+		TenantID:   request.TenantID,
+		ActorID:    request.RequestedBy,
+		Action:     domain.AuditActionUserErased,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID,
+		Details:    domain.AuditDetails{"privacy_request_id": request.ID.String()},
+		CreatedAt:  time.Now(),
+	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if !user.IsErased() {
+			if err := s.personalDataRepo.Erase(ctx, user); err != nil {
+				return fmt.Errorf("failed to erase personal data: %w", err)
+			}
+		}
+		if err := s.auditLogRepo.Save(ctx, entry); err != nil {
+			return fmt.Errorf("failed to write audit log: %w", err)
+		}
+		if err := s.requestRepo.Complete(ctx, request.ID, nil, nil); err != nil {
+			return fmt.Errorf("failed to complete request: %w", err)
+		}
+		return nil
+	})
+}
+
+// deleteExports deletes the bundles of the user's completed exports, which hold the erased data.
+func (s *PrivacyService) deleteExports(ctx context.Context, userID uuid.UUID) error {
+	requests, err := s.requestRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to find exports: %w", err)
+	}
+	for i := range requests {
+		if requests[i].Type == domain.PrivacyRequestExport && requests[i].Status == domain.PrivacyRequestCompleted {
+			if err := s.expire(ctx, &requests[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// purgeExpiredExports deletes the bundles of exports that can no longer be downloaded.
+func (s *PrivacyService) purgeExpiredExports(ctx context.Context) {
+	requests, err := s.requestRepo.FindExpiredExports(ctx, time.Now())
+	if err != nil {
+		log.Errorf(ctx, "Privacy: Failed to find expired exports: %v", err)
+		return
+	}
+	for i := range requests {
+		if err := s.expire(ctx, &requests[i]); err != nil {
+			log.Errorf(ctx, "Privacy: Failed to delete export %s: %v", requests[i].ID, err)
+		}
+	}
+}
+
+func (s *PrivacyService) expire(ctx context.Context, request *domain.PrivacyRequest) error {
+	if request.FilePath != nil {
+		if err := os.Remove(*request.FilePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete export file: %w", err)
+		}
+	}
+	if err := s.requestRepo.MarkExpired(ctx, request.ID); err != nil {
+		return fmt.Errorf("failed to mark export as expired: %w", err)
+	}
+	return nil
+}
+
+func (s *PrivacyService) queue(ctx context.Context, tenantID, callerID, userID, requestType string) (*PrivacyRequestResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	request := &domain.PrivacyRequest{
+		TenantID: parsedTenantID,
+		UserID:   parsedUserID,
+		Type:     requestType,
+		Status:   domain.PrivacyRequestPending,
+	}
+	// API keys have no user to record as the requester.
+	if parsedCallerID, err := uuid.Parse(callerID); err == nil {
+		request.RequestedBy = uuid.NullUUID{UUID: parsedCallerID, Valid: true}
+	}
+	request.GenerateID()
+
+	if err := s.requestRepo.Save(ctx, request); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to save privacy request: %w", err), "Internal error creating privacy request.")
+	}
+	log.Infof(ctx, "Privacy: Queued %s request %s for user %s", requestType, request.ID, parsedUserID)
+	resp := newPrivacyRequestResponse(request)
+	return &resp, nil
+}
+
+// findHomeUser returns a user the tenant owns, including deleted users.
+func (s *PrivacyService) findHomeUser(ctx context.Context, tenantID, id string) (*domain.User, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid user ID format (must be UUID)", nil)
+	}
+	user, err := s.userRepo.FindByIDIncludingDeleted(ctx, parsedID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find user: %w", err), "Internal error fetching user.")
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.TenantID != parsedTenantID {
+		membership, err := s.membershipRepo.Find(ctx, user.ID, parsedTenantID)
+		if err != nil {
+			return nil, errors.NewInternalServerError(fmt.Errorf("failed to find membership: %w", err), "Internal error fetching user.")
+		}
+		if membership == nil || user.IsDeleted() {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrManagedByHomeTenant
+	}
+	return user, nil
+}
+
+func (s *PrivacyService) findRequest(ctx context.Context, tenantID, callerID, id string, canManage bool) (*domain.PrivacyRequest, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid privacy request ID format (must be UUID)", nil)
+	}
+	request, err := s.requestRepo.FindByID(ctx, parsedTenantID, parsedID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find privacy request: %w", err), "Internal error fetching privacy request.")
+	}
+	if request == nil {
+		return nil, ErrRequestNotFound
+	}
+	isRequester := request.RequestedBy.Valid && request.RequestedBy.UUID.String() == callerID
+	if !canManage && !isRequester && request.UserID.String() != callerID {
+		return nil, ErrRequestNotFound
+	}
+	return request, nil
+}
+
+func newPrivacyRequestResponse(request *domain.PrivacyRequest) PrivacyRequestResponse {
+	resp := PrivacyRequestResponse{
+		ID:          request.ID.String(),
+		UserID:      request.UserID.String(),
+		Type:        request.Type,
+		Status:      request.Status,
+		ExpiresAt:   utils.FormatOptionalTime(request.ExpiresAt),
+		CompletedAt: utils.FormatOptionalTime(request.CompletedAt),
+		CreatedAt:   request.CreatedAt.Format(utils.ISO8601TimeFormat),
+	}
+	if request.RequestedBy.Valid {
+		requestedBy := request.RequestedBy.UUID.String()
+		resp.RequestedBy = &requestedBy
+	}
+	if request.IsDownloadable(time.Now()) {
+		downloadURL := "/api/v1/privacy-requests/" + request.ID.String() + "/download"
+		resp.DownloadURL = &downloadURL
+	}
+	return resp
+}
@@ -0,0 +1,14 @@
+package profile
+
+import (
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/utils/errors"
+)
+
+// Module-specific custom errors for the caller's own profile.
+var (
+	ErrInvalidCurrentPassword = errors.New("INVALID_CURRENT_PASSWORD", "Current password is incorrect", http.StatusBadRequest, nil, nil)
+	// ErrNoPassword is returned for accounts that sign in through SSO only; they set a password with the reset flow.
+	ErrNoPassword = errors.New("PASSWORD_NOT_SET", "This account has no password yet, use the password reset flow to set one", http.StatusConflict, nil, nil)
+)
@@ -0,0 +1,103 @@
+package profile
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/app/auth"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type ProfileHandler struct {
+	service   *ProfileService
+	validator *validator.Validate
+}
+
+// NewProfileHandler creates a new instance of ProfileHandler.
+func NewProfileHandler(s *ProfileService, v *validator.Validate) *ProfileHandler {
+	return &ProfileHandler{service: s, validator: v}
+}
+
+// RegisterRoutes registers the profile routes on the authenticated router. They act on the user of
+// the presented access token; API keys have no profile.
+func (h *ProfileHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/user/me", h.GetProfile).Methods("GET")
+	router.HandleFunc("/user/me", h.UpdateProfile).Methods("PATCH")
+	router.HandleFunc("/user/me/password", h.ChangePassword).Methods("POST")
+}
+
+// GetProfile handles the request to get the caller's profile.
+func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	session, ok := auth.SessionFromContext(r)
+	if !ok {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	profile, err := h.service.GetProfile(r.Context(), session)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, profile)
+}
+
+// UpdateProfile handles the request to update the caller's name or phone number.
+func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	session, ok := auth.SessionFromContext(r)
+	if !ok {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	profile, err := h.service.UpdateProfile(r.Context(), session, req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, profile)
+}
+
+// ChangePassword handles the request to change the caller's password.
+func (h *ProfileHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	session, ok := auth.SessionFromContext(r)
+	if !ok {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	if err := h.service.ChangePassword(r.Context(), session, req, utils.ClientIP(r)); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
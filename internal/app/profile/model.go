@@ -0,0 +1,28 @@
+package profile
+
+// UpdateProfileRequest is the DTO for updating the caller's profile. Omitted fields are left unchanged.
+type UpdateProfileRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=255"`
+	PhoneNumber *string `json:"phone_number" validate:"omitempty,max=50"`
+}
+
+// ChangePasswordRequest is the DTO for changing the caller's password.
+type ChangePasswordRequest struct {
+	CurrentPassword     string `json:"current_password" validate:"required"`
+	NewPassword         string `json:"new_password" validate:"required"` // Checked against the home tenant's password policy
+	RevokeOtherSessions bool   `json:"revoke_other_sessions"`            // End every other session of the user
+}
+
+// ProfileResponse is the DTO for responding with the caller's stored profile.
+type ProfileResponse struct {
+	ID            string `json:"id"`
+	TenantID      string `json:"tenant_id"`      // Tenant of the current session
+	HomeTenantID  string `json:"home_tenant_id"` // Tenant that owns the account
+	Email         string `json:"email"`
+	Name          string `json:"name"`
+	PhoneNumber   string `json:"phone_number"`
+	Role          string `json:"role"` // Role in the current tenant
+	EmailVerified bool   `json:"email_verified"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
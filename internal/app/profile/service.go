@@ -0,0 +1,154 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/app/auth"
+	"starterpack-golang-cleanarch/internal/app/passwordpolicy"
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ProfileService lets users read and update their own account.
+type ProfileService struct {
+	userRepo       domain.UserRepository
+	membershipRepo domain.TenantMembershipRepository
+	authService    *auth.AuthService
+	passwordPolicy *passwordpolicy.PasswordPolicyService
+	transactor     domain.Transactor
+}
+
+// NewProfileService creates a new instance of ProfileService.
+func NewProfileService(userRepo domain.UserRepository, membershipRepo domain.TenantMembershipRepository, authService *auth.AuthService, passwordPolicy *passwordpolicy.PasswordPolicyService, transactor domain.Transactor) *ProfileService {
+	return &ProfileService{
+		userRepo:       userRepo,
+		membershipRepo: membershipRepo,
+		authService:    authService,
+		passwordPolicy: passwordPolicy,
+		transactor:     transactor,
+	}
+}
+
+// GetProfile returns the caller's stored profile, with their role in the session's tenant.
+func (s *ProfileService) GetProfile(ctx context.Context, session auth.SessionInfo) (*ProfileResponse, error) {
+	user, err := s.findUser(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	return s.newProfileResponse(ctx, session, user)
+}
+
+// UpdateProfile changes the caller's name or phone number.
+func (s *ProfileService) UpdateProfile(ctx context.Context, session auth.SessionInfo, req UpdateProfileRequest) (*ProfileResponse, error) {
+	user, err := s.findUser(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.PhoneNumber != nil {
+		user.PhoneNumber = *req.PhoneNumber
+	}
+	user.UpdateTimestamp()
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to update user: %w", err), "Internal error updating profile.")
+	}
+	return s.newProfileResponse(ctx, session, user)
+}
+
+// ChangePassword replaces the caller's password after checking the current one, which is
+// throttled like a login. The new password must meet the password policy of the user's home
+// tenant. Pending password reset links stop working. Other sessions are ended when requested; the
+// current session stays signed in.
+func (s *ProfileService) ChangePassword(ctx context.Context, session auth.SessionInfo, req ChangePasswordRequest, clientIP string) error {
+	user, err := s.findUser(ctx, session)
+	if err != nil {
+		return err
+	}
+	if user.PasswordHash == "" {
+		return ErrNoPassword
+	}
+	if err := s.authService.VerifyPassword(ctx, user, req.CurrentPassword, clientIP, ErrInvalidCurrentPassword); err != nil {
+		return err
+	}
+	if err := s.passwordPolicy.Check(ctx, user.TenantID, user.ID, req.NewPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.NewInternalServerError(fmt.Errorf("failed to hash password: %w", err), "Internal error during password hashing.")
+	}
+	user.PasswordHash = string(hashedPassword)
+	user.UpdateTimestamp()
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return errors.NewInternalServerError(fmt.Errorf("failed to update password: %w", err), "Internal error changing password.")
+		}
+		if err := s.passwordPolicy.RecordPassword(ctx, user.ID, user.PasswordHash); err != nil {
+			return errors.NewInternalServerError(err, "Internal error changing password.")
+		}
+		return s.authService.InvalidatePasswordResets(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if req.RevokeOtherSessions {
+		if err := s.authService.RevokeOtherSessions(ctx, session); err != nil {
+			return errors.NewInternalServerError(err, "Internal error changing password.")
+		}
+		log.Infof(ctx, "Profile: Password changed for user %s, other sessions revoked", user.ID)
+		return nil
+	}
+	log.Infof(ctx, "Profile: Password changed for user %s", user.ID)
+	return nil
+}
+
+func (s *ProfileService) findUser(ctx context.Context, session auth.SessionInfo) (*domain.User, error) {
+	userID, err := uuid.Parse(session.UserID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find user: %w", err), "Internal error fetching profile.")
+	}
+	if user == nil {
+		return nil, errors.ErrUnauthorized
+	}
+	return user, nil
+}
+
+func (s *ProfileService) newProfileResponse(ctx context.Context, session auth.SessionInfo, user *domain.User) (*ProfileResponse, error) {
+	resp := &ProfileResponse{
+		ID:            user.ID.String(),
+		TenantID:      session.TenantID,
+		HomeTenantID:  user.TenantID.String(),
+		Email:         user.Email,
+		Name:          user.Name,
+		PhoneNumber:   user.PhoneNumber,
+		Role:          user.Role,
+		EmailVerified: user.IsEmailVerified(),
+		CreatedAt:     user.CreatedAt.Format(utils.ISO8601TimeFormat),
+		UpdatedAt:     user.UpdatedAt.Format(utils.ISO8601TimeFormat),
+	}
+	if tenantID, err := uuid.Parse(session.TenantID); err == nil {
+		membership, err := s.membershipRepo.Find(ctx, user.ID, tenantID)
+		if err != nil {
+			return nil, errors.NewInternalServerError(fmt.Errorf("failed to find tenant membership: %w", err), "Internal error fetching profile.")
+		}
+		if membership != nil {
+			resp.Role = membership.Role
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,15 @@
+package rbac
+
+import (
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/utils/errors"
+)
+
+// Module-specific custom errors for role-based access control.
+var (
+	ErrRoleNotFound = errors.New("ROLE_NOT_FOUND", "Role with given name not found", http.StatusNotFound, nil, nil)
+	ErrUserNotFound = errors.New("USER_NOT_FOUND", "User with given ID not found in this tenant", http.StatusNotFound, nil, nil)
+	// ErrRoleNotAssignable is returned for platform roles, which span tenants and are granted in the database.
+	ErrRoleNotAssignable = errors.New("ROLE_NOT_ASSIGNABLE", "This role can't be assigned or removed through the API", http.StatusForbidden, nil, nil)
+)
@@ -0,0 +1,112 @@
+package rbac
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/http/middleware"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type RBACHandler struct {
+	service   *RBACService
+	validator *validator.Validate
+}
+
+// NewRBACHandler creates a new instance of RBACHandler.
+func NewRBACHandler(s *RBACService, v *validator.Validate) *RBACHandler {
+	return &RBACHandler{service: s, validator: v}
+}
+
+// RegisterRoutes registers the role administration routes. They must be registered on the
+// authenticated router, as each route declares the permission it needs.
+func (h *RBACHandler) RegisterRoutes(router *mux.Router) {
+	router.Handle("/roles", middleware.RequirePermission(domain.PermissionRolesRead)(http.HandlerFunc(h.GetRoles))).Methods("GET")
+	router.Handle("/users/{id}/roles", middleware.RequirePermission(domain.PermissionRolesRead)(http.HandlerFunc(h.GetUserRoles))).Methods("GET")
+	router.Handle("/users/{id}/roles", middleware.RequirePermission(domain.PermissionRolesAssign)(http.HandlerFunc(h.AssignRole))).Methods("POST")
+	router.Handle("/users/{id}/roles/{role}", middleware.RequirePermission(domain.PermissionRolesAssign)(http.HandlerFunc(h.RemoveRole))).Methods("DELETE")
+}
+
+// GetRoles handles the request to list the roles available in the caller's tenant.
+func (h *RBACHandler) GetRoles(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	roles, err := h.service.GetRoles(r.Context(), tenantID)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, roles)
+}
+
+// GetUserRoles handles the request to list the roles of a user.
+func (h *RBACHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	roles, err := h.service.GetUserRoles(r.Context(), tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, roles)
+}
+
+// AssignRole handles the request to grant a role to a user.
+func (h *RBACHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	var req AssignRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	roles, err := h.service.AssignRole(r.Context(), tenantID, mux.Vars(r)["id"], req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, roles)
+}
+
+// RemoveRole handles the request to take a role away from a user.
+func (h *RBACHandler) RemoveRole(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	vars := mux.Vars(r)
+	if err := h.service.RemoveRole(r.Context(), tenantID, vars["id"], vars["role"]); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
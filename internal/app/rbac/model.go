@@ -0,0 +1,15 @@
+package rbac
+
+// AssignRoleRequest is the DTO for granting a role to a user.
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required,max=50"`
+}
+
+// RoleResponse is the DTO for responding with role details.
+type RoleResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	BuiltIn     bool     `json:"built_in"` // Built-in roles are shared by every tenant
+	Permissions []string `json:"permissions"`
+}
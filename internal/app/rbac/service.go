@@ -0,0 +1,141 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/google/uuid"
+)
+
+type RBACService struct {
+	roleRepo       domain.RoleRepository
+	membershipRepo domain.TenantMembershipRepository
+}
+
+// NewRBACService creates a new instance of RBACService.
+func NewRBACService(roleRepo domain.RoleRepository, membershipRepo domain.TenantMembershipRepository) *RBACService {
+	return &RBACService{roleRepo: roleRepo, membershipRepo: membershipRepo}
+}
+
+// ResolvePermissions implements middleware.PermissionResolver.
+func (s *RBACService) ResolvePermissions(ctx context.Context, tenantID, userID string) ([]string, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant ID %q: %w", tenantID, err)
+	}
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID %q: %w", userID, err)
+	}
+	return s.roleRepo.FindPermissionsByUser(ctx, parsedTenantID, parsedUserID)
+}
+
+// GetRoles lists the roles that can be assigned within the tenant.
+func (s *RBACService) GetRoles(ctx context.Context, tenantID string) ([]RoleResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	roles, err := s.roleRepo.FindAll(ctx, parsedTenantID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to fetch roles: %w", err), "Internal error fetching roles.")
+	}
+	return newRoleResponses(roles), nil
+}
+
+// GetUserRoles lists the roles granted to a user of the tenant.
+func (s *RBACService) GetUserRoles(ctx context.Context, tenantID, userID string) ([]RoleResponse, error) {
+	parsedTenantID, parsedUserID, err := s.findTenantUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.roleRepo.FindByUser(ctx, parsedTenantID, parsedUserID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to fetch user roles: %w", err), "Internal error fetching user roles.")
+	}
+	return newRoleResponses(roles), nil
+}
+
+// AssignRole grants a role to a user of the tenant and returns the user's roles.
+func (s *RBACService) AssignRole(ctx context.Context, tenantID, userID string, req AssignRoleRequest) ([]RoleResponse, error) {
+	parsedTenantID, parsedUserID, err := s.findTenantUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.findRole(ctx, parsedTenantID, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.AssignToUser(ctx, parsedTenantID, parsedUserID, role.ID); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to assign role: %w", err), "Internal error assigning role.")
+	}
+	return s.GetUserRoles(ctx, tenantID, userID)
+}
+
+// RemoveRole takes a role away from a user of the tenant.
+func (s *RBACService) RemoveRole(ctx context.Context, tenantID, userID, roleName string) error {
+	parsedTenantID, parsedUserID, err := s.findTenantUser(ctx, tenantID, userID)
+	if err != nil {
+		return err
+	}
+	role, err := s.findRole(ctx, parsedTenantID, roleName)
+	if err != nil {
+		return err
+	}
+	if err := s.roleRepo.RemoveFromUser(ctx, parsedTenantID, parsedUserID, role.ID); err != nil {
+		return errors.NewInternalServerError(fmt.Errorf("failed to remove role: %w", err), "Internal error removing role.")
+	}
+	return nil
+}
+
+// findTenantUser parses the IDs and makes sure the user belongs to the tenant, so admins can't
+// manage roles of users in other tenants.
+func (s *RBACService) findTenantUser(ctx context.Context, tenantID, userID string) (uuid.UUID, uuid.UUID, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.ErrUnauthorized
+	}
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.NewBadRequest("Invalid user ID format (must be UUID)", nil)
+	}
+	membership, err := s.membershipRepo.Find(ctx, parsedUserID, parsedTenantID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.NewInternalServerError(fmt.Errorf("failed to find tenant membership: %w", err), "Internal error fetching user.")
+	}
+	if membership == nil {
+		return uuid.Nil, uuid.Nil, ErrUserNotFound
+	}
+	return parsedTenantID, parsedUserID, nil
+}
+
+func (s *RBACService) findRole(ctx context.Context, tenantID uuid.UUID, name string) (*domain.Role, error) {
+	if name == domain.RolePlatformAdmin {
+		return nil, ErrRoleNotAssignable
+	}
+	role, err := s.roleRepo.FindByName(ctx, tenantID, name)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find role: %w", err), "Internal error fetching role.")
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+	return role, nil
+}
+
+func newRoleResponses(roles []*domain.Role) []RoleResponse {
+	responses := make([]RoleResponse, len(roles))
+	for i, role := range roles {
+		responses[i] = RoleResponse{
+			ID:          role.ID.String(),
+			Name:        role.Name,
+			Description: role.Description,
+			BuiltIn:     !role.TenantID.Valid,
+			Permissions: role.Permissions,
+		}
+	}
+	return responses
+}
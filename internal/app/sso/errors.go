@@ -0,0 +1,16 @@
+package sso
+
+import (
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/utils/errors"
+)
+
+// Module-specific custom errors for single sign-on.
+var (
+	ErrProviderNotFound = errors.New("SSO_PROVIDER_NOT_FOUND", "Identity provider not found", http.StatusNotFound, nil, nil)
+	ErrInvalidState     = errors.New("SSO_INVALID_STATE", "Login session is invalid or expired, please start the login again", http.StatusBadRequest, nil, nil)
+	ErrLoginFailed      = errors.New("SSO_LOGIN_FAILED", "Login with the identity provider failed", http.StatusUnauthorized, nil, nil)
+	ErrAccountNotFound  = errors.New("SSO_ACCOUNT_NOT_FOUND", "No account is linked to this identity", http.StatusForbidden, nil, nil)
+	ErrEmailNotVerified = errors.New("SSO_EMAIL_NOT_VERIFIED", "The identity provider did not verify this email address, so it can't be linked to an existing account", http.StatusForbidden, nil, nil)
+)
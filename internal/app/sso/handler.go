@@ -0,0 +1,91 @@
+package sso
+
+import (
+	"net/http"
+	"strings"
+
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/gorilla/mux"
+)
+
+// flowCookieName is the cookie holding the signed flow state between login and callback.
+const flowCookieName = "sso_flow"
+
+type SSOHandler struct {
+	service *SSOService
+}
+
+// NewSSOHandler creates a new instance of SSOHandler.
+func NewSSOHandler(s *SSOService) *SSOHandler {
+	return &SSOHandler{service: s}
+}
+
+// RegisterRoutes registers the public SSO routes.
+func (h *SSOHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/auth/sso/providers", h.GetProviders).Methods("GET")
+	router.HandleFunc("/auth/sso/{provider}/login", h.Login).Methods("GET")
+	router.HandleFunc("/auth/sso/{provider}/callback", h.Callback).Methods("GET")
+}
+
+// GetProviders handles the request to list the identity providers users can sign in with.
+func (h *SSOHandler) GetProviders(w http.ResponseWriter, r *http.Request) {
+	utils.RespondJSON(w, http.StatusOK, h.service.GetProviders())
+}
+
+// Login handles the request to start a login: it stores the flow state in a cookie and
+// redirects the browser to the identity provider.
+func (h *SSOHandler) Login(w http.ResponseWriter, r *http.Request) {
+	redirectURL, flowToken, err := h.service.StartLogin(r.Context(), mux.Vars(r)["provider"])
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     flowCookieName,
+		Value:    flowToken,
+		Path:     "/auth/sso/",
+		MaxAge:   int(flowTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   isSecureRequest(r),
+		SameSite: http.SameSiteLaxMode, // Sent on the top-level redirect back from the provider
+	})
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+}
+
+// Callback handles the redirect back from the identity provider and responds with the tokens.
+func (h *SSOHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	// The flow cookie is single-use, whatever the outcome.
+	http.SetCookie(w, &http.Cookie{Name: flowCookieName, Path: "/auth/sso/", MaxAge: -1, HttpOnly: true, Secure: isSecureRequest(r)})
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		log.Warnf(r.Context(), "SSO: Provider returned error %q: %s", providerErr, query.Get("error_description"))
+		utils.HandleHTTPError(w, errors.New(ErrLoginFailed.Code(), ErrLoginFailed.Message(), ErrLoginFailed.Status(), nil, map[string]interface{}{"error": providerErr}), r)
+		return
+	}
+	if query.Get("code") == "" || query.Get("state") == "" {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Missing code or state", nil), r)
+		return
+	}
+
+	var flowToken string
+	if cookie, err := r.Cookie(flowCookieName); err == nil {
+		flowToken = cookie.Value
+	}
+
+	authResp, err := h.service.CompleteLogin(r.Context(), mux.Vars(r)["provider"], flowToken, query.Get("state"), query.Get("code"))
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, authResp)
+}
+
+func isSecureRequest(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
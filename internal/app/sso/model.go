@@ -0,0 +1,22 @@
+package sso
+
+import (
+	"starterpack-golang-cleanarch/internal/platform/oidc"
+)
+
+// ProviderConfig configures an OpenID Connect provider users can sign in with. Providers are
+// read from the JSON file named by OIDC_PROVIDERS_FILE, as an array of these objects.
+type ProviderConfig struct {
+	Name        string `json:"name"`         // URL-safe name used in the login routes, e.g. "google"
+	DisplayName string `json:"display_name"` // Label for the login button
+	oidc.Config
+	TenantID        string `json:"tenant_id"`         // Tenant that users of this provider belong to
+	AutoCreateUsers bool   `json:"auto_create_users"` // Create a user on first login instead of refusing unknown identities
+	DefaultRole     string `json:"default_role"`      // Role of created users; defaults to "user"
+}
+
+type ProviderResponse struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	LoginURL    string `json:"login_url"`
+}
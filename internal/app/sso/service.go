@@ -0,0 +1,266 @@
+package sso
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/app/auth"
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/oidc"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// flowTTL is the time the user has to complete the login at the identity provider.
+const flowTTL = 10 * time.Minute
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// LoadProviderConfigs reads the provider configurations from a JSON file.
+func LoadProviderConfigs(path string) ([]ProviderConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OIDC providers file: %w", err)
+	}
+	var configs []ProviderConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse OIDC providers file: %w", err)
+	}
+	for _, cfg := range configs {
+		if !providerNamePattern.MatchString(cfg.Name) {
+			return nil, fmt.Errorf("invalid OIDC provider name %q", cfg.Name)
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+			return nil, fmt.Errorf("OIDC provider %q needs issuer, client_id and redirect_url", cfg.Name)
+		}
+		if _, err := uuid.Parse(cfg.TenantID); err != nil {
+			return nil, fmt.Errorf("OIDC provider %q has an invalid tenant_id: %w", cfg.Name, err)
+		}
+	}
+	return configs, nil
+}
+
+// flowClaims carry the state of a login between the redirect to the provider and the callback.
+// They are stored in a signed, short-lived cookie, so no server-side session is needed.
+type flowClaims struct {
+	TokenType string `json:"token_type"`
+	Provider  string `json:"provider"`
+	State     string `json:"state"`
+	Nonce     string `json:"nonce"`
+	Verifier  string `json:"verifier"`
+	jwt.RegisteredClaims
+}
+
+type provider struct {
+	cfg    ProviderConfig
+	client *oidc.Client
+}
+
+type SSOService struct {
+	providers    map[string]*provider
+	userRepo     domain.UserRepository
+	identityRepo domain.UserIdentityRepository
+	authService  *auth.AuthService
+}
+
+// NewSSOService creates a new instance of SSOService for the configured providers.
+func NewSSOService(configs []ProviderConfig, userRepo domain.UserRepository, identityRepo domain.UserIdentityRepository, authService *auth.AuthService) *SSOService {
+	providers := make(map[string]*provider, len(configs))
+	for _, cfg := range configs {
+		providers[cfg.Name] = &provider{cfg: cfg, client: oidc.NewClient(cfg.Config, nil)}
+	}
+	return &SSOService{
+		providers:    providers,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		authService:  authService,
+	}
+}
+
+// GetProviders lists the configured providers.
+func (s *SSOService) GetProviders() []ProviderResponse {
+	resp := make([]ProviderResponse, 0, len(s.providers))
+	for _, p := range s.providers {
+		displayName := p.cfg.DisplayName
+		if displayName == "" {
+			displayName = p.cfg.Name
+		}
+		resp = append(resp, ProviderResponse{
+			Name:        p.cfg.Name,
+			DisplayName: displayName,
+			LoginURL:    "/auth/sso/" + p.cfg.Name + "/login",
+		})
+	}
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Name < resp[j].Name })
+	return resp
+}
+
+// StartLogin begins the authorization-code flow with PKCE. It returns the provider URL to
+// redirect the user to and the flow token the client must present at the callback.
+func (s *SSOService) StartLogin(ctx context.Context, providerName string) (string, string, error) {
+	p, ok := s.providers[providerName]
+	if !ok {
+		return "", "", ErrProviderNotFound
+	}
+
+	flow := flowClaims{
+		TokenType: utils.TokenTypeSSOFlow,
+		Provider:  providerName,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(flowTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "go-starterpack",
+		},
+	}
+	for _, field := range []*string{&flow.State, &flow.Nonce, &flow.Verifier} {
+		value, err := oidc.RandomString()
+		if err != nil {
+			return "", "", errors.NewInternalServerError(err, "Internal error starting SSO login.")
+		}
+		*field = value
+	}
+
+	redirectURL, err := p.client.AuthCodeURL(ctx, flow.State, flow.Nonce, oidc.CodeChallengeS256(flow.Verifier))
+	if err != nil {
+		return "", "", errors.NewInternalServerError(fmt.Errorf("failed to build authorization URL for %s: %w", providerName, err), "Identity provider is not available.")
+	}
+	flowToken, err := utils.SignClaims(flow)
+	if err != nil {
+		return "", "", errors.NewInternalServerError(fmt.Errorf("failed to sign SSO flow: %w", err), "Internal error starting SSO login.")
+	}
+	return redirectURL, flowToken, nil
+}
+
+// CompleteLogin handles the provider callback: it checks the state, redeems the code, validates
+// the ID token and logs in the linked user, linking or creating the user on first login.
+func (s *SSOService) CompleteLogin(ctx context.Context, providerName, flowToken, state, code string) (*auth.AuthResponse, error) {
+	p, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+
+	var flow flowClaims
+	if flowToken == "" || utils.ParseClaims(flowToken, &flow) != nil {
+		return nil, ErrInvalidState
+	}
+	if flow.TokenType != utils.TokenTypeSSOFlow || flow.Provider != providerName || flow.State == "" || flow.State != state {
+		return nil, ErrInvalidState
+	}
+
+	token, err := p.client.Exchange(ctx, code, flow.Verifier)
+	if err != nil {
+		log.Warnf(ctx, "SSO: Code exchange with %s failed: %v", providerName, err)
+		return nil, ErrLoginFailed
+	}
+	claims, err := p.client.VerifyIDToken(ctx, token.IDToken, flow.Nonce)
+	if err != nil {
+		log.Warnf(ctx, "SSO: ID token from %s rejected: %v", providerName, err)
+		return nil, ErrLoginFailed
+	}
+
+	user, err := s.resolveUser(ctx, p, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.authService.CompleteLogin(ctx, user, uuid.MustParse(p.cfg.TenantID))
+}
+
+// resolveUser returns the user linked to the external identity. An unknown identity is linked to
+// the user with the same verified email in the provider's tenant, or a new user is created when
+// the provider allows it.
+func (s *SSOService) resolveUser(ctx context.Context, p *provider, claims *oidc.IDTokenClaims) (*domain.User, error) {
+	identity, err := s.identityRepo.FindByProviderSubject(ctx, p.cfg.Name, claims.Subject)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find identity: %w", err), "Internal error during SSO login.")
+	}
+	if identity != nil {
+		user, err := s.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, errors.NewInternalServerError(fmt.Errorf("failed to find user for identity: %w", err), "Internal error during SSO login.")
+		}
+		if user == nil {
+			return nil, ErrAccountNotFound
+		}
+		if err := s.identityRepo.TouchLogin(ctx, identity.ID, claims.Email); err != nil {
+			return nil, errors.NewInternalServerError(fmt.Errorf("failed to update identity: %w", err), "Internal error during SSO login.")
+		}
+		return user, nil
+	}
+
+	if claims.Email == "" {
+		log.Warnf(ctx, "SSO: Provider %s returned no email for subject %s", p.cfg.Name, claims.Subject)
+		return nil, ErrLoginFailed
+	}
+	tenantID := uuid.MustParse(p.cfg.TenantID)
+
+	user, err := s.userRepo.FindByEmail(ctx, tenantID, claims.Email)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find user by email: %w", err), "Internal error during SSO login.")
+	}
+	if user != nil {
+		// Only an address the provider has verified proves that the identity owns the account.
+		if !claims.EmailVerified {
+			return nil, ErrEmailNotVerified
+		}
+	} else {
+		if !p.cfg.AutoCreateUsers {
+			return nil, ErrAccountNotFound
+		}
+		user, err = s.createUser(ctx, p, tenantID, claims)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	identity = &domain.UserIdentity{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Provider:    p.cfg.Name,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		CreatedAt:   now,
+		LastLoginAt: &now,
+	}
+	if err := s.identityRepo.Save(ctx, identity); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to link identity: %w", err), "Internal error during SSO login.")
+	}
+	log.Infof(ctx, "SSO: Linked %s identity %s to user %s", p.cfg.Name, claims.Subject, user.ID)
+	return user, nil
+}
+
+// createUser creates the user for a first login through the provider. The user has no password;
+// one can be set later with the password reset flow.
+func (s *SSOService) createUser(ctx context.Context, p *provider, tenantID uuid.UUID, claims *oidc.IDTokenClaims) (*domain.User, error) {
+	role := p.cfg.DefaultRole
+	if role == "" {
+		role = domain.RoleUser
+	}
+	name := claims.Name
+	if name == "" {
+		name = claims.Email
+	}
+	user := &domain.User{
+		Email:    claims.Email,
+		Name:     name,
+		TenantID: tenantID,
+		Role:     role,
+	}
+	user.GenerateID()
+	if claims.EmailVerified {
+		user.EmailVerifiedAt = &user.CreatedAt
+	}
+	if err := s.authService.ProvisionUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
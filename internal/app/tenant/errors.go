@@ -0,0 +1,15 @@
+package tenant
+
+import (
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/utils/errors"
+)
+
+// Module-specific custom errors for tenant management.
+var (
+	ErrTenantNotFound         = errors.New("TENANT_NOT_FOUND", "Tenant with given ID not found", http.StatusNotFound, nil, nil)
+	ErrSlugTaken              = errors.New("TENANT_SLUG_TAKEN", "A tenant with this slug already exists", http.StatusConflict, nil, nil)
+	ErrInvalidSlug            = errors.New("TENANT_INVALID_SLUG", "Slug may only contain lowercase letters, digits and dashes, and can't start or end with a dash", http.StatusBadRequest, nil, nil)
+	ErrCannotSuspendOwnTenant = errors.New("TENANT_SUSPEND_OWN", "You can't suspend the tenant you are signed in to", http.StatusConflict, nil, nil)
+)
@@ -0,0 +1,144 @@
+package tenant
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/http/middleware"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type TenantHandler struct {
+	service   *TenantService
+	validator *validator.Validate
+}
+
+// NewTenantHandler creates a new instance of TenantHandler.
+func NewTenantHandler(s *TenantService, v *validator.Validate) *TenantHandler {
+	return &TenantHandler{service: s, validator: v}
+}
+
+// RegisterRoutes registers the tenant administration routes on the authenticated router. They
+// span all tenants and require the platform-level tenants:manage permission.
+func (h *TenantHandler) RegisterRoutes(router *mux.Router) {
+	manage := middleware.RequirePermission(domain.PermissionTenantsManage)
+	router.Handle("/tenants", manage(http.HandlerFunc(h.GetTenants))).Methods("GET")
+	router.Handle("/tenants", manage(http.HandlerFunc(h.CreateTenant))).Methods("POST")
+	router.Handle("/tenants/{id}", manage(http.HandlerFunc(h.GetTenantByID))).Methods("GET")
+	router.Handle("/tenants/{id}", manage(http.HandlerFunc(h.UpdateTenant))).Methods("PATCH")
+	router.Handle("/tenants/{id}/suspend", manage(http.HandlerFunc(h.SuspendTenant))).Methods("POST")
+	router.Handle("/tenants/{id}/activate", manage(http.HandlerFunc(h.ActivateTenant))).Methods("POST")
+}
+
+// CreateTenant handles the request to create a tenant.
+func (h *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var req CreateTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	tenant, err := h.service.CreateTenant(r.Context(), req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusCreated, tenant)
+}
+
+// GetTenants handles the request to list tenants with pagination.
+func (h *TenantHandler) GetTenants(w http.ResponseWriter, r *http.Request) {
+	var req GetTenantsRequest
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		req.Page, _ = strconv.Atoi(pageStr)
+	} else {
+		req.Page = 1
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		req.Limit, _ = strconv.Atoi(limitStr)
+	} else {
+		req.Limit = 10
+	}
+	req.Query = r.URL.Query().Get("query")
+	req.Status = r.URL.Query().Get("status")
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	response, err := h.service.GetTenants(r.Context(), req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, response)
+}
+
+// GetTenantByID handles the request to get a tenant.
+func (h *TenantHandler) GetTenantByID(w http.ResponseWriter, r *http.Request) {
+	tenant, err := h.service.GetTenantByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, tenant)
+}
+
+// UpdateTenant handles the request to update a tenant's name, plan or settings.
+func (h *TenantHandler) UpdateTenant(w http.ResponseWriter, r *http.Request) {
+	var req UpdateTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	tenant, err := h.service.UpdateTenant(r.Context(), mux.Vars(r)["id"], req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, tenant)
+}
+
+// SuspendTenant handles the request to suspend a tenant.
+func (h *TenantHandler) SuspendTenant(w http.ResponseWriter, r *http.Request) {
+	h.setStatus(w, r, domain.TenantStatusSuspended)
+}
+
+// ActivateTenant handles the request to reactivate a suspended tenant.
+func (h *TenantHandler) ActivateTenant(w http.ResponseWriter, r *http.Request) {
+	h.setStatus(w, r, domain.TenantStatusActive)
+}
+
+func (h *TenantHandler) setStatus(w http.ResponseWriter, r *http.Request, status string) {
+	callerTenantID, _ := r.Context().Value(middleware.ContextKeyTenantID).(string)
+
+	tenant, err := h.service.SetTenantStatus(r.Context(), callerTenantID, mux.Vars(r)["id"], status)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, tenant)
+}
@@ -0,0 +1,40 @@
+package tenant
+
+import "starterpack-golang-cleanarch/internal/utils"
+
+// CreateTenantRequest is the DTO for creating a tenant.
+type CreateTenantRequest struct {
+	Name     string                 `json:"name" validate:"required,max=255"`
+	Slug     string                 `json:"slug" validate:"required,min=2,max=63"` // Lowercase letters, digits and dashes
+	Plan     string                 `json:"plan" validate:"omitempty,max=50"`      // Defaults to "free"
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// UpdateTenantRequest is the DTO for updating a tenant. Omitted fields are left unchanged;
+// settings, when given, replace the current settings. The slug can't be changed.
+type UpdateTenantRequest struct {
+	Name     *string                `json:"name" validate:"omitempty,min=1,max=255"`
+	Plan     *string                `json:"plan" validate:"omitempty,min=1,max=50"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// TenantResponse is the DTO for responding with tenant details.
+type TenantResponse struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Slug      string                 `json:"slug"`
+	Status    string                 `json:"status"`
+	Plan      string                 `json:"plan"`
+	Settings  map[string]interface{} `json:"settings"`
+	CreatedAt string                 `json:"created_at"`
+	UpdatedAt string                 `json:"updated_at"`
+}
+
+// GetTenantsRequest is the DTO for querying tenants with pagination and filters.
+type GetTenantsRequest struct {
+	utils.PaginationRequest
+	Status string `query:"status" validate:"omitempty,oneof=active suspended"`
+}
+
+// GetTenantsResponse is the DTO for responding with a paginated list of tenants.
+type GetTenantsResponse = utils.PaginationResponse[TenantResponse]
@@ -0,0 +1,201 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/google/uuid"
+)
+
+// defaultPlan is the plan of tenants created without one.
+const defaultPlan = "free"
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$`)
+
+type TenantService struct {
+	tenantRepo domain.TenantRepository
+}
+
+// NewTenantService creates a new instance of TenantService.
+func NewTenantService(tenantRepo domain.TenantRepository) *TenantService {
+	return &TenantService{tenantRepo: tenantRepo}
+}
+
+// IsTenantActive implements middleware.TenantChecker. Unknown tenants are reported as inactive.
+func (s *TenantService) IsTenantActive(ctx context.Context, tenantID string) (bool, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return false, nil
+	}
+	tenant, err := s.tenantRepo.FindByID(ctx, parsedTenantID)
+	if err != nil {
+		return false, err
+	}
+	return tenant != nil && tenant.IsActive(), nil
+}
+
+// CreateTenant creates an active tenant.
+func (s *TenantService) CreateTenant(ctx context.Context, req CreateTenantRequest) (*TenantResponse, error) {
+	if !slugPattern.MatchString(req.Slug) {
+		return nil, ErrInvalidSlug
+	}
+	existing, err := s.tenantRepo.FindBySlug(ctx, req.Slug)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to check existing tenant: %w", err), "Internal error during tenant creation check.")
+	}
+	if existing != nil {
+		return nil, ErrSlugTaken
+	}
+
+	tenant := &domain.Tenant{
+		Name:     req.Name,
+		Slug:     req.Slug,
+		Status:   domain.TenantStatusActive,
+		Plan:     req.Plan,
+		Settings: domain.TenantSettings(req.Settings),
+	}
+	if tenant.Plan == "" {
+		tenant.Plan = defaultPlan
+	}
+	if tenant.Settings == nil {
+		tenant.Settings = domain.TenantSettings{}
+	}
+	tenant.GenerateID()
+
+	if err := s.tenantRepo.Save(ctx, tenant); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to save tenant: %w", err), "Internal error saving tenant.")
+	}
+	log.Infof(ctx, "Tenant: Created tenant %s (%s)", tenant.ID, tenant.Slug)
+
+	resp := newTenantResponse(tenant)
+	return &resp, nil
+}
+
+// GetTenants lists tenants with pagination.
+func (s *TenantService) GetTenants(ctx context.Context, req GetTenantsRequest) (*GetTenantsResponse, error) {
+	total, tenants, err := s.tenantRepo.FindAll(ctx, req.Page, req.Limit, req.Status, req.Query)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to fetch tenants: %w", err), "Internal error fetching tenants.")
+	}
+
+	tenantResponses := make([]TenantResponse, len(tenants))
+	for i, tenant := range tenants {
+		tenantResponses[i] = newTenantResponse(tenant)
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	var nextPage, prevPage *int
+	if req.Page < totalPages {
+		np := req.Page + 1
+		nextPage = &np
+	}
+	if req.Page > 1 {
+		pp := req.Page - 1
+		prevPage = &pp
+	}
+
+	return &GetTenantsResponse{
+		Data:       tenantResponses,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+		NextPage:   nextPage,
+		PrevPage:   prevPage,
+	}, nil
+}
+
+// GetTenantByID returns a tenant.
+func (s *TenantService) GetTenantByID(ctx context.Context, id string) (*TenantResponse, error) {
+	tenant, err := s.findTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := newTenantResponse(tenant)
+	return &resp, nil
+}
+
+// UpdateTenant changes a tenant's name, plan or settings.
+func (s *TenantService) UpdateTenant(ctx context.Context, id string, req UpdateTenantRequest) (*TenantResponse, error) {
+	tenant, err := s.findTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		tenant.Name = *req.Name
+	}
+	if req.Plan != nil {
+		tenant.Plan = *req.Plan
+	}
+	if req.Settings != nil {
+		tenant.Settings = domain.TenantSettings(req.Settings)
+	}
+	tenant.UpdateTimestamp()
+
+	if err := s.tenantRepo.Update(ctx, tenant); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to update tenant: %w", err), "Internal error updating tenant.")
+	}
+	resp := newTenantResponse(tenant)
+	return &resp, nil
+}
+
+// SetTenantStatus suspends or reactivates a tenant. Suspension takes effect on the next request of
+// the tenant's users and API keys. Callers can't suspend their own tenant, so a platform admin
+// doesn't lock themselves out.
+func (s *TenantService) SetTenantStatus(ctx context.Context, callerTenantID, id, status string) (*TenantResponse, error) {
+	tenant, err := s.findTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status == domain.TenantStatusSuspended && tenant.ID.String() == callerTenantID {
+		return nil, ErrCannotSuspendOwnTenant
+	}
+	if tenant.Status != status {
+		tenant.Status = status
+		tenant.UpdateTimestamp()
+		if err := s.tenantRepo.Update(ctx, tenant); err != nil {
+			return nil, errors.NewInternalServerError(fmt.Errorf("failed to update tenant status: %w", err), "Internal error updating tenant.")
+		}
+		log.Infof(ctx, "Tenant: Tenant %s (%s) is now %s", tenant.ID, tenant.Slug, status)
+	}
+	resp := newTenantResponse(tenant)
+	return &resp, nil
+}
+
+func (s *TenantService) findTenant(ctx context.Context, id string) (*domain.Tenant, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid tenant ID format (must be UUID)", nil)
+	}
+	tenant, err := s.tenantRepo.FindByID(ctx, parsedID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find tenant: %w", err), "Internal error fetching tenant.")
+	}
+	if tenant == nil {
+		return nil, ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+func newTenantResponse(tenant *domain.Tenant) TenantResponse {
+	settings := map[string]interface{}(tenant.Settings)
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+	return TenantResponse{
+		ID:        tenant.ID.String(),
+		Name:      tenant.Name,
+		Slug:      tenant.Slug,
+		Status:    tenant.Status,
+		Plan:      tenant.Plan,
+		Settings:  settings,
+		CreatedAt: tenant.CreatedAt.Format(utils.ISO8601TimeFormat),
+		UpdatedAt: tenant.UpdatedAt.Format(utils.ISO8601TimeFormat),
+	}
+}
@@ -0,0 +1,17 @@
+package users
+
+import (
+	"net/http"
+
+	"starterpack-golang-cleanarch/internal/utils/errors"
+)
+
+// Module-specific custom errors for user administration.
+var (
+	ErrUserNotFound = errors.New("USER_NOT_FOUND", "User with given ID not found in this tenant", http.StatusNotFound, nil, nil)
+	ErrSelfAction   = errors.New("USER_SELF_ACTION", "You can't deactivate, disable or delete your own account", http.StatusConflict, nil, nil)
+	ErrEmailTaken   = errors.New("USER_ALREADY_EXISTS", "Another user with this email exists in this tenant", http.StatusConflict, nil, nil)
+	ErrUserErased   = errors.New("USER_ERASED", "This user's personal data has been erased, so the account can't be restored", http.StatusConflict, nil, nil)
+	// ErrManagedByHomeTenant is returned when editing the profile of a member from another tenant.
+	ErrManagedByHomeTenant = errors.New("USER_MANAGED_ELSEWHERE", "This user's profile is managed by their home tenant", http.StatusConflict, nil, nil)
+)
@@ -0,0 +1,218 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/http/middleware"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type UserHandler struct {
+	service   *UserService
+	validator *validator.Validate
+}
+
+// NewUserHandler creates a new instance of UserHandler.
+func NewUserHandler(s *UserService, v *validator.Validate) *UserHandler {
+	return &UserHandler{service: s, validator: v}
+}
+
+// RegisterRoutes registers the user administration routes on the authenticated router. They act
+// on the members of the caller's tenant.
+func (h *UserHandler) RegisterRoutes(router *mux.Router) {
+	read := middleware.RequirePermission(domain.PermissionUsersRead)
+	write := middleware.RequirePermission(domain.PermissionUsersWrite)
+	router.Handle("/users", read(http.HandlerFunc(h.GetUsers))).Methods("GET")
+	router.Handle("/users/{id}", read(http.HandlerFunc(h.GetUserByID))).Methods("GET")
+	router.Handle("/users/{id}", write(http.HandlerFunc(h.UpdateUser))).Methods("PATCH")
+	router.Handle("/users/{id}", write(http.HandlerFunc(h.DeleteUser))).Methods("DELETE")
+	router.Handle("/users/{id}/deactivate", write(http.HandlerFunc(h.DeactivateUser))).Methods("POST")
+	router.Handle("/users/{id}/activate", write(http.HandlerFunc(h.ActivateUser))).Methods("POST")
+	router.Handle("/users/{id}/disable", write(http.HandlerFunc(h.DisableUser))).Methods("POST")
+	router.Handle("/users/{id}/enable", write(http.HandlerFunc(h.EnableUser))).Methods("POST")
+	router.Handle("/users/{id}/restore", write(http.HandlerFunc(h.RestoreUser))).Methods("POST")
+}
+
+// GetUsers handles the request to list the tenant's users with pagination.
+func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	var req GetUsersRequest
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		req.Page, _ = strconv.Atoi(pageStr)
+	} else {
+		req.Page = 1
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		req.Limit, _ = strconv.Atoi(limitStr)
+	} else {
+		req.Limit = 10
+	}
+	req.Query = r.URL.Query().Get("query")
+	req.Status = r.URL.Query().Get("status")
+	if deletedStr := r.URL.Query().Get("deleted"); deletedStr != "" {
+		deleted, err := strconv.ParseBool(deletedStr)
+		if err != nil {
+			utils.HandleHTTPError(w, errors.NewBadRequest("Invalid deleted parameter (must be true or false)", nil), r)
+			return
+		}
+		req.Deleted = deleted
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	response, err := h.service.GetUsers(r.Context(), tenantID, req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, response)
+}
+
+// GetUserByID handles the request to get a user of the tenant.
+func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	user, err := h.service.GetUserByID(r.Context(), tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, user)
+}
+
+// UpdateUser handles the request to update a user's profile.
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest("Invalid request payload", nil), r)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		utils.HandleHTTPError(w, errors.NewBadRequest(err.Error(), nil), r)
+		return
+	}
+
+	user, err := h.service.UpdateUser(r.Context(), tenantID, mux.Vars(r)["id"], req)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, user)
+}
+
+// DeleteUser handles the request to remove a user from the tenant.
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+	callerID, _ := r.Context().Value(middleware.ContextKeyUserID).(string)
+
+	if err := h.service.DeleteUser(r.Context(), tenantID, callerID, mux.Vars(r)["id"]); err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeactivateUser handles the request to deactivate a user in the tenant.
+func (h *UserHandler) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	h.setStatus(w, r, domain.MembershipStatusDeactivated)
+}
+
+// ActivateUser handles the request to reactivate a deactivated user.
+func (h *UserHandler) ActivateUser(w http.ResponseWriter, r *http.Request) {
+	h.setStatus(w, r, domain.MembershipStatusActive)
+}
+
+func (h *UserHandler) setStatus(w http.ResponseWriter, r *http.Request, status string) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+	callerID, _ := r.Context().Value(middleware.ContextKeyUserID).(string)
+
+	user, err := h.service.SetUserStatus(r.Context(), tenantID, callerID, mux.Vars(r)["id"], status)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, user)
+}
+
+// DisableUser handles the request to disable a home user's account.
+func (h *UserHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, true)
+}
+
+// EnableUser handles the request to re-enable a disabled account.
+func (h *UserHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, false)
+}
+
+func (h *UserHandler) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+	callerID, _ := r.Context().Value(middleware.ContextKeyUserID).(string)
+
+	user, err := h.service.SetUserDisabled(r.Context(), tenantID, callerID, mux.Vars(r)["id"], disabled)
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, user)
+}
+
+// RestoreUser handles the request to restore a deleted user.
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := r.Context().Value(middleware.ContextKeyTenantID).(string)
+	if !ok || tenantID == "" {
+		utils.HandleHTTPError(w, errors.ErrUnauthorized, r)
+		return
+	}
+
+	user, err := h.service.RestoreUser(r.Context(), tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		utils.HandleHTTPError(w, err, r)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, user)
+}
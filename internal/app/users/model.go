@@ -0,0 +1,37 @@
+package users
+
+import "starterpack-golang-cleanarch/internal/utils"
+
+// UpdateUserRequest is the DTO for updating a user's profile. Omitted fields are left unchanged.
+type UpdateUserRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=255"`
+	PhoneNumber *string `json:"phone_number" validate:"omitempty,max=50"`
+}
+
+// UserResponse is the DTO for responding with a member of the tenant.
+type UserResponse struct {
+	ID            string  `json:"id"`
+	HomeTenantID  string  `json:"home_tenant_id"` // Tenant that owns the account
+	Email         string  `json:"email"`
+	Name          string  `json:"name"`
+	PhoneNumber   string  `json:"phone_number"`
+	Role          string  `json:"role"`   // Role in this tenant
+	Status        string  `json:"status"` // Membership status in this tenant
+	EmailVerified bool    `json:"email_verified"`
+	DisabledAt    *string `json:"disabled_at,omitempty"` // Set when the account can't sign in to any tenant
+	DeletedAt     *string `json:"deleted_at,omitempty"`
+	ErasedAt      *string `json:"erased_at,omitempty"` // Set once the personal data has been erased
+	JoinedAt      string  `json:"joined_at"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+// GetUsersRequest is the DTO for querying the tenant's users with pagination and filters.
+type GetUsersRequest struct {
+	utils.PaginationRequest
+	Status  string `query:"status" validate:"omitempty,oneof=active deactivated"`
+	Deleted bool   `query:"deleted"` // List the tenant's deleted users instead, e.g. to restore one
+}
+
+// GetUsersResponse is the DTO for responding with a paginated list of users.
+type GetUsersResponse = utils.PaginationResponse[UserResponse]
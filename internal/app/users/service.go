@@ -0,0 +1,299 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/app/auth"
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/utils"
+	"starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/google/uuid"
+)
+
+// UserService administers the members of a tenant. A tenant's users are its members: the accounts
+// it owns (its home users) and accounts from other tenants that joined through an invitation.
+type UserService struct {
+	userRepo       domain.UserRepository
+	membershipRepo domain.TenantMembershipRepository
+	roleRepo       domain.RoleRepository
+	authService    *auth.AuthService
+	transactor     domain.Transactor
+}
+
+// NewUserService creates a new instance of UserService.
+func NewUserService(userRepo domain.UserRepository, membershipRepo domain.TenantMembershipRepository, roleRepo domain.RoleRepository, authService *auth.AuthService, transactor domain.Transactor) *UserService {
+	return &UserService{
+		userRepo:       userRepo,
+		membershipRepo: membershipRepo,
+		roleRepo:       roleRepo,
+		authService:    authService,
+		transactor:     transactor,
+	}
+}
+
+// GetUsers lists the tenant's members with pagination.
+func (s *UserService) GetUsers(ctx context.Context, tenantID string, req GetUsersRequest) (*GetUsersResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	filter := domain.MemberFilter{Status: req.Status, Query: req.Query, Deleted: req.Deleted}
+	total, members, err := s.membershipRepo.FindMembers(ctx, parsedTenantID, req.Page, req.Limit, filter)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to fetch users: %w", err), "Internal error fetching users.")
+	}
+
+	userResponses := make([]UserResponse, len(members))
+	for i, member := range members {
+		userResponses[i] = newUserResponse(member)
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	var nextPage, prevPage *int
+	if req.Page < totalPages {
+		np := req.Page + 1
+		nextPage = &np
+	}
+	if req.Page > 1 {
+		pp := req.Page - 1
+		prevPage = &pp
+	}
+
+	return &GetUsersResponse{
+		Data:       userResponses,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+		NextPage:   nextPage,
+		PrevPage:   prevPage,
+	}, nil
+}
+
+// GetUserByID returns a member of the tenant.
+func (s *UserService) GetUserByID(ctx context.Context, tenantID, id string) (*UserResponse, error) {
+	_, member, err := s.findMember(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := newUserResponse(member)
+	return &resp, nil
+}
+
+// UpdateUser changes a member's name or phone number. Only the home tenant can edit an account's
+// profile; other tenants the user belongs to see it read-only.
+func (s *UserService) UpdateUser(ctx context.Context, tenantID, id string, req UpdateUserRequest) (*UserResponse, error) {
+	parsedTenantID, member, err := s.findMember(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !member.IsHomeTenant(parsedTenantID) {
+		return nil, ErrManagedByHomeTenant
+	}
+	if req.Name != nil {
+		member.Name = *req.Name
+	}
+	if req.PhoneNumber != nil {
+		member.PhoneNumber = *req.PhoneNumber
+	}
+	member.UpdateTimestamp()
+
+	if err := s.userRepo.Update(ctx, &member.User); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to update user: %w", err), "Internal error updating user.")
+	}
+	resp := newUserResponse(member)
+	return &resp, nil
+}
+
+// SetUserStatus deactivates or reactivates a member in the tenant. Deactivation ends the member's
+// sessions in the tenant and keeps them from signing in to it until they are reactivated; their
+// account and roles are kept. Callers can't deactivate themselves.
+func (s *UserService) SetUserStatus(ctx context.Context, tenantID, callerID, id, status string) (*UserResponse, error) {
+	parsedTenantID, member, err := s.findMember(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if status == domain.MembershipStatusDeactivated && member.ID.String() == callerID {
+		return nil, ErrSelfAction
+	}
+	if member.MemberStatus != status {
+		if err := s.membershipRepo.UpdateStatus(ctx, member.ID, parsedTenantID, status); err != nil {
+			return nil, errors.NewInternalServerError(fmt.Errorf("failed to update membership status: %w", err), "Internal error updating user.")
+		}
+		if status == domain.MembershipStatusDeactivated {
+			if err := s.authService.RevokeTenantSessions(ctx, &member.User, parsedTenantID); err != nil {
+				return nil, errors.NewInternalServerError(err, "Internal error updating user.")
+			}
+		}
+		member.MemberStatus = status
+		log.Infof(ctx, "Users: User %s is now %s in tenant %s", member.ID, status, parsedTenantID)
+	}
+	resp := newUserResponse(member)
+	return &resp, nil
+}
+
+// SetUserDisabled disables or re-enables a home user's account. A disabled account can't sign in
+// to any tenant and its sessions end; unlike deactivation, this is only up to the home tenant.
+// Callers can't disable themselves.
+func (s *UserService) SetUserDisabled(ctx context.Context, tenantID, callerID, id string, disabled bool) (*UserResponse, error) {
+	parsedTenantID, member, err := s.findMember(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !member.IsHomeTenant(parsedTenantID) {
+		return nil, ErrManagedByHomeTenant
+	}
+	if disabled && member.ID.String() == callerID {
+		return nil, ErrSelfAction
+	}
+	if member.IsDisabled() == disabled {
+		resp := newUserResponse(member)
+		return &resp, nil
+	}
+
+	if disabled {
+		now := time.Now()
+		member.DisabledAt = &now
+	} else {
+		member.DisabledAt = nil
+	}
+	if err := s.userRepo.Update(ctx, &member.User); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to update user: %w", err), "Internal error updating user.")
+	}
+	if disabled {
+		if err := s.authService.RevokeAllSessions(ctx, member.ID); err != nil {
+			return nil, errors.NewInternalServerError(err, "Internal error updating user.")
+		}
+	}
+	log.Infof(ctx, "Users: Account of user %s disabled: %t", member.ID, disabled)
+	resp := newUserResponse(member)
+	return &resp, nil
+}
+
+// DeleteUser removes a member from the tenant. A home user's account is soft-deleted, which
+// removes them from every tenant until an admin restores it; a member from another tenant only
+// loses their membership and roles here. Callers can't delete themselves.
+func (s *UserService) DeleteUser(ctx context.Context, tenantID, callerID, id string) error {
+	parsedTenantID, member, err := s.findMember(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if member.ID.String() == callerID {
+		return ErrSelfAction
+	}
+
+	if member.IsHomeTenant(parsedTenantID) {
+		// The sessions are ended with the deletion, so a failed revocation keeps the user as well.
+		err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.userRepo.Delete(ctx, member.ID); err != nil {
+				return errors.NewInternalServerError(fmt.Errorf("failed to delete user: %w", err), "Internal error deleting user.")
+			}
+			if err := s.authService.RevokeAllSessions(ctx, member.ID); err != nil {
+				return errors.NewInternalServerError(err, "Internal error deleting user.")
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		log.Infof(ctx, "Users: Deleted user %s of tenant %s", member.ID, parsedTenantID)
+		return nil
+	}
+
+	roles, err := s.roleRepo.FindByUser(ctx, parsedTenantID, member.ID)
+	if err != nil {
+		return errors.NewInternalServerError(fmt.Errorf("failed to find user roles: %w", err), "Internal error deleting user.")
+	}
+	for _, role := range roles {
+		if err := s.roleRepo.RemoveFromUser(ctx, parsedTenantID, member.ID, role.ID); err != nil {
+			return errors.NewInternalServerError(fmt.Errorf("failed to remove role %q: %w", role.Name, err), "Internal error deleting user.")
+		}
+	}
+	if err := s.membershipRepo.Delete(ctx, member.ID, parsedTenantID); err != nil {
+		return errors.NewInternalServerError(fmt.Errorf("failed to delete membership: %w", err), "Internal error deleting user.")
+	}
+	if err := s.authService.RevokeTenantSessions(ctx, &member.User, parsedTenantID); err != nil {
+		return errors.NewInternalServerError(err, "Internal error deleting user.")
+	}
+	log.Infof(ctx, "Users: Removed user %s from tenant %s", member.ID, parsedTenantID)
+	return nil
+}
+
+// RestoreUser undoes the deletion of a home user. Their sessions stay ended; the user signs in
+// again. The email must not have been taken by another user of the tenant in the meantime, and
+// erased users can't be restored.
+func (s *UserService) RestoreUser(ctx context.Context, tenantID, id string) (*UserResponse, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid user ID format (must be UUID)", nil)
+	}
+	user, err := s.userRepo.FindByIDIncludingDeleted(ctx, parsedID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to find user: %w", err), "Internal error restoring user.")
+	}
+	if user == nil || !user.IsDeleted() || user.TenantID != parsedTenantID {
+		return nil, ErrUserNotFound
+	}
+	if user.IsErased() {
+		return nil, ErrUserErased
+	}
+	existing, err := s.userRepo.FindByEmail(ctx, parsedTenantID, user.Email)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to check existing user: %w", err), "Internal error restoring user.")
+	}
+	if existing != nil {
+		return nil, ErrEmailTaken
+	}
+
+	if err := s.userRepo.Restore(ctx, user.ID); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Errorf("failed to restore user: %w", err), "Internal error restoring user.")
+	}
+	log.Infof(ctx, "Users: Restored user %s of tenant %s", user.ID, parsedTenantID)
+	return s.GetUserByID(ctx, tenantID, id)
+}
+
+func (s *UserService) findMember(ctx context.Context, tenantID, id string) (uuid.UUID, *domain.TenantMember, error) {
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return uuid.Nil, nil, errors.ErrUnauthorized
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, nil, errors.NewBadRequest("Invalid user ID format (must be UUID)", nil)
+	}
+	member, err := s.membershipRepo.FindMember(ctx, parsedTenantID, parsedID)
+	if err != nil {
+		return uuid.Nil, nil, errors.NewInternalServerError(fmt.Errorf("failed to find user: %w", err), "Internal error fetching user.")
+	}
+	if member == nil {
+		return uuid.Nil, nil, ErrUserNotFound
+	}
+	return parsedTenantID, member, nil
+}
+
+func newUserResponse(member *domain.TenantMember) UserResponse {
+	return UserResponse{
+		ID:            member.ID.String(),
+		HomeTenantID:  member.TenantID.String(),
+		Email:         member.Email,
+		Name:          member.Name,
+		PhoneNumber:   member.PhoneNumber,
+		Role:          member.MemberRole,
+		Status:        member.MemberStatus,
+		EmailVerified: member.IsEmailVerified(),
+		DisabledAt:    utils.FormatOptionalTime(member.DisabledAt),
+		DeletedAt:     utils.FormatOptionalTime(member.DeletedAt),
+		ErasedAt:      utils.FormatOptionalTime(member.ErasedAt),
+		JoinedAt:      member.JoinedAt.Format(utils.ISO8601TimeFormat),
+		CreatedAt:     member.CreatedAt.Format(utils.ISO8601TimeFormat),
+		UpdatedAt:     member.UpdatedAt.Format(utils.ISO8601TimeFormat),
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Purposes of ActionToken.
+const (
+	ActionTokenPasswordReset     = "password_reset"
+	ActionTokenEmailVerification = "email_verification"
+)
+
+// ActionToken is a single-use token sent to a user to authorize one action, such as resetting
+// the password. Only the hash of the token is stored.
+type ActionToken struct {
+	ID         uuid.UUID  `db:"id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	Purpose    string     `db:"purpose"`
+	TokenHash  string     `db:"token_hash"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	ConsumedAt *time.Time `db:"consumed_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// ActionTokenRepository defines the interface for data access operations for ActionToken.
+type ActionTokenRepository interface {
+	Save(ctx context.Context, token *ActionToken) error
+	// FindValid returns the unconsumed, unexpired token with the given purpose and hash.
+	FindValid(ctx context.Context, purpose, tokenHash string) (*ActionToken, error)
+	// Consume marks the token as used. It returns false when the token was already consumed or
+	// has expired, so a token can only be used once even under concurrent requests.
+	Consume(ctx context.Context, id uuid.UUID) (bool, error)
+	// InvalidateForUser consumes every outstanding token of the user with the given purpose.
+	InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error
+}
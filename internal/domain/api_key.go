@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey is a tenant-scoped credential for machine-to-machine access. Only a hash of the key is
+// stored; Scopes are the permissions the key grants.
+type APIKey struct {
+	ID         uuid.UUID     `db:"id"`
+	TenantID   uuid.UUID     `db:"tenant_id"`
+	Name       string        `db:"name"`
+	Prefix     string        `db:"prefix"`
+	KeyHash    string        `db:"key_hash"`
+	Scopes     []string      `db:"-"`
+	CreatedBy  uuid.NullUUID `db:"created_by"`
+	ExpiresAt  *time.Time    `db:"expires_at"`
+	LastUsedAt *time.Time    `db:"last_used_at"`
+	RevokedAt  *time.Time    `db:"revoked_at"`
+	CreatedAt  time.Time     `db:"created_at"`
+}
+
+// IsActive reports whether the key can be used at time t.
+func (k *APIKey) IsActive(at time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || k.ExpiresAt.After(at))
+}
+
+// APIKeyRepository defines the interface for data access operations for API keys.
+type APIKeyRepository interface {
+	Save(ctx context.Context, key *APIKey) error
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*APIKey, error)
+	FindAll(ctx context.Context, tenantID uuid.UUID) ([]APIKey, error)
+	// FindByHash looks a key up by its hash, across tenants, to authenticate a request.
+	FindByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	// Revoke revokes an active key. It returns false when no such key exists or it was already revoked.
+	Revoke(ctx context.Context, tenantID, id uuid.UUID) (bool, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
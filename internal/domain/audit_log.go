@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions.
+const (
+	AuditActionUserErased = "user.erased"
+)
+
+// Audit target types.
+const (
+	AuditTargetUser = "user"
+)
+
+// AuditDetails holds event-specific data of an audit log entry, stored as a JSON object.
+type AuditDetails map[string]interface{}
+
+// Value implements driver.Valuer.
+func (d AuditDetails) Value() (driver.Value, error) {
+	return TenantSettings(d).Value()
+}
+
+// Scan implements sql.Scanner.
+func (d *AuditDetails) Scan(src interface{}) error {
+	return (*TenantSettings)(d).Scan(src)
+}
+
+// AuditLog records a security-relevant event in a tenant. Details must not hold personal data, so
+// the record outlives the erasure of the people it mentions.
+type AuditLog struct {
+	ID         uuid.UUID     `db:"id"`
+	TenantID   uuid.UUID     `db:"tenant_id"`
+	ActorID    uuid.NullUUID `db:"actor_id"`
+	Action     string        `db:"action"`
+	TargetType string        `db:"target_type"`
+	TargetID   uuid.UUID     `db:"target_id"`
+	Details    AuditDetails  `db:"details"`
+	CreatedAt  time.Time     `db:"created_at"`
+}
+
+// AuditLogRepository defines the interface for data access operations for audit logs.
+type AuditLogRepository interface {
+	Save(ctx context.Context, entry *AuditLog) error
+}
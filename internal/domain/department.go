@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Department groups a tenant's employees. Departments form a hierarchy through ParentID.
+type Department struct {
+	ID        uuid.UUID  `db:"id"`
+	TenantID  uuid.UUID  `db:"tenant_id"`
+	Name      string     `db:"name"`
+	ParentID  *uuid.UUID `db:"parent_id"` // nil for a top-level department
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+func (d *Department) GenerateID() {
+	d.ID = uuid.New()
+	d.CreatedAt = time.Now()
+	d.UpdatedAt = time.Now()
+}
+
+// UpdateTimestamp updates the UpdatedAt field.
+func (d *Department) UpdateTimestamp() {
+	d.UpdatedAt = time.Now()
+}
+
+// ErrDepartmentHasChildren is returned by DepartmentRepository.Delete when the department still has
+// subdepartments, including ones added by a concurrent request.
+var ErrDepartmentHasChildren = errors.New("department has subdepartments")
+
+// DepartmentRepository defines the interface for data access operations for Department.
+type DepartmentRepository interface {
+	Save(ctx context.Context, department *Department) error
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*Department, error)
+	// FindByName matches the name regardless of case, like the uniqueness of names.
+	FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*Department, error)
+	// FindAll returns every department of the tenant, ordered by name.
+	FindAll(ctx context.Context, tenantID uuid.UUID) ([]*Department, error)
+	Update(ctx context.Context, department *Department) error
+	Delete(ctx context.Context, tenantID, id uuid.UUID) error
+	// LockHierarchy holds a lock on the tenant's department hierarchy until the unit of work ctx
+	// belongs to ends, so that checking a new parent for cycles and storing it can't interleave
+	// with another move. It must be called inside Transactor.WithinTransaction.
+	LockHierarchy(ctx context.Context, tenantID uuid.UUID) error
+}
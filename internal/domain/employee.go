@@ -2,45 +2,107 @@ package domain
 
 import (
 	"context"
+	"strconv"
 	"time"
 
-	"github.com/google/uuid" // Tetap digunakan untuk generate UUID string untuk TenantID jika diperlukan.
+	"github.com/google/uuid"
 )
 
-// Employee represents the core business entity for an employee.
+// Employment statuses.
+const (
+	EmployeeStatusActive     = "active"
+	EmployeeStatusOnLeave    = "on_leave"
+	EmployeeStatusTerminated = "terminated"
+)
+
+// Employee represents the core business entity for an employee. Employees are records kept by a
+// tenant, not user accounts.
 type Employee struct {
-	ID           int64     `db:"id"`        // Changed to int64 to match SERIAL PRIMARY KEY
-	TenantID     string    `db:"tenant_id"` // Changed to string to match VARCHAR(36)
-	Name         string    `db:"name"`
-	Email        string    `db:"email"`
-	PhoneNumber  string    `db:"phone_number"`
-	PasswordHash string    `db:"password_hash"` // <-- Added this field
-	CreatedAt    time.Time `db:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at"`
+	ID           uuid.UUID  `db:"id"`
+	TenantID     uuid.UUID  `db:"tenant_id"`
+	Name         string     `db:"name"`
+	Email        string     `db:"email"`
+	PhoneNumber  string     `db:"phone_number"`
+	Status       string     `db:"status"`
+	JobTitle     string     `db:"job_title"`
+	DepartmentID *uuid.UUID `db:"department_id"` // nil when not in a department
+	HireDate     *time.Time `db:"hire_date"`     // Date only; nil when unknown
+	ManagerID    *uuid.UUID `db:"manager_id"`    // Another employee of the tenant; nil for none
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
 }
 
-// GenerateID now populates ID (left to DB auto-increment) and creates UUID string for TenantID.
 func (e *Employee) GenerateID() {
-	// ID (SERIAL) akan di-generate oleh database saat insert, jadi tidak perlu diisi di sini.
-	// Jika TenantID masih kosong, generate UUID baru sebagai string.
-	if e.TenantID == "" {
-		e.TenantID = uuid.New().String()
-	}
-	e.CreatedAt = time.Now()
-	e.UpdatedAt = time.Now()
+	e.ID = uuid.New()
+	e.CreatedAt = time.Now().Truncate(time.Microsecond)
+	e.UpdatedAt = e.CreatedAt
 }
 
-// UpdateTimestamp updates the UpdatedAt field.
+// UpdateTimestamp updates the UpdatedAt field. Timestamps are kept to the microsecond, like
+// PostgreSQL stores them, since UpdatedAt also serves as the employee's version.
 func (e *Employee) UpdateTimestamp() {
-	e.UpdatedAt = time.Now()
+	e.UpdatedAt = time.Now().Truncate(time.Microsecond)
+}
+
+// Version identifies the stored state of the employee, so clients can detect changes made since
+// they read it.
+func (e *Employee) Version() string {
+	return strconv.FormatInt(e.UpdatedAt.UnixMicro(), 10)
+}
+
+// EmployeeFilter narrows a list of employees. Zero values don't filter.
+type EmployeeFilter struct {
+	Query        string // Search in name, email and phone number
+	Status       string
+	JobTitle     string // Case-insensitive exact match
+	DepartmentID *uuid.UUID
+	ManagerID    *uuid.UUID
+	HiredFrom    *time.Time // Inclusive
+	HiredTo      *time.Time // Inclusive
+}
+
+// EmployeeReport is an employee found below another in the reporting hierarchy. Depth is 1 for
+// direct reports, 2 for their reports, and so on.
+type EmployeeReport struct {
+	Employee
+	Depth int `db:"depth"`
+}
+
+// EmployeeEmailTakenError is returned by EmployeeRepository writes when another employee of the
+// tenant already has the email, e.g. one added concurrently since the service checked.
+type EmployeeEmailTakenError struct {
+	Email string
+}
+
+func (e *EmployeeEmailTakenError) Error() string {
+	return "email " + e.Email + " is already used by another employee"
 }
 
 // EmployeeRepository defines the interface for data access operations for Employee.
 type EmployeeRepository interface {
 	Save(ctx context.Context, emp *Employee) error
-	FindByID(ctx context.Context, tenantID string, id int64) (*Employee, error)                              // ID changed to int64, TenantID to string
-	FindByEmail(ctx context.Context, tenantID string, email string) (*Employee, error)                       // TenantID to string
-	FindAll(ctx context.Context, tenantID string, page, limit int, query string) (int64, []*Employee, error) // TenantID to string
-	Update(ctx context.Context, emp *Employee) error
-	Delete(ctx context.Context, tenantID string, id int64) error // ID changed to int64, TenantID to string
+	// SaveAll inserts the employees in one transaction, in order, so an employee can have a
+	// manager saved before it in the same call. Nothing is inserted when an email is taken; the
+	// error is then an *EmployeeEmailTakenError.
+	SaveAll(ctx context.Context, employees []*Employee) error
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*Employee, error)
+	FindByEmail(ctx context.Context, tenantID uuid.UUID, email string) (*Employee, error)
+	// FindByEmails returns the employees of the tenant with any of the emails.
+	FindByEmails(ctx context.Context, tenantID uuid.UUID, emails []string) ([]*Employee, error)
+	// FindAll returns a page of the tenant's employees matching the filter.
+	FindAll(ctx context.Context, tenantID uuid.UUID, page, limit int, filter EmployeeFilter) (int64, []*Employee, error)
+	// FindManagementChain returns the employee's managers, from their direct manager to the top of
+	// the hierarchy.
+	FindManagementChain(ctx context.Context, tenantID, id uuid.UUID) ([]*Employee, error)
+	// LockHierarchy holds a lock on the tenant's reporting hierarchy until the unit of work ctx
+	// belongs to ends, so that checking a new manager for cycles and storing it can't interleave
+	// with another manager change. It must be called inside Transactor.WithinTransaction.
+	LockHierarchy(ctx context.Context, tenantID uuid.UUID) error
+	// FindReports returns everyone who reports to the employee, directly or indirectly, ordered by
+	// depth and name.
+	FindReports(ctx context.Context, tenantID, id uuid.UUID) ([]*EmployeeReport, error)
+	// Update stores the employee unless it was changed since it was read, i.e. its stored
+	// updated_at is no longer previousUpdatedAt. It reports whether the employee was stored.
+	Update(ctx context.Context, emp *Employee, previousUpdatedAt time.Time) (bool, error)
+	Delete(ctx context.Context, tenantID, id uuid.UUID) error
 }
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation invites an email address to join a tenant with a role. Only the hash of the token
+// sent to the invitee is stored.
+type Invitation struct {
+	ID         uuid.UUID     `db:"id"`
+	TenantID   uuid.UUID     `db:"tenant_id"`
+	Email      string        `db:"email"`
+	Role       string        `db:"role"`
+	TokenHash  string        `db:"token_hash"`
+	InvitedBy  uuid.NullUUID `db:"invited_by"`
+	ExpiresAt  time.Time     `db:"expires_at"`
+	AcceptedAt *time.Time    `db:"accepted_at"`
+	RevokedAt  *time.Time    `db:"revoked_at"`
+	CreatedAt  time.Time     `db:"created_at"`
+}
+
+// IsPending reports whether the invitation can still be accepted at time t.
+func (i *Invitation) IsPending(at time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && i.ExpiresAt.After(at)
+}
+
+// InvitationRepository defines the interface for data access operations for invitations.
+type InvitationRepository interface {
+	Save(ctx context.Context, invitation *Invitation) error
+	// FindPending lists the tenant's invitations that can still be accepted.
+	FindPending(ctx context.Context, tenantID uuid.UUID) ([]Invitation, error)
+	// FindValidByHash returns the pending invitation with the given token hash.
+	FindValidByHash(ctx context.Context, tokenHash string) (*Invitation, error)
+	// Accept marks the invitation as accepted. It returns false when it is no longer pending,
+	// so an invitation can only be used once even under concurrent requests.
+	Accept(ctx context.Context, id uuid.UUID) (bool, error)
+	// Revoke revokes a pending invitation. It returns false when no such invitation is pending.
+	Revoke(ctx context.Context, tenantID, id uuid.UUID) (bool, error)
+	// RevokePendingForEmail revokes the pending invitations of an email address in the tenant.
+	RevokePendingForEmail(ctx context.Context, tenantID uuid.UUID, email string) error
+}
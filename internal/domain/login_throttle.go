@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Scopes of login throttles.
+const (
+	LoginThrottleScopeAccount = "account" // Keyed by normalized email
+	LoginThrottleScopeIP      = "ip"      // Keyed by client IP
+)
+
+// LoginThrottle counts the recent failed login attempts for an account or a client IP.
+type LoginThrottle struct {
+	Scope         string     `db:"scope"`
+	Key           string     `db:"key"`
+	Failures      int        `db:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at"`
+	LockedUntil   *time.Time `db:"locked_until"`
+}
+
+// IsLocked reports whether the throttle is locked out at time t.
+func (t *LoginThrottle) IsLocked(at time.Time) bool {
+	return t.LockedUntil != nil && t.LockedUntil.After(at)
+}
+
+// LoginThrottleRepository defines the interface for data access operations for login throttles.
+type LoginThrottleRepository interface {
+	Find(ctx context.Context, scope, key string) (*LoginThrottle, error)
+	// RecordFailure adds a failed attempt and returns the updated throttle. The counter starts over
+	// when the previous failure is older than window or a lockout has expired.
+	RecordFailure(ctx context.Context, scope, key string, window time.Duration) (*LoginThrottle, error)
+	Lock(ctx context.Context, scope, key string, until time.Time) error
+	Reset(ctx context.Context, scope, key string) error
+}
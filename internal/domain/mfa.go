@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserMFA holds the TOTP enrollment of a user. MFA is only enforced once EnabledAt is set.
+type UserMFA struct {
+	UserID       uuid.UUID  `db:"user_id"`
+	TOTPSecret   string     `db:"totp_secret"`
+	EnabledAt    *time.Time `db:"enabled_at"`
+	LastUsedStep int64      `db:"last_used_step"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+// IsEnabled reports whether the enrollment was confirmed.
+func (m *UserMFA) IsEnabled() bool {
+	return m.EnabledAt != nil
+}
+
+// MFARepository defines the interface for data access operations for MFA enrollments and recovery codes.
+type MFARepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*UserMFA, error)
+	// SavePending creates or replaces a not-yet-confirmed enrollment.
+	SavePending(ctx context.Context, mfa *UserMFA) error
+	Enable(ctx context.Context, userID uuid.UUID) error
+	// Delete removes the enrollment and the user's recovery codes.
+	Delete(ctx context.Context, userID uuid.UUID) error
+	// UseStep records a TOTP time step as used. It returns false when the step is not newer than
+	// the last used one, i.e. the code was already used.
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	// ReplaceRecoveryCodes discards the user's recovery codes and stores the given hashes.
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	// UseRecoveryCode marks an unused recovery code as used. It returns false when no such code exists.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Limits of password policies.
+const (
+	PasswordMinLength = 8  // No policy allows shorter passwords
+	PasswordMaxLength = 72 // bcrypt only uses the first 72 bytes, so no policy can require longer passwords
+	// PasswordHistoryMax is the most previous passwords a policy can forbid; older history is
+	// dropped. A new password is compared with each of them using bcrypt, tens of milliseconds of
+	// CPU apiece, also on the unauthenticated reset path, so it is kept small.
+	PasswordHistoryMax = 10
+)
+
+// PasswordPolicy holds the rules a tenant's users must follow when choosing a password. The
+// policy of the user's home tenant applies.
+type PasswordPolicy struct {
+	TenantID         uuid.UUID `db:"tenant_id"`
+	MinLength        int       `db:"min_length"`
+	RequireUppercase bool      `db:"require_uppercase"`
+	RequireLowercase bool      `db:"require_lowercase"`
+	RequireDigit     bool      `db:"require_digit"`
+	RequireSymbol    bool      `db:"require_symbol"`
+	HistoryCount     int       `db:"history_count"`  // Previous passwords that can't be reused
+	CheckBreached    bool      `db:"check_breached"` // Reject passwords found in the breached-password list
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+// DefaultPasswordPolicy returns the policy of tenants that haven't set one.
+func DefaultPasswordPolicy(tenantID uuid.UUID) *PasswordPolicy {
+	return &PasswordPolicy{TenantID: tenantID, MinLength: PasswordMinLength}
+}
+
+// PasswordPolicyRepository defines the interface for data access operations for password policies.
+type PasswordPolicyRepository interface {
+	// Find returns the tenant's policy, or nil when the tenant uses the default policy.
+	Find(ctx context.Context, tenantID uuid.UUID) (*PasswordPolicy, error)
+	// Save creates or replaces the tenant's policy.
+	Save(ctx context.Context, policy *PasswordPolicy) error
+}
+
+// PasswordHistoryRepository stores the hashes of the passwords users have set.
+type PasswordHistoryRepository interface {
+	// Add records a password the user has set and drops all but the newest keep entries.
+	Add(ctx context.Context, userID uuid.UUID, passwordHash string, keep int) error
+	// FindRecent returns the hashes of the user's newest passwords, newest first.
+	FindRecent(ctx context.Context, userID uuid.UUID, limit int) ([]string, error)
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Privacy request types.
+const (
+	PrivacyRequestExport  = "export"  // Export everything stored about the user
+	PrivacyRequestErasure = "erasure" // Erase the user's personal data
+)
+
+// Privacy request statuses. Completed exports become expired once their bundle has been deleted.
+const (
+	PrivacyRequestPending    = "pending"
+	PrivacyRequestProcessing = "processing"
+	PrivacyRequestCompleted  = "completed"
+	PrivacyRequestFailed     = "failed"
+	PrivacyRequestExpired    = "expired"
+)
+
+// PrivacyRequest is a data subject request about a user, processed in the background.
+type PrivacyRequest struct {
+	ID          uuid.UUID     `db:"id"`
+	TenantID    uuid.UUID     `db:"tenant_id"`
+	UserID      uuid.UUID     `db:"user_id"`
+	Type        string        `db:"type"`
+	Status      string        `db:"status"`
+	RequestedBy uuid.NullUUID `db:"requested_by"`
+	FilePath    *string       `db:"file_path"`
+	Error       *string       `db:"error"`
+	ExpiresAt   *time.Time    `db:"expires_at"`
+	CompletedAt *time.Time    `db:"completed_at"`
+	ClaimedAt   *time.Time    `db:"claimed_at"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
+
+func (r *PrivacyRequest) GenerateID() {
+	r.ID = uuid.New()
+	r.CreatedAt = time.Now()
+}
+
+// IsDownloadable reports whether the request is an export whose bundle can still be downloaded.
+func (r *PrivacyRequest) IsDownloadable(now time.Time) bool {
+	return r.Type == PrivacyRequestExport && r.Status == PrivacyRequestCompleted &&
+		r.FilePath != nil && (r.ExpiresAt == nil || now.Before(*r.ExpiresAt))
+}
+
+// PrivacyRequestRepository defines the interface for data access operations for privacy requests.
+type PrivacyRequestRepository interface {
+	Save(ctx context.Context, request *PrivacyRequest) error
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*PrivacyRequest, error)
+	// FindByUser lists the requests about the user in every tenant, newest first.
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]PrivacyRequest, error)
+	// ClaimNext marks the oldest pending request as processing and returns it, or nil when none is
+	// pending. Requests still processing that were claimed before staleBefore are claimed again, as
+	// their worker is assumed to have stopped. Concurrent workers never claim the same request.
+	ClaimNext(ctx context.Context, staleBefore time.Time) (*PrivacyRequest, error)
+	// Complete marks the request as completed, with the export bundle for exports.
+	Complete(ctx context.Context, id uuid.UUID, filePath *string, expiresAt *time.Time) error
+	Fail(ctx context.Context, id uuid.UUID, message string) error
+	// FindExpiredExports returns the completed exports whose bundle expired before now.
+	FindExpiredExports(ctx context.Context, now time.Time) ([]PrivacyRequest, error)
+	MarkExpired(ctx context.Context, id uuid.UUID) error
+}
+
+// PersonalDataRepository reads and erases everything stored about a user, across tables.
+type PersonalDataRepository interface {
+	// Collect returns the user's rows from every table holding data about them, as JSON arrays
+	// keyed by table name. Secrets such as password and token hashes are left out.
+	Collect(ctx context.Context, user *User) (map[string]json.RawMessage, error)
+	// Erase anonymizes the user's personal data and deletes data that only serves to identify or
+	// authenticate them. The user row is kept, deleted and disabled, so references stay valid.
+	Erase(ctx context.Context, user *User) error
+}
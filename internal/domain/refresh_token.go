@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is a persisted refresh-token session. ID is the token's JWT ID (jti).
+// Tokens issued by rotating an earlier token share its FamilyID. AccessTokenID and
+// AccessExpiresAt describe the access token issued together with the refresh token. TenantID is
+// the tenant the session acts in; it is unset for sessions started in the user's home tenant
+// before tenant switching existed.
+type RefreshToken struct {
+	ID              uuid.UUID     `db:"id"`
+	FamilyID        uuid.UUID     `db:"family_id"`
+	UserID          uuid.UUID     `db:"user_id"`
+	TenantID        uuid.NullUUID `db:"tenant_id"`
+	ExpiresAt       time.Time     `db:"expires_at"`
+	AccessTokenID   uuid.NullUUID `db:"access_token_id"`
+	AccessExpiresAt *time.Time    `db:"access_expires_at"`
+	RotatedAt       *time.Time    `db:"rotated_at"`
+	ReplacedBy      uuid.NullUUID `db:"replaced_by"`
+	RevokedAt       *time.Time    `db:"revoked_at"`
+	CreatedAt       time.Time     `db:"created_at"`
+}
+
+// IsRotated reports whether the token has already been exchanged for a new one.
+func (t *RefreshToken) IsRotated() bool {
+	return t.RotatedAt != nil
+}
+
+// IsRevoked reports whether the token has been revoked.
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// RefreshTokenRepository defines the interface for data access operations for RefreshToken.
+type RefreshTokenRepository interface {
+	Save(ctx context.Context, token *RefreshToken) error
+	FindByID(ctx context.Context, id uuid.UUID) (*RefreshToken, error)
+	FindByAccessTokenID(ctx context.Context, accessTokenID uuid.UUID) (*RefreshToken, error)
+	// FindWithLiveAccessToken returns the user's tokens whose paired access token has not expired yet.
+	FindWithLiveAccessToken(ctx context.Context, userID uuid.UUID) ([]*RefreshToken, error)
+	// Rotate marks the token as replaced by replacedBy. It returns false when the token had
+	// already been rotated or revoked, which means the presented token is being reused.
+	Rotate(ctx context.Context, id, replacedBy uuid.UUID) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	// RevokeAllForUserExceptFamily revokes the user's tokens outside the given token family.
+	RevokeAllForUserExceptFamily(ctx context.Context, userID, familyID uuid.UUID) error
+	// RevokeAllForUserInTenant revokes the user's tokens for sessions in the tenant. Tokens that
+	// record no tenant belong to sessions in homeTenantID.
+	RevokeAllForUserInTenant(ctx context.Context, userID, tenantID, homeTenantID uuid.UUID) error
+}
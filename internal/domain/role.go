@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Built-in roles, available in every tenant.
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+	// RolePlatformAdmin manages tenants across the platform. It can't be assigned through the API.
+	RolePlatformAdmin = "platform_admin"
+)
+
+// Permissions checked by the HTTP layer. They must exist in the 'permissions' table.
+const (
+	PermissionUsersRead      = "users:read"
+	PermissionUsersWrite     = "users:write"
+	PermissionRolesRead      = "roles:read"
+	PermissionRolesAssign    = "roles:assign"
+	PermissionEmployeesRead  = "employees:read"
+	PermissionEmployeesWrite = "employees:write"
+	PermissionAPIKeysManage  = "api_keys:manage"
+	PermissionTenantsManage  = "tenants:manage"
+	// PermissionPasswordPolicyManage allows changing the tenant's password policy.
+	PermissionPasswordPolicyManage = "password_policy:manage"
+)
+
+// Role groups permissions. Built-in roles have no TenantID.
+type Role struct {
+	ID          uuid.UUID     `db:"id"`
+	TenantID    uuid.NullUUID `db:"tenant_id"`
+	Name        string        `db:"name"`
+	Description string        `db:"description"`
+	Permissions []string      `db:"-"`
+	CreatedAt   time.Time     `db:"created_at"`
+	UpdatedAt   time.Time     `db:"updated_at"`
+}
+
+// RoleRepository defines the interface for data access operations for roles and role assignments.
+type RoleRepository interface {
+	// FindByName returns the tenant's own role with that name, or the built-in one.
+	FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*Role, error)
+	// FindAll returns the built-in roles and the tenant's own roles, with their permissions.
+	FindAll(ctx context.Context, tenantID uuid.UUID) ([]*Role, error)
+	FindByUser(ctx context.Context, tenantID, userID uuid.UUID) ([]*Role, error)
+	AssignToUser(ctx context.Context, tenantID, userID, roleID uuid.UUID) error
+	RemoveFromUser(ctx context.Context, tenantID, userID, roleID uuid.UUID) error
+	// FindPermissionsByUser returns the distinct permissions granted by the user's roles in the tenant.
+	FindPermissionsByUser(ctx context.Context, tenantID, userID uuid.UUID) ([]string, error)
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tenant statuses. Users and API keys of a suspended tenant can't sign in or call the API.
+const (
+	TenantStatusActive    = "active"
+	TenantStatusSuspended = "suspended"
+)
+
+// TenantSettingAllowSelfRegistration is the setting (a boolean) that lets anyone register into the
+// tenant through /auth/register. Without it, users join through invitations.
+const TenantSettingAllowSelfRegistration = "allow_self_registration"
+
+// TenantSettings holds tenant-specific configuration, stored as a JSON object.
+type TenantSettings map[string]interface{}
+
+// Bool returns the boolean setting, or false when it is not set or not a boolean.
+func (s TenantSettings) Bool(key string) bool {
+	v, _ := s[key].(bool)
+	return v
+}
+
+// Value implements driver.Valuer.
+func (s TenantSettings) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(s)
+}
+
+// Scan implements sql.Scanner.
+func (s *TenantSettings) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*s = TenantSettings{}
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %T for TenantSettings", src)
+	}
+	return json.Unmarshal(data, s)
+}
+
+type Tenant struct {
+	ID        uuid.UUID      `db:"id"`
+	Name      string         `db:"name"`
+	Slug      string         `db:"slug"`
+	Status    string         `db:"status"`
+	Plan      string         `db:"plan"`
+	Settings  TenantSettings `db:"settings"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func (t *Tenant) GenerateID() {
+	t.ID = uuid.New()
+	t.CreatedAt = time.Now()
+	t.UpdatedAt = time.Now()
+}
+
+func (t *Tenant) UpdateTimestamp() {
+	t.UpdatedAt = time.Now()
+}
+
+// IsActive reports whether the tenant's users and API keys may use the application.
+func (t *Tenant) IsActive() bool {
+	return t.Status == TenantStatusActive
+}
+
+// AllowsSelfRegistration reports whether users can register into the tenant without an invitation.
+func (t *Tenant) AllowsSelfRegistration() bool {
+	return t.Settings.Bool(TenantSettingAllowSelfRegistration)
+}
+
+type TenantRepository interface {
+	Save(ctx context.Context, tenant *Tenant) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Tenant, error)
+	FindBySlug(ctx context.Context, slug string) (*Tenant, error)
+	// FindAll returns a page of tenants, optionally filtered by status and a name or slug search.
+	FindAll(ctx context.Context, page, limit int, status, query string) (int64, []*Tenant, error)
+	Update(ctx context.Context, tenant *Tenant) error
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Membership statuses. Deactivated members keep their account and roles but can't act in the tenant.
+const (
+	MembershipStatusActive      = "active"
+	MembershipStatusDeactivated = "deactivated"
+)
+
+// TenantMembership lets a user act in a tenant. Every user is a member of their home tenant
+// (User.TenantID) and can be a member of others. Role is the role placed in access tokens for the
+// tenant; permissions come from the user's RBAC roles in the tenant.
+type TenantMembership struct {
+	UserID    uuid.UUID `db:"user_id"`
+	TenantID  uuid.UUID `db:"tenant_id"`
+	Role      string    `db:"role"`
+	Status    string    `db:"status"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// IsActive reports whether the member may act in the tenant.
+func (m *TenantMembership) IsActive() bool {
+	return m.Status == MembershipStatusActive
+}
+
+// TenantMember is a user together with their membership in a tenant.
+type TenantMember struct {
+	User
+	MemberRole   string    `db:"member_role"`
+	MemberStatus string    `db:"member_status"`
+	JoinedAt     time.Time `db:"joined_at"`
+}
+
+// IsHomeTenant reports whether the tenant the member was loaded for owns the account.
+func (m *TenantMember) IsHomeTenant(tenantID uuid.UUID) bool {
+	return m.TenantID == tenantID
+}
+
+// MemberFilter narrows a list of tenant members.
+type MemberFilter struct {
+	Status string // Membership status; empty for any
+	Query  string // Search in name and email
+	// Deleted lists the deleted users the tenant owns instead of the live members.
+	Deleted bool
+}
+
+// TenantMembershipRepository defines the interface for data access operations for tenant memberships.
+type TenantMembershipRepository interface {
+	Save(ctx context.Context, membership *TenantMembership) error
+	// Find returns the user's membership in the tenant, or nil when the user is not a member.
+	Find(ctx context.Context, userID, tenantID uuid.UUID) (*TenantMembership, error)
+	// FindByUser lists the user's memberships, oldest first.
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]TenantMembership, error)
+	// FindMember returns the member of the tenant with their account, or nil when the user is not a
+	// member or has been deleted.
+	FindMember(ctx context.Context, tenantID, userID uuid.UUID) (*TenantMember, error)
+	// FindMembers returns a page of the tenant's members matching the filter.
+	FindMembers(ctx context.Context, tenantID uuid.UUID, page, limit int, filter MemberFilter) (int64, []*TenantMember, error)
+	UpdateStatus(ctx context.Context, userID, tenantID uuid.UUID, status string) error
+	Delete(ctx context.Context, userID, tenantID uuid.UUID) error
+}
@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TokenDenylist records revoked JWT IDs (jti). An entry only needs to be kept until the token
+// it belongs to expires, after which the token is rejected on its own.
+type TokenDenylist interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// Transactor runs units of work. The repository calls made with the context passed to fn share
+// one database transaction, which is committed when fn returns nil and rolled back otherwise.
+// Calls made with the context inside fn must not be run concurrently.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -8,15 +8,19 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID `db:"id"`
-	TenantID     uuid.UUID `db:"tenant_id"`
-	Email        string    `db:"email"`
-	PasswordHash string    `db:"password_hash"`
-	Name         string    `db:"name"`
-	PhoneNumber  string    `db:"phone_number"`
-	Role         string    `db:"role"`
-	CreatedAt    time.Time `db:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at"`
+	ID              uuid.UUID  `db:"id"`
+	TenantID        uuid.UUID  `db:"tenant_id"`
+	Email           string     `db:"email"`
+	PasswordHash    string     `db:"password_hash"`
+	Name            string     `db:"name"`
+	PhoneNumber     string     `db:"phone_number"`
+	Role            string     `db:"role"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+	DisabledAt      *time.Time `db:"disabled_at"`
+	DeletedAt       *time.Time `db:"deleted_at"`
+	ErasedAt        *time.Time `db:"erased_at"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
 }
 
 func (u *User) GenerateID() {
@@ -29,10 +33,40 @@ func (u *User) UpdateTimestamp() {
 	u.UpdatedAt = time.Now()
 }
 
+// IsEmailVerified reports whether the user has confirmed their email address.
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsDisabled reports whether the account has been disabled and can't sign in.
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// IsDeleted reports whether the user has been soft-deleted.
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// IsErased reports whether the user's personal data has been erased. Erased users stay deleted.
+func (u *User) IsErased() bool {
+	return u.ErasedAt != nil
+}
+
+// UserRepository defines the interface for data access operations for users. Reads leave out
+// deleted users unless stated otherwise.
 type UserRepository interface {
 	Save(ctx context.Context, user *User) error
-	FindByEmail(ctx context.Context, email string) (*User, error)
+	// FindByEmail returns the account with the email in the tenant.
+	FindByEmail(ctx context.Context, tenantID uuid.UUID, email string) (*User, error)
+	// FindAllByEmail returns the accounts with the email in every tenant.
+	FindAllByEmail(ctx context.Context, email string) ([]User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
+	// FindByIDIncludingDeleted returns the user even when they have been deleted.
+	FindByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*User, error)
 	Update(ctx context.Context, user *User) error
+	// Delete soft-deletes the user; the row is kept so references to it stay valid.
 	Delete(ctx context.Context, id uuid.UUID) error
+	// Restore undoes the deletion of a user.
+	Restore(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to an account at an external OpenID Connect provider.
+type UserIdentity struct {
+	ID          uuid.UUID  `db:"id"`
+	UserID      uuid.UUID  `db:"user_id"`
+	Provider    string     `db:"provider"`
+	Subject     string     `db:"subject"`
+	Email       string     `db:"email"`
+	CreatedAt   time.Time  `db:"created_at"`
+	LastLoginAt *time.Time `db:"last_login_at"`
+}
+
+// UserIdentityRepository defines the interface for data access operations for external identities.
+type UserIdentityRepository interface {
+	Save(ctx context.Context, identity *UserIdentity) error
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*UserIdentity, error)
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error)
+	// TouchLogin records a login through the identity and the email the provider reported.
+	TouchLogin(ctx context.Context, id uuid.UUID, email string) error
+}
@@ -0,0 +1,65 @@
+// Package breached checks passwords against a local copy of a breached-password list, such as
+// Pwned Passwords, stored in the k-anonymity range format: the list is split into files named
+// after the first five hex characters of the SHA-1 hash of the passwords (e.g. 5BAA6.txt), each
+// holding "SUFFIX:COUNT" lines with the remaining 35 characters. A check only reads the file of
+// the password's prefix, so the whole list never has to be loaded.
+package breached
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const prefixLength = 5
+
+// RangeList is a breached-password list split into hash-prefix files in a directory.
+type RangeList struct {
+	dir string
+}
+
+// OpenRangeList opens the list in dir.
+func OpenRangeList(dir string) (*RangeList, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached-password list: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("breached-password list %s is not a directory", dir)
+	}
+	return &RangeList{dir: dir}, nil
+}
+
+// Contains reports whether the password is in the list. A missing prefix file means that no
+// listed password has the prefix.
+func (l *RangeList) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	file, err := os.Open(filepath.Join(l.dir, prefix+".txt"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read breached-password list: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry, count, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		// Range files may be padded with made-up entries, which have a count of 0.
+		if strings.EqualFold(entry, suffix) && count != "0" {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read breached-password list: %w", err)
+	}
+	return false, nil
+}
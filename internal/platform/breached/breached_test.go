@@ -0,0 +1,58 @@
+package breached
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8, of "P@ssw0rd" it is
+// 21BD12DC183F740EE76F27B78EB39C8AD972A757.
+func TestRangeListContains(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"5BAA6.txt": "003D68EB55068C33ACE09247EE4C639306B:3\r\n" +
+			"1e4c9b93f3f0682250b6cf8331b7ee68fd8:3861493\r\n", // Lowercase hashes match too
+		// Padding entries with a count of 0 aren't breached passwords.
+		"21BD1.txt": "2DC183F740EE76F27B78EB39C8AD972A757:0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := OpenRangeList(dir)
+	if err != nil {
+		t.Fatalf("OpenRangeList() error = %v", err)
+	}
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{password: "password", want: true},
+		{password: "P@ssw0rd", want: false}, // Padding entry
+		{password: "Password", want: false}, // Prefix file missing
+	}
+	for _, tt := range tests {
+		got, err := list.Contains(tt.password)
+		if err != nil {
+			t.Fatalf("Contains(%q) error = %v", tt.password, err)
+		}
+		if got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestOpenRangeListRequiresDirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.txt")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{file, filepath.Join(t.TempDir(), "missing")} {
+		if _, err := OpenRangeList(path); err == nil {
+			t.Errorf("OpenRangeList(%s) error = nil, want an error", path)
+		}
+	}
+}
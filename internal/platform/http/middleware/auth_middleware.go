@@ -4,61 +4,211 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/tenancy"
 	"starterpack-golang-cleanarch/internal/utils"
 	globalErrors "starterpack-golang-cleanarch/internal/utils/errors"
 	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/gorilla/mux"
 )
 
 type ContextKey string
 
 const (
-	ContextKeyUserID   ContextKey = "userID"
-	ContextKeyTenantID ContextKey = "tenantID"
-	ContextKeyUserRole ContextKey = "userRole"
+	ContextKeyUserID         ContextKey = "userID"
+	ContextKeyTenantID       ContextKey = "tenantID"
+	ContextKeyUserRole       ContextKey = "userRole"
+	ContextKeyTokenID        ContextKey = "tokenID"        // JWT ID (jti) of the presented access token
+	ContextKeyTokenExpiresAt ContextKey = "tokenExpiresAt" // Expiry (time.Time) of the presented access token
+	ContextKeyAPIKeyID       ContextKey = "apiKeyID"       // ID of the presented API key; unset for user tokens
 )
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			log.Warnf(r.Context(), "Auth: Missing Authorization header for path: %s", r.URL.Path)
-			utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
-			return
-		}
-
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			log.Warnf(r.Context(), "Auth: Invalid Authorization header format for path: %s", r.URL.Path)
-			utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid Authorization header format", nil), r)
-			return
-		}
-
-		tokenString := tokenParts[1]
-
-		claims, err := utils.ValidateToken(tokenString)
-		if err != nil {
-			log.Warnf(r.Context(), "Auth: Invalid token for path: %s, error: %v", r.URL.Path, err)
-			utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid or expired token", nil), r)
-			return
-		}
-
-		userID := claims.UserID
-		tenantID := claims.TenantID
-		userRole := claims.Role
-
-		if userID == "" || tenantID == "" || userRole == "" {
-			log.Warnf(r.Context(), "Auth: Claims missing (UserID/TenantID/Role) in token for path: %s", r.URL.Path)
-			utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
-			return
-		}
-
-		ctx := context.WithValue(r.Context(), ContextKeyUserID, userID)
-		ctx = context.WithValue(ctx, ContextKeyTenantID, tenantID)
-		ctx = context.WithValue(ctx, ContextKeyUserRole, userRole)
-
-		log.Debugf(ctx, "Auth: Authenticated user %s (Role: %s) for tenant %s accessing path: %s", userID, userRole, tenantID, r.URL.Path)
-
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+// APIKeyRole is the role stored in the context for requests authenticated with an API key.
+const APIKeyRole = "api_key"
+
+// APIKeyAuthenticator looks up an API key. It returns nil when the key is unknown, revoked or expired.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*domain.APIKey, error)
+}
+
+// TenantChecker reports whether a tenant may use the API, i.e. exists and is not suspended.
+type TenantChecker interface {
+	IsTenantActive(ctx context.Context, tenantID string) (bool, error)
+}
+
+// AuthConfig holds the dependencies used by AuthMiddleware.
+type AuthConfig struct {
+	// Denylist rejects access tokens that were revoked (e.g. on logout) before they expired.
+	Denylist domain.TokenDenylist
+	// Permissions resolves the caller's permissions for RequirePermission.
+	Permissions PermissionResolver
+	// APIKeys authenticates API keys; without it, only access tokens are accepted.
+	APIKeys APIKeyAuthenticator
+	// Tenants rejects callers whose tenant is suspended; without it, tenants are not checked.
+	Tenants TenantChecker
+}
+
+// AuthMiddleware validates the Bearer access token and stores its claims in the request context.
+// When configured, it also accepts an API key in the X-API-Key header or as "Authorization: ApiKey <key>",
+// storing the key's tenant and scopes under the same context keys.
+// The context is also scoped to the caller's tenant, which restricts repository queries to it.
+func AuthMiddleware(cfg AuthConfig) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rawKey, ok := apiKeyFromRequest(r); ok && cfg.APIKeys != nil {
+				authenticateAPIKey(w, r, next, cfg, rawKey)
+				return
+			}
+
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				log.Warnf(r.Context(), "Auth: Missing Authorization header for path: %s", r.URL.Path)
+				utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+				return
+			}
+
+			tokenParts := strings.Split(authHeader, " ")
+			if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+				log.Warnf(r.Context(), "Auth: Invalid Authorization header format for path: %s", r.URL.Path)
+				utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid Authorization header format", nil), r)
+				return
+			}
+
+			tokenString := tokenParts[1]
+
+			claims, err := utils.ValidateToken(tokenString)
+			if err != nil {
+				log.Warnf(r.Context(), "Auth: Invalid token for path: %s, error: %v", r.URL.Path, err)
+				utils.HandleHTTPError(w, globalErrors.NewBadRequest("Invalid or expired token", nil), r)
+				return
+			}
+
+			// Tokens issued before token types were introduced carry no type and are access tokens.
+			if claims.TokenType != "" && claims.TokenType != utils.TokenTypeAccess {
+				log.Warnf(r.Context(), "Auth: Token of type %q presented as access token for path: %s", claims.TokenType, r.URL.Path)
+				utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+				return
+			}
+
+			userID := claims.UserID
+			tenantID := claims.TenantID
+			userRole := claims.Role
+
+			if userID == "" || tenantID == "" || userRole == "" {
+				log.Warnf(r.Context(), "Auth: Claims missing (UserID/TenantID/Role) in token for path: %s", r.URL.Path)
+				utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+				return
+			}
+
+			if cfg.Denylist != nil {
+				revoked, err := cfg.Denylist.IsRevoked(r.Context(), claims.ID)
+				if err != nil {
+					utils.HandleHTTPError(w, globalErrors.NewInternalServerError(err, "Failed to check token revocation"), r)
+					return
+				}
+				if revoked {
+					log.Warnf(r.Context(), "Auth: Revoked token %s presented for path: %s", claims.ID, r.URL.Path)
+					utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+					return
+				}
+			}
+
+			if !tenantAllowed(w, r, cfg.Tenants, tenantID) {
+				return
+			}
+
+			var expiresAt time.Time
+			if claims.ExpiresAt != nil {
+				expiresAt = claims.ExpiresAt.Time
+			}
+
+			ctx := context.WithValue(r.Context(), ContextKeyUserID, userID)
+			ctx = context.WithValue(ctx, ContextKeyTenantID, tenantID)
+			ctx = tenancy.WithTenantID(ctx, tenantID)
+			ctx = tenancy.WithUserID(ctx, userID)
+			ctx = context.WithValue(ctx, ContextKeyUserRole, userRole)
+			ctx = context.WithValue(ctx, ContextKeyTokenID, claims.ID)
+			ctx = context.WithValue(ctx, ContextKeyTokenExpiresAt, expiresAt)
+			if cfg.Permissions != nil {
+				permCtx := ctx
+				ctx = context.WithValue(ctx, contextKeyPermissions, newPermissionSet(func() ([]string, error) {
+					return cfg.Permissions.ResolvePermissions(permCtx, tenantID, userID)
+				}))
+			}
+
+			log.Debugf(ctx, "Auth: Authenticated user %s (Role: %s) for tenant %s accessing path: %s", userID, userRole, tenantID, r.URL.Path)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// apiKeyFromRequest reads an API key from the X-API-Key header or an "ApiKey" Authorization header.
+func apiKeyFromRequest(r *http.Request) (string, bool) {
+	if key := strings.TrimSpace(r.Header.Get("X-API-Key")); key != "" {
+		return key, true
+	}
+	scheme, key, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "ApiKey") && strings.TrimSpace(key) != "" {
+		return strings.TrimSpace(key), true
+	}
+	return "", false
+}
+
+// authenticateAPIKey validates the API key and stores the key in the context in place of a user:
+// the user ID is the key's ID and the permissions are the key's scopes.
+func authenticateAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, cfg AuthConfig, rawKey string) {
+	key, err := cfg.APIKeys.AuthenticateAPIKey(r.Context(), rawKey)
+	if err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewInternalServerError(err, "Failed to check API key"), r)
+		return
+	}
+	if key == nil {
+		log.Warnf(r.Context(), "Auth: Invalid, revoked or expired API key for path: %s", r.URL.Path)
+		utils.HandleHTTPError(w, globalErrors.ErrUnauthorized, r)
+		return
+	}
+
+	keyID := key.ID.String()
+	tenantID := key.TenantID.String()
+	scopes := key.Scopes
+
+	if !tenantAllowed(w, r, cfg.Tenants, tenantID) {
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), ContextKeyUserID, keyID)
+	ctx = context.WithValue(ctx, ContextKeyTenantID, tenantID)
+	ctx = tenancy.WithTenantID(ctx, tenantID)
+	ctx = context.WithValue(ctx, ContextKeyUserRole, APIKeyRole)
+	ctx = context.WithValue(ctx, ContextKeyAPIKeyID, keyID)
+	ctx = context.WithValue(ctx, contextKeyPermissions, newPermissionSet(func() ([]string, error) {
+		return scopes, nil
+	}))
+
+	log.Debugf(ctx, "Auth: Authenticated API key %s (%s) for tenant %s accessing path: %s", keyID, key.Prefix, tenantID, r.URL.Path)
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// tenantAllowed checks that the caller's tenant is active. It writes the error response itself and
+// returns false when the request must be rejected.
+func tenantAllowed(w http.ResponseWriter, r *http.Request, tenants TenantChecker, tenantID string) bool {
+	if tenants == nil {
+		return true
+	}
+	active, err := tenants.IsTenantActive(r.Context(), tenantID)
+	if err != nil {
+		utils.HandleHTTPError(w, globalErrors.NewInternalServerError(err, "Failed to check tenant status"), r)
+		return false
+	}
+	if !active {
+		log.Warnf(r.Context(), "Auth: Request for inactive tenant %s rejected for path: %s", tenantID, r.URL.Path)
+		utils.HandleHTTPError(w, globalErrors.ErrTenantSuspended, r)
+		return false
+	}
+	return true
 }
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"starterpack-golang-cleanarch/internal/utils"
+	globalErrors "starterpack-golang-cleanarch/internal/utils/errors"
+	"starterpack-golang-cleanarch/internal/utils/log"
+
+	"github.com/gorilla/mux"
+)
+
+const contextKeyPermissions ContextKey = "permissions"
+
+// PermissionResolver loads the permissions a user holds within a tenant.
+type PermissionResolver interface {
+	ResolvePermissions(ctx context.Context, tenantID, userID string) ([]string, error)
+}
+
+// permissionSet is stored in the request context by AuthMiddleware. Permissions are only loaded
+// the first time a route asks for them, so routes without requirements cost no lookup.
+type permissionSet struct {
+	once        sync.Once
+	load        func() ([]string, error)
+	permissions map[string]struct{}
+	err         error
+}
+
+func newPermissionSet(load func() ([]string, error)) *permissionSet {
+	return &permissionSet{load: load}
+}
+
+func (p *permissionSet) has(permission string) (bool, error) {
+	p.once.Do(func() {
+		perms, err := p.load()
+		if err != nil {
+			p.err = err
+			return
+		}
+		p.permissions = make(map[string]struct{}, len(perms))
+		for _, perm := range perms {
+			p.permissions[perm] = struct{}{}
+		}
+	})
+	if p.err != nil {
+		return false, p.err
+	}
+	_, ok := p.permissions[permission]
+	return ok, nil
+}
+
+// HasPermission reports whether the authenticated caller holds the permission.
+func HasPermission(ctx context.Context, permission string) (bool, error) {
+	set, ok := ctx.Value(contextKeyPermissions).(*permissionSet)
+	if !ok {
+		return false, nil
+	}
+	return set.has(permission)
+}
+
+// RequirePermission only lets a request through when the authenticated caller holds every given
+// permission. It must run after AuthMiddleware, e.g. on routes of the authenticated sub-router:
+//
+//	router.Handle("/employees", middleware.RequirePermission("employees:write")(http.HandlerFunc(h.CreateEmployee)))
+func RequirePermission(permissions ...string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, permission := range permissions {
+				ok, err := HasPermission(r.Context(), permission)
+				if err != nil {
+					utils.HandleHTTPError(w, globalErrors.NewInternalServerError(err, "Failed to resolve permissions"), r)
+					return
+				}
+				if !ok {
+					log.Warnf(r.Context(), "RBAC: Missing permission %s for path: %s", permission, r.URL.Path)
+					utils.HandleHTTPError(w, globalErrors.ErrForbidden, r)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/utils/log"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails. Production deployments plug in an SMTP or email-API implementation;
+// the implementations in this package are meant for local development.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type logMailer struct{}
+
+// NewLogMailer returns a Mailer that writes every message to the application log.
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	log.Infof(ctx, "Mailer: To=%s Subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+type fileMailer struct {
+	dir string
+}
+
+// NewFileMailer returns a Mailer that stores every message as an .eml file in dir.
+func NewFileMailer(dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("mailer: failed to create directory %s: %w", dir, err)
+	}
+	return &fileMailer{dir: dir}, nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405.000000000"), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("mailer: failed to write message: %w", err)
+	}
+	log.Debugf(ctx, "Mailer: Stored message for %s in %s", msg.To, name)
+	return nil
+}
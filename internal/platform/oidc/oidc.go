@@ -0,0 +1,303 @@
+// Package oidc implements the relying-party side of OpenID Connect: discovery, the
+// authorization-code flow with PKCE and ID token validation.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Config describes the client registration at an OpenID provider.
+type Config struct {
+	Issuer       string   `json:"issuer"`        // Issuer URL; the discovery document is read from <issuer>/.well-known/openid-configuration
+	ClientID     string   `json:"client_id"`     // Client ID registered at the provider
+	ClientSecret string   `json:"client_secret"` // Empty for public clients, which rely on PKCE only
+	RedirectURL  string   `json:"redirect_url"`  // Callback URL registered at the provider
+	Scopes       []string `json:"scopes"`        // Requested scopes; "openid" is always added
+}
+
+// Discovery is the subset of the provider metadata used by the client.
+type Discovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	UserinfoEndpoint      string   `json:"userinfo_endpoint,omitempty"`
+	SigningAlgs           []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// TokenResponse is the response of the token endpoint.
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// IDTokenClaims are the ID token claims the application relies on.
+type IDTokenClaims struct {
+	Nonce           string `json:"nonce"`
+	Email           string `json:"email"`
+	EmailVerified   bool   `json:"email_verified"`
+	Name            string `json:"name"`
+	AuthorizedParty string `json:"azp,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// keyRefreshInterval throttles JWKS reloads triggered by unknown key IDs.
+const keyRefreshInterval = time.Minute
+
+// Client talks to one OpenID provider. The discovery document and signing keys are fetched
+// lazily and cached.
+type Client struct {
+	cfg        Config
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	discovery     *Discovery
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+}
+
+// NewClient creates a client for the provider. A nil httpClient uses a client with a 10s timeout.
+func NewClient(cfg Config, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	return &Client{cfg: cfg, httpClient: httpClient}
+}
+
+// Discover returns the provider metadata, fetching it on first use.
+func (c *Client) Discover(ctx context.Context) (*Discovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.discoverLocked(ctx)
+}
+
+func (c *Client) discoverLocked(ctx context.Context) (*Discovery, error) {
+	if c.discovery != nil {
+		return c.discovery, nil
+	}
+	var d Discovery
+	if err := c.getJSON(ctx, c.cfg.Issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, fmt.Errorf("oidc: discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != c.cfg.Issuer {
+		return nil, fmt.Errorf("oidc: discovery issuer %q does not match configured issuer %q", d.Issuer, c.cfg.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc: discovery document of %s is incomplete", c.cfg.Issuer)
+	}
+	c.discovery = &d
+	return c.discovery, nil
+}
+
+// AuthCodeURL returns the URL to send the user to. codeChallenge is the S256 PKCE challenge.
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", c.cfg.ClientID)
+	params.Set("redirect_uri", c.cfg.RedirectURL)
+	params.Set("scope", strings.Join(c.scopes(), " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange redeems an authorization code at the token endpoint.
+func (c *Client) Exchange(ctx context.Context, code, codeVerifier string) (*TokenResponse, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.cfg.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if c.cfg.ClientSecret == "" {
+		form.Set("client_id", c.cfg.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: failed to build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.ClientSecret != "" {
+		// client_secret_basic, the default client authentication method (RFC 6749, section 2.3.1).
+		req.SetBasicAuth(url.QueryEscape(c.cfg.ClientID), url.QueryEscape(c.cfg.ClientSecret))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var token TokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("oidc: invalid token response: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("oidc: token response has no id_token")
+	}
+	return &token, nil
+}
+
+// VerifyIDToken validates the ID token signature, issuer, audience, expiry and nonce, and
+// returns its claims.
+func (c *Client) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDTokenClaims, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := &IDTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (interface{}, error) { return c.verificationKey(ctx, token) },
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(d.Issuer), // Exactly as published, including a trailing slash if any
+		jwt.WithAudience(c.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: invalid ID token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("oidc: ID token has no subject")
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("oidc: ID token nonce mismatch")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != c.cfg.ClientID {
+		return nil, fmt.Errorf("oidc: ID token azp %q does not match client", claims.AuthorizedParty)
+	}
+	return claims, nil
+}
+
+// verificationKey returns the provider key for the token's "kid", reloading the JWKS when the
+// key is unknown, e.g. after the provider rotated its keys.
+func (c *Client) verificationKey(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.lookupKeyLocked(kid); ok {
+		return key, nil
+	}
+	if time.Since(c.keysFetchedAt) < keyRefreshInterval && c.keys != nil {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if err := c.fetchKeysLocked(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := c.lookupKeyLocked(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (c *Client) lookupKeyLocked(kid string) (interface{}, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+func (c *Client) fetchKeysLocked(ctx context.Context) error {
+	d, err := c.discoverLocked(ctx)
+	if err != nil {
+		return err
+	}
+	var set jsonWebKeySet
+	if err := c.getJSON(ctx, d.JWKSURI, &set); err != nil {
+		return fmt.Errorf("oidc: failed to fetch JWKS: %w", err)
+	}
+	keys, err := set.publicKeys()
+	if err != nil {
+		return err
+	}
+	c.keys = keys
+	c.keysFetchedAt = time.Now()
+	return nil
+}
+
+func (c *Client) scopes() []string {
+	scopes := []string{"openid"}
+	for _, scope := range c.cfg.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+func (c *Client) getJSON(ctx context.Context, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", rawURL, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// RandomString returns a URL-safe random string, used for state, nonce and PKCE verifiers.
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("oidc: failed to generate random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallengeS256 derives the PKCE code challenge from a code verifier (RFC 7636).
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,230 @@
+// Package spreadsheet reads tabular files uploaded by users: CSV, and the first worksheet of
+// Office Open XML workbooks (.xlsx) without needing a spreadsheet library. Rows keep the line
+// number they have in the file, so errors can point users at the right row.
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPartSize bounds the uncompressed size of the parts read from a workbook, so a small
+// compressed upload can't expand without limit.
+const maxPartSize = 64 << 20
+
+// Row is a row of a spreadsheet. Line is 1 for the first row of the file.
+type Row struct {
+	Line  int
+	Cells []string
+}
+
+// ReadCSV reads every row of a CSV file. A UTF-8 byte order mark, which spreadsheet programs
+// often write, is removed.
+func ReadCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) == 0 && len(record) > 0 {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+		}
+		rows = append(rows, Row{Line: line, Cells: record})
+	}
+	return rows, nil
+}
+
+// ReadXLSX reads the rows of the first worksheet of an .xlsx workbook. Cells are returned as
+// stored: numbers and dates keep their raw value (see ExcelDate).
+func ReadXLSX(r io.ReaderAt, size int64) ([]Row, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workbook: %w", err)
+	}
+	parts := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		parts[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(parts)
+	if err != nil {
+		return nil, err
+	}
+	sharedStrings, err := readSharedStrings(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Number int `xml:"r,attr"`
+			Cells  []struct {
+				Ref    string     `xml:"r,attr"`
+				Type   string     `xml:"t,attr"`
+				Value  string     `xml:"v"`
+				Inline stringItem `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodePart(parts, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(sheet.Rows))
+	for i, sheetRow := range sheet.Rows {
+		row := Row{Line: sheetRow.Number}
+		if row.Line == 0 {
+			row.Line = i + 1
+		}
+		for j, cell := range sheetRow.Cells {
+			column := j
+			if cell.Ref != "" {
+				if column, err = columnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(row.Cells) <= column {
+				row.Cells = append(row.Cells, "")
+			}
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(sharedStrings) {
+					return nil, fmt.Errorf("cell %s refers to a missing shared string", cell.Ref)
+				}
+				row.Cells[column] = sharedStrings[index]
+			case "inlineStr":
+				row.Cells[column] = cell.Inline.text()
+			case "b":
+				if cell.Value == "1" {
+					row.Cells[column] = "TRUE"
+				} else {
+					row.Cells[column] = "FALSE"
+				}
+			default:
+				row.Cells[column] = cell.Value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ExcelDate converts a date stored as a serial number, the days since 1899-12-30, to a date.
+func ExcelDate(serial float64) time.Time {
+	return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial))
+}
+
+// stringItem is a string in a workbook: plain text, or runs of differently formatted text.
+type stringItem struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (s stringItem) text() string {
+	var b strings.Builder
+	b.WriteString(s.Text)
+	for _, run := range s.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+// firstSheetPath finds the part of the first worksheet through the workbook's relationships.
+func firstSheetPath(parts map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			RelationshipID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodePart(parts, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("workbook has no worksheets")
+	}
+
+	var relationships struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(parts, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return "", err
+	}
+	for _, rel := range relationships.Items {
+		if rel.ID == workbook.Sheets[0].RelationshipID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return "", errors.New("workbook has no part for its first worksheet")
+}
+
+func readSharedStrings(parts map[string]*zip.File) ([]string, error) {
+	if parts["xl/sharedStrings.xml"] == nil {
+		return nil, nil // Workbooks without text cells have no shared strings
+	}
+	var table struct {
+		Items []stringItem `xml:"si"`
+	}
+	if err := decodePart(parts, "xl/sharedStrings.xml", &table); err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(table.Items))
+	for i, item := range table.Items {
+		strs[i] = item.text()
+	}
+	return strs, nil
+}
+
+func decodePart(parts map[string]*zip.File, name string, v interface{}) error {
+	f := parts[name]
+	if f == nil {
+		return fmt.Errorf("workbook has no %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, maxPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return nil
+}
+
+// columnIndex returns the zero-based column of a cell reference such as "C12".
+func columnIndex(ref string) (int, error) {
+	column := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		column = column*26 + int(c-'A') + 1
+	}
+	if column == 0 || column > 16384 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return column - 1, nil
+}
@@ -0,0 +1,217 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testWorkbook = `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+  <sheets><sheet name="Employees" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+	testWorkbookRels = `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+	testSharedStrings = `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <si><t>name</t></si>
+  <si><t>email</t></si>
+  <si><r><t>Ada </t></r><r><t>Lovelace</t></r></si>
+</sst>`
+)
+
+// newWorkbook builds an .xlsx file with the given first worksheet and, unless empty, shared strings.
+func newWorkbook(t *testing.T, sheet, sharedStrings string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/workbook.xml":            testWorkbook,
+		"xl/_rels/workbook.xml.rels": testWorkbookRels,
+		"xl/worksheets/sheet1.xml":   sheet,
+	}
+	if sharedStrings != "" {
+		parts["xl/sharedStrings.xml"] = sharedStrings
+	}
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func worksheet(rows string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+func TestReadXLSX(t *testing.T) {
+	tests := []struct {
+		name          string
+		sheet         string
+		sharedStrings string
+		want          []Row
+		wantErr       string
+	}{
+		{
+			name: "minimal workbook",
+			sheet: worksheet(`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+				<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>45000</v></c></row>`),
+			sharedStrings: testSharedStrings,
+			want: []Row{
+				{Line: 1, Cells: []string{"name", "email"}},
+				{Line: 2, Cells: []string{"Ada Lovelace", "45000"}},
+			},
+		},
+		{
+			name:  "inline strings and booleans",
+			sheet: worksheet(`<row r="1"><c r="A1" t="inlineStr"><is><t>Grace</t></is></c><c r="B1" t="b"><v>1</v></c><c r="C1" t="b"><v>0</v></c></row>`),
+			want:  []Row{{Line: 1, Cells: []string{"Grace", "TRUE", "FALSE"}}},
+		},
+		{
+			name:  "sparse cells and rows",
+			sheet: worksheet(`<row r="1"><c r="A1"><v>1</v></c><c r="D1"><v>4</v></c></row><row r="5"><c r="AA5"><v>27</v></c></row>`),
+			want: []Row{
+				{Line: 1, Cells: []string{"1", "", "", "4"}},
+				{Line: 5, Cells: append(make([]string, 26), "27")},
+			},
+		},
+		{
+			name:  "cells and rows without references",
+			sheet: worksheet(`<row><c><v>a</v></c><c><v>b</v></c></row><row><c><v>c</v></c></row>`),
+			want: []Row{
+				{Line: 1, Cells: []string{"a", "b"}},
+				{Line: 2, Cells: []string{"c"}},
+			},
+		},
+		{
+			name:    "bad cell reference",
+			sheet:   worksheet(`<row r="1"><c r="1A"><v>1</v></c></row>`),
+			wantErr: `invalid cell reference "1A"`,
+		},
+		{
+			name:    "column beyond the last one",
+			sheet:   worksheet(`<row r="1"><c r="XFE1"><v>1</v></c></row>`),
+			wantErr: `invalid cell reference "XFE1"`,
+		},
+		{
+			name:          "missing shared string",
+			sheet:         worksheet(`<row r="1"><c r="A1" t="s"><v>7</v></c></row>`),
+			sharedStrings: testSharedStrings,
+			wantErr:       "cell A1 refers to a missing shared string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := newWorkbook(t, tt.sheet, tt.sharedStrings)
+			got, err := ReadXLSX(file, file.Size())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadXLSX() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadXLSX() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadXLSX() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadXLSXNotAWorkbook(t *testing.T) {
+	file := bytes.NewReader([]byte("name,email\n"))
+	if _, err := ReadXLSX(file, file.Size()); err == nil {
+		t.Fatal("ReadXLSX() error = nil, want an error for a file that is not a ZIP archive")
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Row
+		wantErr bool
+	}{
+		{
+			name:  "plain",
+			input: "name,email\nAda,ada@example.com\n",
+			want: []Row{
+				{Line: 1, Cells: []string{"name", "email"}},
+				{Line: 2, Cells: []string{"Ada", "ada@example.com"}},
+			},
+		},
+		{
+			name:  "byte order mark",
+			input: "\ufeffname,email\nAda,ada@example.com\n",
+			want: []Row{
+				{Line: 1, Cells: []string{"name", "email"}},
+				{Line: 2, Cells: []string{"Ada", "ada@example.com"}},
+			},
+		},
+		{
+			name:  "rows of different lengths keep their line",
+			input: "name,email\n\n\"Grace\nHopper\",grace@example.com,extra\nAda\n",
+			want: []Row{
+				{Line: 1, Cells: []string{"name", "email"}},
+				{Line: 3, Cells: []string{"Grace\nHopper", "grace@example.com", "extra"}},
+				{Line: 5, Cells: []string{"Ada"}},
+			},
+		},
+		{
+			name:    "unterminated quote",
+			input:   "name\n\"Ada\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadCSV(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ReadCSV() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadCSV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcelDate(t *testing.T) {
+	tests := []struct {
+		serial float64
+		want   string
+	}{
+		{serial: 1, want: "1899-12-31"},
+		{serial: 61, want: "1900-03-01"},
+		{serial: 45000, want: "2023-03-15"},
+		{serial: 45000.75, want: "2023-03-15"}, // The time of day is dropped
+	}
+	for _, tt := range tests {
+		if got := ExcelDate(tt.serial).Format(time.DateOnly); got != tt.want {
+			t.Errorf("ExcelDate(%v) = %s, want %s", tt.serial, got, tt.want)
+		}
+	}
+}
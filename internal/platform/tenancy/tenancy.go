@@ -0,0 +1,36 @@
+// Package tenancy carries the tenant a request acts on from the HTTP layer down to the repositories,
+// which restrict their queries to it with PostgreSQL row-level security.
+package tenancy
+
+import "context"
+
+type contextKey int
+
+const (
+	tenantIDKey contextKey = iota
+	userIDKey
+)
+
+// WithTenantID returns a copy of ctx scoped to the tenant.
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey, tenantID)
+}
+
+// TenantID returns the tenant ctx is scoped to. It reports false for unscoped contexts, e.g. during
+// login, when the tenant is not known yet.
+func TenantID(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantIDKey).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// WithUserID returns a copy of ctx that records the caller. Scoped queries can then also see the
+// caller's own rows outside the tenant, such as their memberships in other tenants.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID returns the caller recorded in ctx, or an empty string.
+func UserID(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
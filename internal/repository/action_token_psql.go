@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLActionTokenRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLActionTokenRepository(db *sqlx.DB) domain.ActionTokenRepository {
+	return &postgreSQLActionTokenRepository{db: newUnscopedDB(db)}
+}
+
+func (r *postgreSQLActionTokenRepository) Save(ctx context.Context, token *domain.ActionToken) error {
+	query := `INSERT INTO user_action_tokens (id, user_id, purpose, token_hash, expires_at, created_at)
+              VALUES (:id, :user_id, :purpose, :token_hash, :expires_at, :created_at)`
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		return fmt.Errorf("actionTokenRepo.Save: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLActionTokenRepository) FindValid(ctx context.Context, purpose, tokenHash string) (*domain.ActionToken, error) {
+	var token domain.ActionToken
+	query := `SELECT id, user_id, purpose, token_hash, expires_at, consumed_at, created_at
+              FROM user_action_tokens
+              WHERE purpose = $1 AND token_hash = $2 AND consumed_at IS NULL AND expires_at > NOW()`
+	err := r.db.GetContext(ctx, &token, query, purpose, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("actionTokenRepo.FindValid: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *postgreSQLActionTokenRepository) Consume(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `UPDATE user_action_tokens SET consumed_at = NOW()
+              WHERE id = $1 AND consumed_at IS NULL AND expires_at > NOW()`
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("actionTokenRepo.Consume: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("actionTokenRepo.Consume: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *postgreSQLActionTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error {
+	query := `UPDATE user_action_tokens SET consumed_at = NOW()
+              WHERE user_id = $1 AND purpose = $2 AND consumed_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, userID, purpose)
+	if err != nil {
+		return fmt.Errorf("actionTokenRepo.InvalidateForUser: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type postgreSQLAPIKeyRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLAPIKeyRepository(db *sqlx.DB) domain.APIKeyRepository {
+	return &postgreSQLAPIKeyRepository{db: newScopedDB(db)}
+}
+
+// apiKeyRow maps the scopes array column, which domain.APIKey leaves to the repository.
+type apiKeyRow struct {
+	domain.APIKey
+	Scopes pq.StringArray `db:"scopes"`
+}
+
+func (r apiKeyRow) toDomain() domain.APIKey {
+	key := r.APIKey
+	key.Scopes = []string(r.Scopes)
+	return key
+}
+
+const apiKeyColumns = `id, tenant_id, name, prefix, key_hash, scopes, created_by, expires_at, last_used_at, revoked_at, created_at`
+
+func (r *postgreSQLAPIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	query := `INSERT INTO api_keys (` + apiKeyColumns + `)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := r.db.ExecContext(ctx, query, key.ID, key.TenantID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes),
+		key.CreatedBy, key.ExpiresAt, key.LastUsedAt, key.RevokedAt, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("apiKeyRepo.Save: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLAPIKeyRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.APIKey, error) {
+	var row apiKeyRow
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1 AND tenant_id = $2`
+	err := r.db.GetContext(ctx, &row, query, id, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("apiKeyRepo.FindByID: %w", err)
+	}
+	key := row.toDomain()
+	return &key, nil
+}
+
+func (r *postgreSQLAPIKeyRepository) FindAll(ctx context.Context, tenantID uuid.UUID) ([]domain.APIKey, error) {
+	var rows []apiKeyRow
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE tenant_id = $1 ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &rows, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("apiKeyRepo.FindAll: %w", err)
+	}
+	keys := make([]domain.APIKey, len(rows))
+	for i, row := range rows {
+		keys[i] = row.toDomain()
+	}
+	return keys, nil
+}
+
+func (r *postgreSQLAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var row apiKeyRow
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+	err := r.db.GetContext(ctx, &row, query, keyHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("apiKeyRepo.FindByHash: %w", err)
+	}
+	key := row.toDomain()
+	return &key, nil
+}
+
+func (r *postgreSQLAPIKeyRepository) Revoke(ctx context.Context, tenantID, id uuid.UUID) (bool, error) {
+	query := `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return false, fmt.Errorf("apiKeyRepo.Revoke: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("apiKeyRepo.Revoke: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *postgreSQLAPIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, at)
+	if err != nil {
+		return fmt.Errorf("apiKeyRepo.TouchLastUsed: %w", err)
+	}
+	return nil
+}
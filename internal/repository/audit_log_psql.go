@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLAuditLogRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLAuditLogRepository(db *sqlx.DB) domain.AuditLogRepository {
+	return &postgreSQLAuditLogRepository{db: newScopedDB(db)}
+}
+
+func (r *postgreSQLAuditLogRepository) Save(ctx context.Context, entry *domain.AuditLog) error {
+	query := `INSERT INTO audit_logs (id, tenant_id, actor_id, action, target_type, target_id, details, created_at)
+              VALUES (:id, :tenant_id, :actor_id, :action, :target_type, :target_id, :details, :created_at)`
+	_, err := r.db.NamedExecContext(ctx, query, entry)
+	if err != nil {
+		return fmt.Errorf("auditLogRepo.Save: %w", err)
+	}
+	return nil
+}
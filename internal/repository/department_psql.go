@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type postgreSQLDepartmentRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLDepartmentRepository(db *sqlx.DB) domain.DepartmentRepository {
+	return &postgreSQLDepartmentRepository{db: newScopedDB(db)}
+}
+
+const departmentColumns = `id, tenant_id, name, parent_id, created_at, updated_at`
+
+func (r *postgreSQLDepartmentRepository) Save(ctx context.Context, department *domain.Department) error {
+	query := `INSERT INTO departments (` + departmentColumns + `)
+              VALUES (:id, :tenant_id, :name, :parent_id, :created_at, :updated_at)`
+	_, err := r.db.NamedExecContext(ctx, query, department)
+	if err != nil {
+		return fmt.Errorf("departmentRepo.Save: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLDepartmentRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Department, error) {
+	var department domain.Department
+	query := `SELECT ` + departmentColumns + ` FROM departments WHERE id = $1 AND tenant_id = $2`
+	err := r.db.GetContext(ctx, &department, query, id, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("departmentRepo.FindByID: %w", err)
+	}
+	return &department, nil
+}
+
+func (r *postgreSQLDepartmentRepository) FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.Department, error) {
+	var department domain.Department
+	query := `SELECT ` + departmentColumns + ` FROM departments WHERE lower(name) = lower($1) AND tenant_id = $2`
+	err := r.db.GetContext(ctx, &department, query, name, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("departmentRepo.FindByName: %w", err)
+	}
+	return &department, nil
+}
+
+func (r *postgreSQLDepartmentRepository) FindAll(ctx context.Context, tenantID uuid.UUID) ([]*domain.Department, error) {
+	var departments []*domain.Department
+	query := `SELECT ` + departmentColumns + ` FROM departments WHERE tenant_id = $1 ORDER BY name ASC`
+	err := r.db.SelectContext(ctx, &departments, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("departmentRepo.FindAll: %w", err)
+	}
+	return departments, nil
+}
+
+func (r *postgreSQLDepartmentRepository) Update(ctx context.Context, department *domain.Department) error {
+	query := `UPDATE departments SET name = :name, parent_id = :parent_id, updated_at = :updated_at
+              WHERE id = :id AND tenant_id = :tenant_id`
+	_, err := r.db.NamedExecContext(ctx, query, department)
+	if err != nil {
+		return fmt.Errorf("departmentRepo.Update: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLDepartmentRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	query := `DELETE FROM departments WHERE id = $1 AND tenant_id = $2`
+	_, err := r.db.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "departments_parent_id_fkey" {
+			return domain.ErrDepartmentHasChildren
+		}
+		return fmt.Errorf("departmentRepo.Delete: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLDepartmentRepository) LockHierarchy(ctx context.Context, tenantID uuid.UUID) error {
+	query := `SELECT pg_advisory_xact_lock(hashtext('departments.parent_id'), hashtext($1::text))`
+	_, err := r.db.ExecContext(ctx, query, tenantID)
+	if err != nil {
+		return fmt.Errorf("departmentRepo.LockHierarchy: %w", err)
+	}
+	return nil
+}
@@ -3,57 +3,62 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"starterpack-golang-cleanarch/internal/domain"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	// Tetap import ini jika GenerateID() di domain masih pakai uuid.New().String()
+	"github.com/lib/pq"
 )
 
 type postgreSQLEmployeeRepository struct {
-	db *sqlx.DB
+	db *scopedDB
 }
 
 func NewPostgreSQLEmployeeRepository(db *sqlx.DB) domain.EmployeeRepository {
-	return &postgreSQLEmployeeRepository{db: db}
+	return &postgreSQLEmployeeRepository{db: newScopedDB(db)}
 }
 
-// Save menyimpan data Employee baru ke database.
+const employeeColumns = `id, tenant_id, name, email, phone_number, status, job_title, department_id, hire_date, manager_id,
+	created_at, updated_at`
+
+const insertEmployeeQuery = `INSERT INTO employees (` + employeeColumns + `)
+              VALUES (:id, :tenant_id, :name, :email, :phone_number, :status, :job_title, :department_id, :hire_date, :manager_id,
+                      :created_at, :updated_at)`
+
+// Save inserts a new Employee.
 func (r *postgreSQLEmployeeRepository) Save(ctx context.Context, emp *domain.Employee) error {
-	// ID (SERIAL) akan di-generate oleh database, jadi tidak perlu disertakan di sini.
-	// PostgreSQL akan mengembalikan ID yang di-generate.
-	// FIX: Tambahkan password_hash ke query INSERT
-	query := `INSERT INTO users (tenant_id, name, email, phone_number, password_hash, created_at, updated_at)
-              VALUES (:tenant_id, :name, :email, :phone_number, :password_hash, :created_at, :updated_at)
-              RETURNING id`
-
-	// NamedQueryContext dan Scan untuk mendapatkan ID yang di-generate
-	rows, err := r.db.NamedQueryContext(ctx, query, emp)
+	_, err := r.db.NamedExecContext(ctx, insertEmployeeQuery, emp)
 	if err != nil {
-		return fmt.Errorf("employeeRepo.Save: %w", err)
+		return fmt.Errorf("employeeRepo.Save: %w", employeeEmailTaken(err, emp.Email))
 	}
-	defer rows.Close()
+	return nil
+}
 
-	if rows.Next() {
-		err := rows.Scan(&emp.ID) // Scan ID yang di-generate kembali ke struct Employee
-		if err != nil {
-			return fmt.Errorf("employeeRepo.Save: failed to scan ID: %w", err)
+// SaveAll inserts Employees in one transaction, in the given order.
+func (r *postgreSQLEmployeeRepository) SaveAll(ctx context.Context, employees []*domain.Employee) error {
+	err := r.db.transact(ctx, func(q sqlx.ExtContext) error {
+		for _, emp := range employees {
+			if _, err := sqlx.NamedExecContext(ctx, q, insertEmployeeQuery, emp); err != nil {
+				return fmt.Errorf("employee %s: %w", emp.Email, employeeEmailTaken(err, emp.Email))
+			}
 		}
-	} else {
-		return fmt.Errorf("employeeRepo.Save: no ID returned after insert")
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("employeeRepo.SaveAll: %w", err)
 	}
-
 	return nil
 }
 
-// FindByID mencari Employee berdasarkan ID (int64) dan TenantID (string).
-func (r *postgreSQLEmployeeRepository) FindByID(ctx context.Context, tenantID string, id int64) (*domain.Employee, error) {
+// FindByID finds an Employee of the tenant by ID.
+func (r *postgreSQLEmployeeRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Employee, error) {
 	var emp domain.Employee
-	// FIX: Tambahkan password_hash ke query SELECT
-	query := `SELECT id, tenant_id, name, email, phone_number, password_hash, created_at, updated_at
-              FROM users WHERE id = $1 AND tenant_id = $2`
+	query := `SELECT ` + employeeColumns + ` FROM employees WHERE id = $1 AND tenant_id = $2`
 	err := r.db.GetContext(ctx, &emp, query, id, tenantID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -64,12 +69,10 @@ func (r *postgreSQLEmployeeRepository) FindByID(ctx context.Context, tenantID st
 	return &emp, nil
 }
 
-// FindByEmail mencari Employee berdasarkan email dan TenantID (string).
-func (r *postgreSQLEmployeeRepository) FindByEmail(ctx context.Context, tenantID string, email string) (*domain.Employee, error) {
+// FindByEmail finds an Employee of the tenant by email.
+func (r *postgreSQLEmployeeRepository) FindByEmail(ctx context.Context, tenantID uuid.UUID, email string) (*domain.Employee, error) {
 	var emp domain.Employee
-	// FIX: Tambahkan password_hash ke query SELECT
-	query := `SELECT id, tenant_id, name, email, phone_number, password_hash, created_at, updated_at
-              FROM users WHERE email = $1 AND tenant_id = $2`
+	query := `SELECT ` + employeeColumns + ` FROM employees WHERE email = $1 AND tenant_id = $2`
 	err := r.db.GetContext(ctx, &emp, query, email, tenantID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -80,21 +83,61 @@ func (r *postgreSQLEmployeeRepository) FindByEmail(ctx context.Context, tenantID
 	return &emp, nil
 }
 
+// FindByEmails finds the Employees of the tenant with any of the emails.
+func (r *postgreSQLEmployeeRepository) FindByEmails(ctx context.Context, tenantID uuid.UUID, emails []string) ([]*domain.Employee, error) {
+	var employees []*domain.Employee
+	query := `SELECT ` + employeeColumns + ` FROM employees WHERE email = ANY($1) AND tenant_id = $2`
+	err := r.db.SelectContext(ctx, &employees, query, pq.Array(emails), tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("employeeRepo.FindByEmails: %w", err)
+	}
+	return employees, nil
+}
+
 // FindAll retrieves a list of Employees with pagination and filtering.
-func (r *postgreSQLEmployeeRepository) FindAll(ctx context.Context, tenantID string, page, limit int, query string) (int64, []*domain.Employee, error) {
+func (r *postgreSQLEmployeeRepository) FindAll(ctx context.Context, tenantID uuid.UUID, page, limit int, filter domain.EmployeeFilter) (int64, []*domain.Employee, error) {
 	offset := (page - 1) * limit
 	var employees []*domain.Employee
 	var total int64
 
-	baseQuery := `FROM users WHERE tenant_id = $1`
+	baseQuery := `FROM employees WHERE tenant_id = $1`
 	args := []interface{}{tenantID}
 	argCounter := 2
 
-	if query != "" {
-		searchQuery := `(name ILIKE $` + strconv.Itoa(argCounter) + ` OR email ILIKE $` + strconv.Itoa(argCounter+1) + ` OR phone_number ILIKE $` + strconv.Itoa(argCounter+2) + `)`
-		baseQuery += " AND " + searchQuery
-		args = append(args, "%"+query+"%", "%"+query+"%", "%"+query+"%")
-		argCounter += 3
+	if filter.Query != "" {
+		baseQuery += ` AND (name ILIKE $` + strconv.Itoa(argCounter) + ` OR email ILIKE $` + strconv.Itoa(argCounter) + ` OR phone_number ILIKE $` + strconv.Itoa(argCounter) + `)`
+		args = append(args, "%"+filter.Query+"%")
+		argCounter++
+	}
+	if filter.Status != "" {
+		baseQuery += ` AND status = $` + strconv.Itoa(argCounter)
+		args = append(args, filter.Status)
+		argCounter++
+	}
+	if filter.JobTitle != "" {
+		baseQuery += ` AND LOWER(job_title) = LOWER($` + strconv.Itoa(argCounter) + `)`
+		args = append(args, filter.JobTitle)
+		argCounter++
+	}
+	if filter.DepartmentID != nil {
+		baseQuery += ` AND department_id = $` + strconv.Itoa(argCounter)
+		args = append(args, *filter.DepartmentID)
+		argCounter++
+	}
+	if filter.ManagerID != nil {
+		baseQuery += ` AND manager_id = $` + strconv.Itoa(argCounter)
+		args = append(args, *filter.ManagerID)
+		argCounter++
+	}
+	if filter.HiredFrom != nil {
+		baseQuery += ` AND hire_date >= $` + strconv.Itoa(argCounter)
+		args = append(args, *filter.HiredFrom)
+		argCounter++
+	}
+	if filter.HiredTo != nil {
+		baseQuery += ` AND hire_date <= $` + strconv.Itoa(argCounter)
+		args = append(args, *filter.HiredTo)
+		argCounter++
 	}
 
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) %s`, baseQuery)
@@ -103,9 +146,7 @@ func (r *postgreSQLEmployeeRepository) FindAll(ctx context.Context, tenantID str
 		return 0, nil, fmt.Errorf("employeeRepo.FindAll count: %w", err)
 	}
 
-	// FIX: Tambahkan password_hash ke query SELECT
-	dataQuery := fmt.Sprintf(`SELECT id, tenant_id, name, email, phone_number, password_hash, created_at, updated_at
-                              %s ORDER BY name ASC LIMIT $%d OFFSET $%d`,
+	dataQuery := fmt.Sprintf(`SELECT `+employeeColumns+` %s ORDER BY name ASC LIMIT $%d OFFSET $%d`,
 		baseQuery, argCounter, argCounter+1)
 	args = append(args, limit, offset)
 
@@ -117,23 +158,88 @@ func (r *postgreSQLEmployeeRepository) FindAll(ctx context.Context, tenantID str
 	return total, employees, nil
 }
 
-// Update an existing Employee.
-func (r *postgreSQLEmployeeRepository) Update(ctx context.Context, emp *domain.Employee) error {
-	query := `UPDATE users SET name = :name, email = :email, phone_number = :phone_number, password_hash = :password_hash, updated_at = :updated_at
-              WHERE id = :id AND tenant_id = :tenant_id`
-	_, err := r.db.NamedExecContext(ctx, query, emp)
+// FindManagementChain walks up the manager references of an Employee.
+func (r *postgreSQLEmployeeRepository) FindManagementChain(ctx context.Context, tenantID, id uuid.UUID) ([]*domain.Employee, error) {
+	var managers []*domain.Employee
+	query := `WITH RECURSIVE chain AS (
+                  SELECT m.*, 1 AS depth, ARRAY[e.id, m.id] AS path
+                  FROM employees e JOIN employees m ON m.id = e.manager_id AND m.tenant_id = e.tenant_id
+                  WHERE e.id = $1 AND e.tenant_id = $2
+                  UNION ALL
+                  SELECT m.*, c.depth + 1, c.path || m.id
+                  FROM chain c JOIN employees m ON m.id = c.manager_id AND m.tenant_id = c.tenant_id
+                  WHERE NOT m.id = ANY(c.path)
+              )
+              SELECT ` + employeeColumns + ` FROM chain ORDER BY depth ASC`
+	err := r.db.SelectContext(ctx, &managers, query, id, tenantID)
 	if err != nil {
-		return fmt.Errorf("employeeRepo.Update: %w", err)
+		return nil, fmt.Errorf("employeeRepo.FindManagementChain: %w", err)
+	}
+	return managers, nil
+}
+
+func (r *postgreSQLEmployeeRepository) LockHierarchy(ctx context.Context, tenantID uuid.UUID) error {
+	query := `SELECT pg_advisory_xact_lock(hashtext('employees.manager_id'), hashtext($1::text))`
+	_, err := r.db.ExecContext(ctx, query, tenantID)
+	if err != nil {
+		return fmt.Errorf("employeeRepo.LockHierarchy: %w", err)
 	}
 	return nil
 }
 
+// FindReports walks down the manager references to an Employee.
+func (r *postgreSQLEmployeeRepository) FindReports(ctx context.Context, tenantID, id uuid.UUID) ([]*domain.EmployeeReport, error) {
+	var reports []*domain.EmployeeReport
+	query := `WITH RECURSIVE reports AS (
+                  SELECT e.*, 1 AS depth, ARRAY[$1::uuid, e.id] AS path
+                  FROM employees e
+                  WHERE e.manager_id = $1 AND e.tenant_id = $2
+                  UNION ALL
+                  SELECT e.*, r.depth + 1, r.path || e.id
+                  FROM reports r JOIN employees e ON e.manager_id = r.id AND e.tenant_id = r.tenant_id
+                  WHERE NOT e.id = ANY(r.path)
+              )
+              SELECT ` + employeeColumns + `, depth FROM reports ORDER BY depth ASC, name ASC`
+	err := r.db.SelectContext(ctx, &reports, query, id, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("employeeRepo.FindReports: %w", err)
+	}
+	return reports, nil
+}
+
+// Update an existing Employee that wasn't changed since it was read.
+func (r *postgreSQLEmployeeRepository) Update(ctx context.Context, emp *domain.Employee, previousUpdatedAt time.Time) (bool, error) {
+	query := `UPDATE employees SET name = $3, email = $4, phone_number = $5, status = $6,
+              job_title = $7, department_id = $8, hire_date = $9, manager_id = $10, updated_at = $11
+              WHERE id = $1 AND tenant_id = $2 AND updated_at = $12`
+	res, err := r.db.ExecContext(ctx, query, emp.ID, emp.TenantID, emp.Name, emp.Email, emp.PhoneNumber, emp.Status,
+		emp.JobTitle, emp.DepartmentID, emp.HireDate, emp.ManagerID, emp.UpdatedAt, previousUpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("employeeRepo.Update: %w", employeeEmailTaken(err, emp.Email))
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("employeeRepo.Update: %w", err)
+	}
+	return affected == 1, nil
+}
+
 // Delete an Employee by ID and TenantID.
-func (r *postgreSQLEmployeeRepository) Delete(ctx context.Context, tenantID string, id int64) error {
-	query := `DELETE FROM users WHERE id = $1 AND tenant_id = $2`
+func (r *postgreSQLEmployeeRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	query := `DELETE FROM employees WHERE id = $1 AND tenant_id = $2`
 	_, err := r.db.ExecContext(ctx, query, id, tenantID)
 	if err != nil {
 		return fmt.Errorf("employeeRepo.Delete: %w", err)
 	}
 	return nil
 }
+
+// employeeEmailTaken turns a violation of the unique email index into a
+// domain.EmployeeEmailTakenError, and returns other errors as is.
+func employeeEmailTaken(err error, email string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_employees_tenant_email" {
+		return &domain.EmployeeEmailTakenError{Email: email}
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLInvitationRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLInvitationRepository(db *sqlx.DB) domain.InvitationRepository {
+	return &postgreSQLInvitationRepository{db: newScopedDB(db)}
+}
+
+const invitationColumns = `id, tenant_id, email, role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at`
+
+// invitationPending is the condition for invitations that can still be accepted.
+const invitationPending = `accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()`
+
+func (r *postgreSQLInvitationRepository) Save(ctx context.Context, invitation *domain.Invitation) error {
+	query := `INSERT INTO invitations (` + invitationColumns + `)
+              VALUES (:id, :tenant_id, :email, :role, :token_hash, :invited_by, :expires_at, :accepted_at, :revoked_at, :created_at)`
+	_, err := r.db.NamedExecContext(ctx, query, invitation)
+	if err != nil {
+		return fmt.Errorf("invitationRepo.Save: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLInvitationRepository) FindPending(ctx context.Context, tenantID uuid.UUID) ([]domain.Invitation, error) {
+	var invitations []domain.Invitation
+	query := `SELECT ` + invitationColumns + ` FROM invitations WHERE tenant_id = $1 AND ` + invitationPending + ` ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &invitations, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("invitationRepo.FindPending: %w", err)
+	}
+	return invitations, nil
+}
+
+func (r *postgreSQLInvitationRepository) FindValidByHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	query := `SELECT ` + invitationColumns + ` FROM invitations WHERE token_hash = $1 AND ` + invitationPending
+	err := r.db.GetContext(ctx, &invitation, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invitationRepo.FindValidByHash: %w", err)
+	}
+	return &invitation, nil
+}
+
+func (r *postgreSQLInvitationRepository) Accept(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `UPDATE invitations SET accepted_at = NOW() WHERE id = $1 AND ` + invitationPending
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("invitationRepo.Accept: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("invitationRepo.Accept: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *postgreSQLInvitationRepository) Revoke(ctx context.Context, tenantID, id uuid.UUID) (bool, error) {
+	query := `UPDATE invitations SET revoked_at = NOW() WHERE id = $1 AND tenant_id = $2 AND ` + invitationPending
+	res, err := r.db.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return false, fmt.Errorf("invitationRepo.Revoke: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("invitationRepo.Revoke: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *postgreSQLInvitationRepository) RevokePendingForEmail(ctx context.Context, tenantID uuid.UUID, email string) error {
+	query := `UPDATE invitations SET revoked_at = NOW() WHERE tenant_id = $1 AND email = $2 AND ` + invitationPending
+	_, err := r.db.ExecContext(ctx, query, tenantID, email)
+	if err != nil {
+		return fmt.Errorf("invitationRepo.RevokePendingForEmail: %w", err)
+	}
+	return nil
+}
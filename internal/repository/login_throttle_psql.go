@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLLoginThrottleRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgreSQLLoginThrottleRepository(db *sqlx.DB) domain.LoginThrottleRepository {
+	return &postgreSQLLoginThrottleRepository{db: db}
+}
+
+func (r *postgreSQLLoginThrottleRepository) Find(ctx context.Context, scope, key string) (*domain.LoginThrottle, error) {
+	var throttle domain.LoginThrottle
+	query := `SELECT scope, key, failures, last_failure_at, locked_until FROM login_throttles WHERE scope = $1 AND key = $2`
+	err := r.db.GetContext(ctx, &throttle, query, scope, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("loginThrottleRepo.Find: %w", err)
+	}
+	return &throttle, nil
+}
+
+func (r *postgreSQLLoginThrottleRepository) RecordFailure(ctx context.Context, scope, key string, window time.Duration) (*domain.LoginThrottle, error) {
+	var throttle domain.LoginThrottle
+	query := `INSERT INTO login_throttles (scope, key, failures, last_failure_at)
+              VALUES ($1, $2, 1, NOW())
+              ON CONFLICT (scope, key) DO UPDATE
+              SET failures = CASE
+                      WHEN login_throttles.last_failure_at < NOW() - $3 * INTERVAL '1 second'
+                        OR login_throttles.locked_until < NOW() THEN 1
+                      ELSE login_throttles.failures + 1
+                  END,
+                  locked_until = CASE WHEN login_throttles.locked_until < NOW() THEN NULL ELSE login_throttles.locked_until END,
+                  last_failure_at = NOW()
+              RETURNING scope, key, failures, last_failure_at, locked_until`
+	err := r.db.GetContext(ctx, &throttle, query, scope, key, int64(window.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("loginThrottleRepo.RecordFailure: %w", err)
+	}
+	return &throttle, nil
+}
+
+func (r *postgreSQLLoginThrottleRepository) Lock(ctx context.Context, scope, key string, until time.Time) error {
+	query := `UPDATE login_throttles SET locked_until = $3 WHERE scope = $1 AND key = $2`
+	_, err := r.db.ExecContext(ctx, query, scope, key, until)
+	if err != nil {
+		return fmt.Errorf("loginThrottleRepo.Lock: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLLoginThrottleRepository) Reset(ctx context.Context, scope, key string) error {
+	query := `DELETE FROM login_throttles WHERE scope = $1 AND key = $2`
+	_, err := r.db.ExecContext(ctx, query, scope, key)
+	if err != nil {
+		return fmt.Errorf("loginThrottleRepo.Reset: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/utils/secretbox"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLMFARepository struct {
+	db  *scopedDB
+	box *secretbox.Box
+}
+
+// NewPostgreSQLMFARepository returns the MFA repository. TOTP secrets are encrypted with box
+// before they are stored.
+func NewPostgreSQLMFARepository(db *sqlx.DB, box *secretbox.Box) domain.MFARepository {
+	return &postgreSQLMFARepository{db: newUnscopedDB(db), box: box}
+}
+
+func (r *postgreSQLMFARepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserMFA, error) {
+	var mfa domain.UserMFA
+	query := `SELECT user_id, totp_secret, enabled_at, last_used_step, created_at, updated_at
+              FROM user_mfa WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &mfa, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("mfaRepo.FindByUserID: %w", err)
+	}
+	if mfa.TOTPSecret, err = r.box.Open(mfa.TOTPSecret); err != nil {
+		return nil, fmt.Errorf("mfaRepo.FindByUserID: %w", err)
+	}
+	return &mfa, nil
+}
+
+func (r *postgreSQLMFARepository) SavePending(ctx context.Context, mfa *domain.UserMFA) error {
+	query := `INSERT INTO user_mfa (user_id, totp_secret, enabled_at, last_used_step, created_at, updated_at)
+              VALUES (:user_id, :totp_secret, NULL, 0, :created_at, :updated_at)
+              ON CONFLICT (user_id) DO UPDATE
+              SET totp_secret = EXCLUDED.totp_secret, enabled_at = NULL, last_used_step = 0, updated_at = EXCLUDED.updated_at`
+	sealed := *mfa
+	var err error
+	if sealed.TOTPSecret, err = r.box.Seal(mfa.TOTPSecret); err != nil {
+		return fmt.Errorf("mfaRepo.SavePending: %w", err)
+	}
+	_, err = r.db.NamedExecContext(ctx, query, &sealed)
+	if err != nil {
+		return fmt.Errorf("mfaRepo.SavePending: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLMFARepository) Enable(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE user_mfa SET enabled_at = NOW(), updated_at = NOW() WHERE user_id = $1`
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("mfaRepo.Enable: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLMFARepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.transact(ctx, func(q sqlx.ExtContext) error {
+		if _, err := q.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return fmt.Errorf("recovery codes: %w", err)
+		}
+		_, err := q.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("mfaRepo.Delete: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLMFARepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	query := `UPDATE user_mfa SET last_used_step = $2, updated_at = NOW() WHERE user_id = $1 AND last_used_step < $2`
+	res, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("mfaRepo.UseStep: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("mfaRepo.UseStep: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *postgreSQLMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	err := r.db.transact(ctx, func(q sqlx.ExtContext) error {
+		if _, err := q.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		for _, hash := range codeHashes {
+			if _, err := q.ExecContext(ctx, `INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash); err != nil {
+				return fmt.Errorf("insert: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("mfaRepo.ReplaceRecoveryCodes: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLMFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	query := `UPDATE mfa_recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("mfaRepo.UseRecoveryCode: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("mfaRepo.UseRecoveryCode: %w", err)
+	}
+	return affected == 1, nil
+}
+
+// EncryptMFASecrets encrypts the TOTP secrets still stored in plain text, from before they were
+// encrypted. It is run at startup and does nothing once every secret is encrypted.
+func EncryptMFASecrets(ctx context.Context, db *sqlx.DB, box *secretbox.Box) (int, error) {
+	var count int
+	err := newUnscopedDB(db).transact(ctx, func(q sqlx.ExtContext) error {
+		var rows []struct {
+			UserID     uuid.UUID `db:"user_id"`
+			TOTPSecret string    `db:"totp_secret"`
+		}
+		query := `SELECT user_id, totp_secret FROM user_mfa WHERE totp_secret NOT LIKE 'v1:%' FOR UPDATE`
+		if err := sqlx.SelectContext(ctx, q, &rows, query); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			sealed, err := box.Seal(row.TOTPSecret)
+			if err != nil {
+				return err
+			}
+			if _, err := q.ExecContext(ctx, `UPDATE user_mfa SET totp_secret = $2 WHERE user_id = $1`, row.UserID, sealed); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("mfaRepo.EncryptMFASecrets: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLPasswordHistoryRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLPasswordHistoryRepository(db *sqlx.DB) domain.PasswordHistoryRepository {
+	return &postgreSQLPasswordHistoryRepository{db: newUnscopedDB(db)}
+}
+
+func (r *postgreSQLPasswordHistoryRepository) Add(ctx context.Context, userID uuid.UUID, passwordHash string, keep int) error {
+	err := r.db.transact(ctx, func(q sqlx.ExtContext) error {
+		if _, err := q.ExecContext(ctx, `INSERT INTO password_history (user_id, password_hash) VALUES ($1, $2)`, userID, passwordHash); err != nil {
+			return err
+		}
+		prune := `DELETE FROM password_history WHERE user_id = $1 AND id NOT IN (
+                      SELECT id FROM password_history WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2)`
+		if _, err := q.ExecContext(ctx, prune, userID, keep); err != nil {
+			return fmt.Errorf("prune: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("passwordHistoryRepo.Add: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLPasswordHistoryRepository) FindRecent(ctx context.Context, userID uuid.UUID, limit int) ([]string, error) {
+	var hashes []string
+	query := `SELECT password_hash FROM password_history WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2`
+	if err := r.db.SelectContext(ctx, &hashes, query, userID, limit); err != nil {
+		return nil, fmt.Errorf("passwordHistoryRepo.FindRecent: %w", err)
+	}
+	return hashes, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLPasswordPolicyRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLPasswordPolicyRepository(db *sqlx.DB) domain.PasswordPolicyRepository {
+	return &postgreSQLPasswordPolicyRepository{db: newScopedDB(db)}
+}
+
+const passwordPolicyColumns = `tenant_id, min_length, require_uppercase, require_lowercase, require_digit, require_symbol, history_count, check_breached, updated_at`
+
+func (r *postgreSQLPasswordPolicyRepository) Find(ctx context.Context, tenantID uuid.UUID) (*domain.PasswordPolicy, error) {
+	var policy domain.PasswordPolicy
+	query := `SELECT ` + passwordPolicyColumns + ` FROM password_policies WHERE tenant_id = $1`
+	err := r.db.GetContext(ctx, &policy, query, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("passwordPolicyRepo.Find: %w", err)
+	}
+	return &policy, nil
+}
+
+func (r *postgreSQLPasswordPolicyRepository) Save(ctx context.Context, policy *domain.PasswordPolicy) error {
+	query := `INSERT INTO password_policies (` + passwordPolicyColumns + `)
+              VALUES (:tenant_id, :min_length, :require_uppercase, :require_lowercase, :require_digit, :require_symbol, :history_count, :check_breached, :updated_at)
+              ON CONFLICT (tenant_id) DO UPDATE SET
+                  min_length = EXCLUDED.min_length, require_uppercase = EXCLUDED.require_uppercase,
+                  require_lowercase = EXCLUDED.require_lowercase, require_digit = EXCLUDED.require_digit,
+                  require_symbol = EXCLUDED.require_symbol, history_count = EXCLUDED.history_count,
+                  check_breached = EXCLUDED.check_breached, updated_at = EXCLUDED.updated_at`
+	_, err := r.db.NamedExecContext(ctx, query, policy)
+	if err != nil {
+		return fmt.Errorf("passwordPolicyRepo.Save: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type postgreSQLPersonalDataRepository struct {
+	db *scopedDB
+}
+
+// NewPostgreSQLPersonalDataRepository creates the repository. It reads and writes across tenants,
+// so it is only used by the privacy request worker, never with a tenant-scoped context.
+func NewPostgreSQLPersonalDataRepository(db *sqlx.DB) domain.PersonalDataRepository {
+	return &postgreSQLPersonalDataRepository{db: newUnscopedDB(db)}
+}
+
+// personalDataSource is a table holding data about a user. The condition selects the user's rows
+// (aliased t) by $1, the user's ID or, with byEmail, their email; omit lists the secret columns
+// left out of exports.
+type personalDataSource struct {
+	name      string
+	table     string
+	condition string
+	byEmail   bool
+	omit      []string
+}
+
+// personalDataSources lists every table holding data about a user. Add new tables here.
+var personalDataSources = []personalDataSource{
+	{name: "user", table: "users", condition: "t.id = $1", omit: []string{"password_hash"}},
+	{name: "tenant_memberships", table: "tenant_memberships", condition: "t.user_id = $1"},
+	{name: "user_roles", table: "user_roles", condition: "t.user_id = $1"},
+	{name: "user_identities", table: "user_identities", condition: "t.user_id = $1"},
+	{name: "mfa", table: "user_mfa", condition: "t.user_id = $1", omit: []string{"totp_secret"}},
+	{name: "mfa_recovery_codes", table: "mfa_recovery_codes", condition: "t.user_id = $1", omit: []string{"code_hash"}},
+	{name: "password_history", table: "password_history", condition: "t.user_id = $1", omit: []string{"password_hash"}},
+	{name: "sessions", table: "refresh_tokens", condition: "t.user_id = $1"},
+	{name: "action_tokens", table: "user_action_tokens", condition: "t.user_id = $1", omit: []string{"token_hash"}},
+	{name: "api_keys_created", table: "api_keys", condition: "t.created_by = $1", omit: []string{"key_hash"}},
+	{name: "invitations_sent", table: "invitations", condition: "t.invited_by = $1", omit: []string{"token_hash"}},
+	{name: "invitations_received", table: "invitations", condition: "lower(t.email) = lower($1)", byEmail: true, omit: []string{"token_hash"}},
+	{name: "login_throttles", table: "login_throttles", condition: "t.scope = 'account' AND t.key = lower($1)", byEmail: true},
+	{name: "privacy_requests", table: "privacy_requests", condition: "t.user_id = $1", omit: []string{"file_path"}},
+	{name: "audit_logs", table: "audit_logs", condition: "t.target_id = $1 OR t.actor_id = $1"},
+}
+
+func (r *postgreSQLPersonalDataRepository) Collect(ctx context.Context, user *domain.User) (map[string]json.RawMessage, error) {
+	data := make(map[string]json.RawMessage, len(personalDataSources))
+	for _, source := range personalDataSources {
+		var key interface{} = user.ID
+		if source.byEmail {
+			key = user.Email
+		}
+		query := fmt.Sprintf(`SELECT COALESCE(jsonb_agg(to_jsonb(t) - COALESCE($2::text[], '{}')), '[]'::jsonb) FROM %s t WHERE %s`, source.table, source.condition)
+		var rows []byte
+		if err := r.db.GetContext(ctx, &rows, query, key, pq.Array(source.omit)); err != nil {
+			return nil, fmt.Errorf("personalDataRepo.Collect %s: %w", source.name, err)
+		}
+		data[source.name] = rows
+	}
+	return data, nil
+}
+
+// Erase keeps the user row and everything referencing it, with the personal data replaced, so
+// records such as audit logs and other modules' foreign keys stay valid.
+func (r *postgreSQLPersonalDataRepository) Erase(ctx context.Context, user *domain.User) error {
+	err := r.db.transact(ctx, func(q sqlx.ExtContext) error {
+		// The password hash can't be empty; an unusable value keeps the account from ever signing in.
+		anonymizeUser := `UPDATE users SET name = 'Erased user', email = 'erased-' || id || '@invalid', phone_number = '',
+                  password_hash = '!erased', disabled_at = COALESCE(disabled_at, NOW()), deleted_at = COALESCE(deleted_at, NOW()),
+                  erased_at = NOW(), updated_at = NOW()
+                  WHERE id = $1`
+		if _, err := q.ExecContext(ctx, anonymizeUser, user.ID); err != nil {
+			return fmt.Errorf("user: %w", err)
+		}
+		if _, err := q.ExecContext(ctx, `UPDATE invitations SET email = 'erased-' || id || '@invalid' WHERE lower(email) = lower($1)`, user.Email); err != nil {
+			return fmt.Errorf("invitations: %w", err)
+		}
+		deletes := []struct{ name, query string }{
+			{"identities", `DELETE FROM user_identities WHERE user_id = $1`},
+			{"password history", `DELETE FROM password_history WHERE user_id = $1`},
+			{"recovery codes", `DELETE FROM mfa_recovery_codes WHERE user_id = $1`},
+			{"mfa", `DELETE FROM user_mfa WHERE user_id = $1`},
+			{"action tokens", `DELETE FROM user_action_tokens WHERE user_id = $1`},
+		}
+		for _, d := range deletes {
+			if _, err := q.ExecContext(ctx, d.query, user.ID); err != nil {
+				return fmt.Errorf("%s: %w", d.name, err)
+			}
+		}
+		if _, err := q.ExecContext(ctx, `DELETE FROM login_throttles WHERE scope = 'account' AND key = lower($1)`, user.Email); err != nil {
+			return fmt.Errorf("login throttles: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("personalDataRepo.Erase: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLPrivacyRequestRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLPrivacyRequestRepository(db *sqlx.DB) domain.PrivacyRequestRepository {
+	return &postgreSQLPrivacyRequestRepository{db: newScopedDB(db)}
+}
+
+const privacyRequestColumns = `id, tenant_id, user_id, type, status, requested_by, file_path, error, expires_at, completed_at, claimed_at, created_at`
+
+func (r *postgreSQLPrivacyRequestRepository) Save(ctx context.Context, request *domain.PrivacyRequest) error {
+	if request.Status == "" {
+		request.Status = domain.PrivacyRequestPending
+	}
+	query := `INSERT INTO privacy_requests (` + privacyRequestColumns + `)
+              VALUES (:id, :tenant_id, :user_id, :type, :status, :requested_by, :file_path, :error, :expires_at, :completed_at, :claimed_at, :created_at)`
+	_, err := r.db.NamedExecContext(ctx, query, request)
+	if err != nil {
+		return fmt.Errorf("privacyRequestRepo.Save: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLPrivacyRequestRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.PrivacyRequest, error) {
+	var request domain.PrivacyRequest
+	query := `SELECT ` + privacyRequestColumns + ` FROM privacy_requests WHERE tenant_id = $1 AND id = $2`
+	err := r.db.GetContext(ctx, &request, query, tenantID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("privacyRequestRepo.FindByID: %w", err)
+	}
+	return &request, nil
+}
+
+func (r *postgreSQLPrivacyRequestRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]domain.PrivacyRequest, error) {
+	var requests []domain.PrivacyRequest
+	query := `SELECT ` + privacyRequestColumns + ` FROM privacy_requests WHERE user_id = $1 ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &requests, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("privacyRequestRepo.FindByUser: %w", err)
+	}
+	return requests, nil
+}
+
+// ClaimNext skips rows locked by other workers, so each request is processed once. Requests
+// claimed before claimed_at was recorded count as stale.
+func (r *postgreSQLPrivacyRequestRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (*domain.PrivacyRequest, error) {
+	var request domain.PrivacyRequest
+	query := `UPDATE privacy_requests SET status = $1, claimed_at = NOW()
+              WHERE id = (SELECT id FROM privacy_requests
+                          WHERE status = $2 OR (status = $1 AND (claimed_at IS NULL OR claimed_at < $3))
+                          ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED)
+              RETURNING ` + privacyRequestColumns
+	err := r.db.GetContext(ctx, &request, query, domain.PrivacyRequestProcessing, domain.PrivacyRequestPending, staleBefore)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("privacyRequestRepo.ClaimNext: %w", err)
+	}
+	return &request, nil
+}
+
+func (r *postgreSQLPrivacyRequestRepository) Complete(ctx context.Context, id uuid.UUID, filePath *string, expiresAt *time.Time) error {
+	query := `UPDATE privacy_requests SET status = $2, file_path = $3, expires_at = $4, error = NULL, completed_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, domain.PrivacyRequestCompleted, filePath, expiresAt)
+	if err != nil {
+		return fmt.Errorf("privacyRequestRepo.Complete: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLPrivacyRequestRepository) Fail(ctx context.Context, id uuid.UUID, message string) error {
+	query := `UPDATE privacy_requests SET status = $2, error = $3, completed_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, domain.PrivacyRequestFailed, message)
+	if err != nil {
+		return fmt.Errorf("privacyRequestRepo.Fail: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLPrivacyRequestRepository) FindExpiredExports(ctx context.Context, now time.Time) ([]domain.PrivacyRequest, error) {
+	var requests []domain.PrivacyRequest
+	query := `SELECT ` + privacyRequestColumns + ` FROM privacy_requests
+              WHERE type = $1 AND status = $2 AND expires_at <= $3`
+	err := r.db.SelectContext(ctx, &requests, query, domain.PrivacyRequestExport, domain.PrivacyRequestCompleted, now)
+	if err != nil {
+		return nil, fmt.Errorf("privacyRequestRepo.FindExpiredExports: %w", err)
+	}
+	return requests, nil
+}
+
+func (r *postgreSQLPrivacyRequestRepository) MarkExpired(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE privacy_requests SET status = $2, file_path = NULL WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, domain.PrivacyRequestExpired)
+	if err != nil {
+		return fmt.Errorf("privacyRequestRepo.MarkExpired: %w", err)
+	}
+	return nil
+}
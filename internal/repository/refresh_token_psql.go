@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLRefreshTokenRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLRefreshTokenRepository(db *sqlx.DB) domain.RefreshTokenRepository {
+	return &postgreSQLRefreshTokenRepository{db: newUnscopedDB(db)}
+}
+
+func (r *postgreSQLRefreshTokenRepository) Save(ctx context.Context, token *domain.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, family_id, user_id, tenant_id, expires_at, access_token_id, access_expires_at, created_at)
+              VALUES (:id, :family_id, :user_id, :tenant_id, :expires_at, :access_token_id, :access_expires_at, :created_at)`
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		return fmt.Errorf("refreshTokenRepo.Save: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLRefreshTokenRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	query := `SELECT id, family_id, user_id, tenant_id, expires_at, access_token_id, access_expires_at, rotated_at, replaced_by, revoked_at, created_at
+              FROM refresh_tokens WHERE id = $1`
+	err := r.db.GetContext(ctx, &token, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("refreshTokenRepo.FindByID: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *postgreSQLRefreshTokenRepository) FindByAccessTokenID(ctx context.Context, accessTokenID uuid.UUID) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	query := `SELECT id, family_id, user_id, tenant_id, expires_at, access_token_id, access_expires_at, rotated_at, replaced_by, revoked_at, created_at
+              FROM refresh_tokens WHERE access_token_id = $1`
+	err := r.db.GetContext(ctx, &token, query, accessTokenID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("refreshTokenRepo.FindByAccessTokenID: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *postgreSQLRefreshTokenRepository) FindWithLiveAccessToken(ctx context.Context, userID uuid.UUID) ([]*domain.RefreshToken, error) {
+	var tokens []*domain.RefreshToken
+	query := `SELECT id, family_id, user_id, tenant_id, expires_at, access_token_id, access_expires_at, rotated_at, replaced_by, revoked_at, created_at
+              FROM refresh_tokens WHERE user_id = $1 AND access_token_id IS NOT NULL AND access_expires_at > NOW()`
+	err := r.db.SelectContext(ctx, &tokens, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("refreshTokenRepo.FindWithLiveAccessToken: %w", err)
+	}
+	return tokens, nil
+}
+
+// Rotate only succeeds for a token that is still active, so two concurrent refreshes with the
+// same token cannot both win.
+func (r *postgreSQLRefreshTokenRepository) Rotate(ctx context.Context, id, replacedBy uuid.UUID) (bool, error) {
+	query := `UPDATE refresh_tokens SET rotated_at = NOW(), replaced_by = $2
+              WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, id, replacedBy)
+	if err != nil {
+		return false, fmt.Errorf("refreshTokenRepo.Rotate: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("refreshTokenRepo.Rotate: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *postgreSQLRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	if err != nil {
+		return fmt.Errorf("refreshTokenRepo.RevokeFamily: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("refreshTokenRepo.RevokeAllForUser: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLRefreshTokenRepository) RevokeAllForUserExceptFamily(ctx context.Context, userID, familyID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, userID, familyID)
+	if err != nil {
+		return fmt.Errorf("refreshTokenRepo.RevokeAllForUserExceptFamily: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLRefreshTokenRepository) RevokeAllForUserInTenant(ctx context.Context, userID, tenantID, homeTenantID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW()
+              WHERE user_id = $1 AND COALESCE(tenant_id, $3) = $2 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, userID, tenantID, homeTenantID)
+	if err != nil {
+		return fmt.Errorf("refreshTokenRepo.RevokeAllForUserInTenant: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type postgreSQLRoleRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLRoleRepository(db *sqlx.DB) domain.RoleRepository {
+	return &postgreSQLRoleRepository{db: newScopedDB(db)}
+}
+
+func (r *postgreSQLRoleRepository) FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.Role, error) {
+	var role domain.Role
+	// A tenant's own role takes precedence over a built-in role with the same name.
+	query := `SELECT id, tenant_id, name, description, created_at, updated_at
+              FROM roles WHERE name = $1 AND (tenant_id = $2 OR tenant_id IS NULL)
+              ORDER BY tenant_id NULLS LAST LIMIT 1`
+	err := r.db.GetContext(ctx, &role, query, name, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("roleRepo.FindByName: %w", err)
+	}
+	if err := r.loadPermissions(ctx, []*domain.Role{&role}); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *postgreSQLRoleRepository) FindAll(ctx context.Context, tenantID uuid.UUID) ([]*domain.Role, error) {
+	var roles []*domain.Role
+	query := `SELECT id, tenant_id, name, description, created_at, updated_at
+              FROM roles WHERE tenant_id = $1 OR tenant_id IS NULL ORDER BY name ASC`
+	if err := r.db.SelectContext(ctx, &roles, query, tenantID); err != nil {
+		return nil, fmt.Errorf("roleRepo.FindAll: %w", err)
+	}
+	if err := r.loadPermissions(ctx, roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *postgreSQLRoleRepository) FindByUser(ctx context.Context, tenantID, userID uuid.UUID) ([]*domain.Role, error) {
+	var roles []*domain.Role
+	query := `SELECT r.id, r.tenant_id, r.name, r.description, r.created_at, r.updated_at
+              FROM roles r JOIN user_roles ur ON ur.role_id = r.id
+              WHERE ur.tenant_id = $1 AND ur.user_id = $2 ORDER BY r.name ASC`
+	if err := r.db.SelectContext(ctx, &roles, query, tenantID, userID); err != nil {
+		return nil, fmt.Errorf("roleRepo.FindByUser: %w", err)
+	}
+	if err := r.loadPermissions(ctx, roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *postgreSQLRoleRepository) AssignToUser(ctx context.Context, tenantID, userID, roleID uuid.UUID) error {
+	query := `INSERT INTO user_roles (user_id, tenant_id, role_id) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, userID, tenantID, roleID)
+	if err != nil {
+		return fmt.Errorf("roleRepo.AssignToUser: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLRoleRepository) RemoveFromUser(ctx context.Context, tenantID, userID, roleID uuid.UUID) error {
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND tenant_id = $2 AND role_id = $3`
+	_, err := r.db.ExecContext(ctx, query, userID, tenantID, roleID)
+	if err != nil {
+		return fmt.Errorf("roleRepo.RemoveFromUser: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLRoleRepository) FindPermissionsByUser(ctx context.Context, tenantID, userID uuid.UUID) ([]string, error) {
+	var permissions []string
+	query := `SELECT DISTINCT rp.permission
+              FROM user_roles ur JOIN role_permissions rp ON rp.role_id = ur.role_id
+              WHERE ur.tenant_id = $1 AND ur.user_id = $2`
+	if err := r.db.SelectContext(ctx, &permissions, query, tenantID, userID); err != nil {
+		return nil, fmt.Errorf("roleRepo.FindPermissionsByUser: %w", err)
+	}
+	return permissions, nil
+}
+
+// loadPermissions fills the Permissions field of the given roles with a single query.
+func (r *postgreSQLRoleRepository) loadPermissions(ctx context.Context, roles []*domain.Role) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*domain.Role, len(roles))
+	ids := make([]string, len(roles))
+	for i, role := range roles {
+		role.Permissions = []string{}
+		byID[role.ID] = role
+		ids[i] = role.ID.String()
+	}
+
+	var rows []struct {
+		RoleID     uuid.UUID `db:"role_id"`
+		Permission string    `db:"permission"`
+	}
+	query := `SELECT role_id, permission FROM role_permissions WHERE role_id = ANY($1::uuid[]) ORDER BY permission ASC`
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(ids)); err != nil {
+		return fmt.Errorf("roleRepo.loadPermissions: %w", err)
+	}
+	for _, row := range rows {
+		if role, ok := byID[row.RoleID]; ok {
+			role.Permissions = append(role.Permissions, row.Permission)
+		}
+	}
+	return nil
+}
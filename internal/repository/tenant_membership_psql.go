@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLTenantMembershipRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLTenantMembershipRepository(db *sqlx.DB) domain.TenantMembershipRepository {
+	return &postgreSQLTenantMembershipRepository{db: newScopedDB(db)}
+}
+
+const membershipColumns = `user_id, tenant_id, role, status, created_at`
+
+// memberColumns selects a user (u) together with their membership (m) as a domain.TenantMember.
+const memberColumns = `u.id, u.tenant_id, u.email, u.password_hash, u.name, u.phone_number, u.role, u.email_verified_at, u.disabled_at, u.deleted_at, u.erased_at, u.created_at, u.updated_at,
+              m.role AS member_role, m.status AS member_status, m.created_at AS joined_at`
+
+// Save adds the membership. Saving an existing membership leaves it unchanged.
+func (r *postgreSQLTenantMembershipRepository) Save(ctx context.Context, membership *domain.TenantMembership) error {
+	if membership.Status == "" {
+		membership.Status = domain.MembershipStatusActive
+	}
+	query := `INSERT INTO tenant_memberships (` + membershipColumns + `)
+              VALUES (:user_id, :tenant_id, :role, :status, :created_at)
+              ON CONFLICT (user_id, tenant_id) DO NOTHING`
+	_, err := r.db.NamedExecContext(ctx, query, membership)
+	if err != nil {
+		return fmt.Errorf("tenantMembershipRepo.Save: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLTenantMembershipRepository) Find(ctx context.Context, userID, tenantID uuid.UUID) (*domain.TenantMembership, error) {
+	var membership domain.TenantMembership
+	query := `SELECT ` + membershipColumns + ` FROM tenant_memberships WHERE user_id = $1 AND tenant_id = $2`
+	err := r.db.GetContext(ctx, &membership, query, userID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("tenantMembershipRepo.Find: %w", err)
+	}
+	return &membership, nil
+}
+
+func (r *postgreSQLTenantMembershipRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]domain.TenantMembership, error) {
+	var memberships []domain.TenantMembership
+	query := `SELECT ` + membershipColumns + ` FROM tenant_memberships WHERE user_id = $1 ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &memberships, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("tenantMembershipRepo.FindByUser: %w", err)
+	}
+	return memberships, nil
+}
+
+func (r *postgreSQLTenantMembershipRepository) FindMember(ctx context.Context, tenantID, userID uuid.UUID) (*domain.TenantMember, error) {
+	var member domain.TenantMember
+	query := `SELECT ` + memberColumns + `
+              FROM tenant_memberships m JOIN users u ON u.id = m.user_id
+              WHERE m.tenant_id = $1 AND m.user_id = $2 AND u.deleted_at IS NULL`
+	err := r.db.GetContext(ctx, &member, query, tenantID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("tenantMembershipRepo.FindMember: %w", err)
+	}
+	return &member, nil
+}
+
+// FindMembers leaves out deleted users, unless filter.Deleted asks for the deleted users the tenant owns.
+func (r *postgreSQLTenantMembershipRepository) FindMembers(ctx context.Context, tenantID uuid.UUID, page, limit int, filter domain.MemberFilter) (int64, []*domain.TenantMember, error) {
+	offset := (page - 1) * limit
+	var members []*domain.TenantMember
+	var total int64
+
+	baseQuery := `FROM tenant_memberships m JOIN users u ON u.id = m.user_id WHERE m.tenant_id = $1`
+	args := []interface{}{tenantID}
+	argCounter := 2
+
+	if filter.Deleted {
+		baseQuery += ` AND u.deleted_at IS NOT NULL AND u.tenant_id = m.tenant_id`
+	} else {
+		baseQuery += ` AND u.deleted_at IS NULL`
+	}
+	if filter.Status != "" {
+		baseQuery += ` AND m.status = $` + strconv.Itoa(argCounter)
+		args = append(args, filter.Status)
+		argCounter++
+	}
+	if filter.Query != "" {
+		baseQuery += ` AND (u.name ILIKE $` + strconv.Itoa(argCounter) + ` OR u.email ILIKE $` + strconv.Itoa(argCounter) + `)`
+		args = append(args, "%"+filter.Query+"%")
+		argCounter++
+	}
+
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) %s`, baseQuery)
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return 0, nil, fmt.Errorf("tenantMembershipRepo.FindMembers count: %w", err)
+	}
+
+	dataQuery := fmt.Sprintf(`SELECT `+memberColumns+` %s ORDER BY u.name ASC, u.email ASC LIMIT $%d OFFSET $%d`, baseQuery, argCounter, argCounter+1)
+	args = append(args, limit, offset)
+	if err := r.db.SelectContext(ctx, &members, dataQuery, args...); err != nil {
+		return 0, nil, fmt.Errorf("tenantMembershipRepo.FindMembers data: %w", err)
+	}
+
+	return total, members, nil
+}
+
+func (r *postgreSQLTenantMembershipRepository) UpdateStatus(ctx context.Context, userID, tenantID uuid.UUID, status string) error {
+	query := `UPDATE tenant_memberships SET status = $3 WHERE user_id = $1 AND tenant_id = $2`
+	_, err := r.db.ExecContext(ctx, query, userID, tenantID, status)
+	if err != nil {
+		return fmt.Errorf("tenantMembershipRepo.UpdateStatus: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLTenantMembershipRepository) Delete(ctx context.Context, userID, tenantID uuid.UUID) error {
+	query := `DELETE FROM tenant_memberships WHERE user_id = $1 AND tenant_id = $2`
+	_, err := r.db.ExecContext(ctx, query, userID, tenantID)
+	if err != nil {
+		return fmt.Errorf("tenantMembershipRepo.Delete: %w", err)
+	}
+	return nil
+}
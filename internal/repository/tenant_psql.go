@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLTenantRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLTenantRepository(db *sqlx.DB) domain.TenantRepository {
+	return &postgreSQLTenantRepository{db: newUnscopedDB(db)}
+}
+
+const tenantColumns = `id, name, slug, status, plan, settings, created_at, updated_at`
+
+func (r *postgreSQLTenantRepository) Save(ctx context.Context, tenant *domain.Tenant) error {
+	query := `INSERT INTO tenants (` + tenantColumns + `)
+              VALUES (:id, :name, :slug, :status, :plan, :settings, :created_at, :updated_at)`
+	_, err := r.db.NamedExecContext(ctx, query, tenant)
+	if err != nil {
+		return fmt.Errorf("tenantRepo.Save: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLTenantRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	query := `SELECT ` + tenantColumns + ` FROM tenants WHERE id = $1`
+	err := r.db.GetContext(ctx, &tenant, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("tenantRepo.FindByID: %w", err)
+	}
+	return &tenant, nil
+}
+
+func (r *postgreSQLTenantRepository) FindBySlug(ctx context.Context, slug string) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	query := `SELECT ` + tenantColumns + ` FROM tenants WHERE slug = $1`
+	err := r.db.GetContext(ctx, &tenant, query, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("tenantRepo.FindBySlug: %w", err)
+	}
+	return &tenant, nil
+}
+
+func (r *postgreSQLTenantRepository) FindAll(ctx context.Context, page, limit int, status, query string) (int64, []*domain.Tenant, error) {
+	offset := (page - 1) * limit
+	var tenants []*domain.Tenant
+	var total int64
+
+	baseQuery := `FROM tenants WHERE 1 = 1`
+	args := []interface{}{}
+	argCounter := 1
+
+	if status != "" {
+		baseQuery += ` AND status = $` + strconv.Itoa(argCounter)
+		args = append(args, status)
+		argCounter++
+	}
+	if query != "" {
+		baseQuery += ` AND (name ILIKE $` + strconv.Itoa(argCounter) + ` OR slug ILIKE $` + strconv.Itoa(argCounter) + `)`
+		args = append(args, "%"+query+"%")
+		argCounter++
+	}
+
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) %s`, baseQuery)
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return 0, nil, fmt.Errorf("tenantRepo.FindAll count: %w", err)
+	}
+
+	dataQuery := fmt.Sprintf(`SELECT `+tenantColumns+` %s ORDER BY name ASC LIMIT $%d OFFSET $%d`, baseQuery, argCounter, argCounter+1)
+	args = append(args, limit, offset)
+	if err := r.db.SelectContext(ctx, &tenants, dataQuery, args...); err != nil {
+		return 0, nil, fmt.Errorf("tenantRepo.FindAll data: %w", err)
+	}
+
+	return total, tenants, nil
+}
+
+func (r *postgreSQLTenantRepository) Update(ctx context.Context, tenant *domain.Tenant) error {
+	query := `UPDATE tenants SET name = :name, status = :status, plan = :plan, settings = :settings, updated_at = :updated_at
+              WHERE id = :id`
+	_, err := r.db.NamedExecContext(ctx, query, tenant)
+	if err != nil {
+		return fmt.Errorf("tenantRepo.Update: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/platform/tenancy"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// scopeTenantQuery switches the transaction to the tenant_scoped role, which is subject to the
+// row-level security policies of the tenant tables, and sets the tenant and user those policies
+// allow. The settings end with the transaction.
+const scopeTenantQuery = `SELECT set_config('role', 'tenant_scoped', true), set_config('app.current_tenant', $1, true), set_config('app.current_user', $2, true)`
+
+// scopedDB runs queries against tenant tables. When the context is scoped to a tenant (see package
+// tenancy), each query runs in its own transaction that can only see and write that tenant's rows,
+// so a query that forgets its tenant_id condition still can't reach other tenants' data.
+// Unscoped contexts, such as login before the tenant is known, query the database directly.
+// Inside a unit of work (see NewTransactor) queries run in its transaction instead, which saves
+// the extra round trips of a transaction per query.
+type scopedDB struct {
+	db    *sqlx.DB
+	scope bool
+}
+
+func newScopedDB(db *sqlx.DB) *scopedDB {
+	return &scopedDB{db: db, scope: true}
+}
+
+// newUnscopedDB returns a scopedDB that never scopes its queries, for repositories of tables
+// without tenant data or that work across tenants. Its queries still join a unit of work.
+func newUnscopedDB(db *sqlx.DB) *scopedDB {
+	return &scopedDB{db: db}
+}
+
+func (s *scopedDB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.run(ctx, func(q sqlx.ExtContext) error {
+		return sqlx.GetContext(ctx, q, dest, query, args...)
+	})
+}
+
+func (s *scopedDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.run(ctx, func(q sqlx.ExtContext) error {
+		return sqlx.SelectContext(ctx, q, dest, query, args...)
+	})
+}
+
+func (s *scopedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := s.run(ctx, func(q sqlx.ExtContext) error {
+		var err error
+		result, err = q.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+func (s *scopedDB) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := s.run(ctx, func(q sqlx.ExtContext) error {
+		var err error
+		result, err = sqlx.NamedExecContext(ctx, q, query, arg)
+		return err
+	})
+	return result, err
+}
+
+// run calls fn with the database, or with a tenant-scoped transaction when ctx is scoped to a
+// tenant, or with the transaction of the unit of work ctx belongs to. Errors of fn are returned as
+// is, so callers can still compare them with sql.ErrNoRows.
+func (s *scopedDB) run(ctx context.Context, fn func(q sqlx.ExtContext) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return fn(tx)
+	}
+	if _, ok := tenancy.TenantID(ctx); !ok || !s.scope {
+		return fn(s.db)
+	}
+	return s.transact(ctx, fn)
+}
+
+// transact calls fn in a single transaction, scoped to the tenant when ctx is, for writes that
+// must succeed or fail together. Inside a unit of work that is the unit's transaction.
+func (s *scopedDB) transact(ctx context.Context, fn func(q sqlx.ExtContext) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return fn(tx)
+	}
+	tx, err := beginTx(ctx, s.db, s.scope)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CheckTenantScopedRole makes sure the role the application connects as can switch to
+// tenant_scoped. Migration 000013 grants it to the role running the migrations; when the
+// application connects as another role, every scoped query would fail until it is granted.
+func CheckTenantScopedRole(ctx context.Context, db *sqlx.DB) error {
+	var member bool
+	if err := db.GetContext(ctx, &member, `SELECT pg_has_role(current_user, 'tenant_scoped', 'MEMBER')`); err != nil {
+		return fmt.Errorf("failed to check membership of the tenant_scoped role: %w", err)
+	}
+	if !member {
+		return fmt.Errorf("database role is not a member of tenant_scoped; run GRANT tenant_scoped TO <application role>")
+	}
+	return nil
+}
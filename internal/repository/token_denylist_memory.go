@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/domain"
+)
+
+// inMemoryTokenDenylist keeps revoked JWT IDs in process memory. Entries are not shared between
+// instances and are lost on restart, so multi-instance deployments should plug in a shared
+// implementation (e.g. Redis) instead.
+type inMemoryTokenDenylist struct {
+	mu        sync.RWMutex
+	entries   map[string]time.Time // jti -> token expiry
+	nextSweep time.Time            // When Revoke next drops expired entries
+}
+
+// denylistSweepInterval is the minimum time between two sweeps of expired entries. IsRevoked
+// ignores expired entries on its own, so they only need to be dropped to bound memory.
+const denylistSweepInterval = time.Minute
+
+func NewInMemoryTokenDenylist() domain.TokenDenylist {
+	return &inMemoryTokenDenylist{entries: make(map[string]time.Time)}
+}
+
+func (d *inMemoryTokenDenylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	// Drop entries whose tokens have expired on their own, at most once per interval, so that
+	// revoking many tokens in a row (e.g. every session of a user) stays cheap without a
+	// background goroutine.
+	if !now.Before(d.nextSweep) {
+		for id, exp := range d.entries {
+			if exp.Before(now) {
+				delete(d.entries, id)
+			}
+		}
+		d.nextSweep = now.Add(denylistSweepInterval)
+	}
+	if expiresAt.After(now) {
+		d.entries[jti] = expiresAt
+	}
+	return nil
+}
+
+func (d *inMemoryTokenDenylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	exp, ok := d.entries[jti]
+	return ok && exp.After(time.Now()), nil
+}
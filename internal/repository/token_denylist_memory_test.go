@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInMemoryTokenDenylist(t *testing.T) {
+	ctx := context.Background()
+	d := NewInMemoryTokenDenylist().(*inMemoryTokenDenylist)
+
+	if err := d.Revoke(ctx, "live", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Revoke(ctx, "expired", time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	for jti, want := range map[string]bool{"live": true, "expired": false, "unknown": false} {
+		if got, _ := d.IsRevoked(ctx, jti); got != want {
+			t.Errorf("IsRevoked(%q) = %v, want %v", jti, got, want)
+		}
+	}
+
+	// An entry that expires between sweeps is ignored by IsRevoked and dropped by the next sweep.
+	d.entries["stale"] = time.Now().Add(-time.Second)
+	if got, _ := d.IsRevoked(ctx, "stale"); got {
+		t.Error(`IsRevoked("stale") = true for an expired token`)
+	}
+	if err := d.Revoke(ctx, "other", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.entries["stale"]; !ok {
+		t.Fatal("Revoke swept again before the sweep interval passed")
+	}
+	d.nextSweep = time.Now()
+	if err := d.Revoke(ctx, "another", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.entries["stale"]; ok {
+		t.Error("Revoke didn't drop the expired entry once the sweep interval passed")
+	}
+	if n := len(d.entries); n != 3 {
+		t.Errorf("denylist holds %d entries, want 3", n)
+	}
+}
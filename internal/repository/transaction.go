@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+	"starterpack-golang-cleanarch/internal/platform/tenancy"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type txContextKey struct{}
+
+// unitOfWork is the transaction a context carries inside Transactor.WithinTransaction, with the
+// tenant it was scoped to when it began.
+type unitOfWork struct {
+	tx       *sqlx.Tx
+	tenantID string
+}
+
+// txFromContext returns the transaction of the unit of work ctx belongs to. A context re-scoped to
+// another tenant inside the unit of work doesn't match its transaction and gets none.
+func txFromContext(ctx context.Context) (*sqlx.Tx, bool) {
+	uow, ok := ctx.Value(txContextKey{}).(*unitOfWork)
+	if !ok {
+		return nil, false
+	}
+	tenantID, _ := tenancy.TenantID(ctx)
+	if tenantID != uow.tenantID {
+		return nil, false
+	}
+	return uow.tx, true
+}
+
+type transactor struct {
+	db *sqlx.DB
+}
+
+// NewTransactor returns a Transactor for the repositories of this package. The transaction is
+// scoped to the tenant like a scopedDB query when ctx is scoped, and repositories that work across
+// tenants join it too, so a unit of work that uses them should start from an unscoped context.
+func NewTransactor(db *sqlx.DB) domain.Transactor {
+	return &transactor{db: db}
+}
+
+// WithinTransaction runs fn in a new transaction, or in the current one when ctx already belongs
+// to a unit of work, so services can compose methods that use units of work themselves.
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+	tx, err := beginTx(ctx, t.db, true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tenantID, _ := tenancy.TenantID(ctx)
+	if err := fn(context.WithValue(ctx, txContextKey{}, &unitOfWork{tx: tx, tenantID: tenantID})); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// beginTx begins a transaction, scoped to the tenant when scope is set and ctx is scoped.
+func beginTx(ctx context.Context, db *sqlx.DB, scope bool) (*sqlx.Tx, error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if tenantID, ok := tenancy.TenantID(ctx); ok && scope {
+		if _, err := tx.ExecContext(ctx, scopeTenantQuery, tenantID, tenancy.UserID(ctx)); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to scope transaction to tenant: %w", err)
+		}
+	}
+	return tx, nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"starterpack-golang-cleanarch/internal/platform/tenancy"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestTxFromContext(t *testing.T) {
+	tx := &sqlx.Tx{}
+	tenantCtx := tenancy.WithTenantID(context.Background(), "tenant-a")
+	inUnitOfWork := func(ctx context.Context, tenantID string) context.Context {
+		return context.WithValue(ctx, txContextKey{}, &unitOfWork{tx: tx, tenantID: tenantID})
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{name: "no unit of work", ctx: tenantCtx, want: false},
+		{name: "unit of work of the tenant", ctx: inUnitOfWork(tenantCtx, "tenant-a"), want: true},
+		{name: "unscoped unit of work", ctx: inUnitOfWork(context.Background(), ""), want: true},
+		// A query for another tenant must not run in a transaction scoped to the first one, where
+		// row-level security would hide that tenant's rows.
+		{name: "re-scoped to another tenant", ctx: tenancy.WithTenantID(inUnitOfWork(tenantCtx, "tenant-a"), "tenant-b"), want: false},
+		{name: "scoped inside an unscoped unit of work", ctx: tenancy.WithTenantID(inUnitOfWork(context.Background(), ""), "tenant-a"), want: false},
+	}
+	for _, tt := range tests {
+		got, ok := txFromContext(tt.ctx)
+		if ok != tt.want || (ok && got != tx) {
+			t.Errorf("%s: txFromContext() = %v, %v, want %v", tt.name, got, ok, tt.want)
+		}
+	}
+}
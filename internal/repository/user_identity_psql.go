@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"starterpack-golang-cleanarch/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgreSQLUserIdentityRepository struct {
+	db *scopedDB
+}
+
+func NewPostgreSQLUserIdentityRepository(db *sqlx.DB) domain.UserIdentityRepository {
+	return &postgreSQLUserIdentityRepository{db: newUnscopedDB(db)}
+}
+
+func (r *postgreSQLUserIdentityRepository) Save(ctx context.Context, identity *domain.UserIdentity) error {
+	query := `INSERT INTO user_identities (id, user_id, provider, subject, email, created_at, last_login_at)
+              VALUES (:id, :user_id, :provider, :subject, :email, :created_at, :last_login_at)`
+	_, err := r.db.NamedExecContext(ctx, query, identity)
+	if err != nil {
+		return fmt.Errorf("userIdentityRepo.Save: %w", err)
+	}
+	return nil
+}
+
+func (r *postgreSQLUserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	query := `SELECT id, user_id, provider, subject, COALESCE(email, '') AS email, created_at, last_login_at
+              FROM user_identities WHERE provider = $1 AND subject = $2`
+	err := r.db.GetContext(ctx, &identity, query, provider, subject)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("userIdentityRepo.FindByProviderSubject: %w", err)
+	}
+	return &identity, nil
+}
+
+func (r *postgreSQLUserIdentityRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error) {
+	var identities []domain.UserIdentity
+	query := `SELECT id, user_id, provider, subject, COALESCE(email, '') AS email, created_at, last_login_at
+              FROM user_identities WHERE user_id = $1 ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &identities, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("userIdentityRepo.FindByUser: %w", err)
+	}
+	return identities, nil
+}
+
+func (r *postgreSQLUserIdentityRepository) TouchLogin(ctx context.Context, id uuid.UUID, email string) error {
+	query := `UPDATE user_identities SET last_login_at = NOW(), email = $2 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, email)
+	if err != nil {
+		return fmt.Errorf("userIdentityRepo.TouchLogin: %w", err)
+	}
+	return nil
+}
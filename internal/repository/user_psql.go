@@ -13,16 +13,18 @@ import (
 )
 
 type postgreSQLUserRepository struct {
-	db *sqlx.DB
+	db *scopedDB
 }
 
 func NewPostgreSQLUserRepository(db *sqlx.DB) domain.UserRepository {
-	return &postgreSQLUserRepository{db: db}
+	return &postgreSQLUserRepository{db: newScopedDB(db)}
 }
 
+const userColumns = `id, tenant_id, email, password_hash, name, phone_number, role, email_verified_at, disabled_at, deleted_at, erased_at, created_at, updated_at`
+
 func (r *postgreSQLUserRepository) Save(ctx context.Context, user *domain.User) error {
-	query := `INSERT INTO users (id, tenant_id, email, password_hash, name, phone_number, role, created_at, updated_at)
-              VALUES (:id, :tenant_id, :email, :password_hash, :name, :phone_number, :role, :created_at, :updated_at)`
+	query := `INSERT INTO users (id, tenant_id, email, password_hash, name, phone_number, role, email_verified_at, created_at, updated_at)
+              VALUES (:id, :tenant_id, :email, :password_hash, :name, :phone_number, :role, :email_verified_at, :created_at, :updated_at)`
 	_, err := r.db.NamedExecContext(ctx, query, user)
 	if err != nil {
 		return fmt.Errorf("userRepo.Save: %w", err)
@@ -30,11 +32,11 @@ func (r *postgreSQLUserRepository) Save(ctx context.Context, user *domain.User)
 	return nil
 }
 
-func (r *postgreSQLUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+func (r *postgreSQLUserRepository) FindByEmail(ctx context.Context, tenantID uuid.UUID, email string) (*domain.User, error) {
 	var user domain.User
-	query := `SELECT id, tenant_id, email, password_hash, name, phone_number, role, created_at, updated_at
-              FROM users WHERE email = $1`
-	err := r.db.GetContext(ctx, &user, query, email)
+	query := `SELECT ` + userColumns + `
+              FROM users WHERE tenant_id = $1 AND email = $2 AND deleted_at IS NULL`
+	err := r.db.GetContext(ctx, &user, query, tenantID, email)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -44,10 +46,21 @@ func (r *postgreSQLUserRepository) FindByEmail(ctx context.Context, email string
 	return &user, nil
 }
 
+func (r *postgreSQLUserRepository) FindAllByEmail(ctx context.Context, email string) ([]domain.User, error) {
+	var users []domain.User
+	query := `SELECT ` + userColumns + `
+              FROM users WHERE email = $1 AND deleted_at IS NULL ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &users, query, email)
+	if err != nil {
+		return nil, fmt.Errorf("userRepo.FindAllByEmail: %w", err)
+	}
+	return users, nil
+}
+
 func (r *postgreSQLUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	var user domain.User
-	query := `SELECT id, tenant_id, email, password_hash, name, phone_number, role, created_at, updated_at
-              FROM users WHERE id = $1`
+	query := `SELECT ` + userColumns + `
+              FROM users WHERE id = $1 AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &user, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -58,9 +71,23 @@ func (r *postgreSQLUserRepository) FindByID(ctx context.Context, id uuid.UUID) (
 	return &user, nil
 }
 
+func (r *postgreSQLUserRepository) FindByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	var user domain.User
+	query := `SELECT ` + userColumns + `
+              FROM users WHERE id = $1`
+	err := r.db.GetContext(ctx, &user, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("userRepo.FindByIDIncludingDeleted: %w", err)
+	}
+	return &user, nil
+}
+
 func (r *postgreSQLUserRepository) Update(ctx context.Context, user *domain.User) error {
 	user.UpdatedAt = time.Now()
-	query := `UPDATE users SET email = :email, password_hash = :password_hash, name = :name, phone_number = :phone_number, role = :role, updated_at = :updated_at
+	query := `UPDATE users SET email = :email, password_hash = :password_hash, name = :name, phone_number = :phone_number, role = :role, email_verified_at = :email_verified_at, disabled_at = :disabled_at, updated_at = :updated_at
               WHERE id = :id`
 	_, err := r.db.NamedExecContext(ctx, query, user)
 	if err != nil {
@@ -70,10 +97,19 @@ func (r *postgreSQLUserRepository) Update(ctx context.Context, user *domain.User
 }
 
 func (r *postgreSQLUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM users WHERE id = $1`
+	query := `UPDATE users SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 	_, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("userRepo.Delete: %w", err)
 	}
 	return nil
 }
+
+func (r *postgreSQLUserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE users SET deleted_at = NULL, updated_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("userRepo.Restore: %w", err)
+	}
+	return nil
+}
@@ -1,7 +1,9 @@
 package errors
 
 import (
+	"math"
 	"net/http"
+	"time"
 )
 
 // AppError defines a generic interface for application-specific errors.
@@ -70,6 +72,7 @@ var (
 	ErrConflict           = New("CONFLICT", "Resource conflict or already exists", http.StatusConflict, nil, nil)
 	ErrInternalServer     = New("INTERNAL_SERVER_ERROR", "An unexpected internal server error occurred", http.StatusInternalServerError, nil, nil)
 	ErrServiceUnavailable = New("SERVICE_UNAVAILABLE", "Service is temporarily unavailable, please try again later", http.StatusServiceUnavailable, nil, nil)
+	ErrTenantSuspended    = New("TENANT_SUSPENDED", "This tenant has been suspended", http.StatusForbidden, nil, nil)
 )
 
 // NewBadRequest creates a new bad request error with optional details.
@@ -86,6 +89,19 @@ func NewInternalServerError(originalErr error, message string) AppError {
 	return New(ErrInternalServer.Code(), message, ErrInternalServer.Status(), originalErr, nil)
 }
 
+// DetailRetryAfter is the details key holding the number of seconds a client has to wait before
+// retrying. HandleHTTPError sends it as the Retry-After header.
+const DetailRetryAfter = "retry_after"
+
+// WithRetryAfter returns a copy of err that tells the client to retry after the given duration.
+func WithRetryAfter(err AppError, retryAfter time.Duration) AppError {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return New(err.Code(), err.Message(), err.Status(), err.Unwrap(), map[string]interface{}{DetailRetryAfter: seconds})
+}
+
 // Wrap wraps an existing error into an AppError with specified code, message, and status.
 func Wrap(err error, code, message string, status int) AppError {
 	if err == nil {
@@ -5,34 +5,67 @@ import (
 	"os"
 	"time"
 
+	"starterpack-golang-cleanarch/internal/utils/jwtkeys"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
+// Token types carried in Claims.TokenType, so a token can't be used in place of another kind.
+const (
+	TokenTypeAccess       = "access"
+	TokenTypeRefresh      = "refresh"
+	TokenTypeMFAChallenge = "mfa_challenge"
+	TokenTypeSSOFlow      = "sso_flow"
+)
+
 type Claims struct {
-	UserID   string `json:"user_id"`
-	TenantID string `json:"tenant_id"`
-	Role     string `json:"role"`
+	UserID    string `json:"user_id"`
+	TenantID  string `json:"tenant_id"`
+	Role      string `json:"role"`
+	TokenType string `json:"token_type,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateAccessToken(userID, tenantID, role string) (string, error) {
+// keyManager signs and verifies every token. It is set once at startup with SetKeyManager.
+var keyManager *jwtkeys.Manager
+
+// SetKeyManager installs the key manager used by the token helpers in this file.
+func SetKeyManager(m *jwtkeys.Manager) {
+	keyManager = m
+}
+
+// getKeyManager returns the installed key manager, falling back to HS256 with JWT_SECRET so
+// the helpers keep working when none was configured.
+func getKeyManager() (*jwtkeys.Manager, error) {
+	if keyManager != nil {
+		return keyManager, nil
+	}
 	jwtSecret := os.Getenv("JWT_SECRET")
-	expiresInMinutes := os.Getenv("JWT_EXPIRES_IN_MINUTES")
+	if jwtSecret == "" {
+		return nil, fmt.Errorf("JWT_SECRET not set")
+	}
+	return jwtkeys.NewManager(jwtkeys.Config{Algorithm: jwtkeys.AlgHS256, HMACSecret: jwtSecret})
+}
 
-	if jwtSecret == "" || expiresInMinutes == "" {
-		return "", fmt.Errorf("JWT_SECRET or JWT_EXPIRES_IN_MINUTES not set")
+// GenerateAccessToken signs a new access token and also returns its claims, so callers can
+// keep track of the token by its JWT ID (claims.ID).
+func GenerateAccessToken(userID, tenantID, role string) (string, *Claims, error) {
+	expiresInMinutes := os.Getenv("JWT_EXPIRES_IN_MINUTES")
+	if expiresInMinutes == "" {
+		return "", nil, fmt.Errorf("JWT_EXPIRES_IN_MINUTES not set")
 	}
 
 	expMinutes, err := time.ParseDuration(expiresInMinutes + "m")
 	if err != nil {
-		return "", fmt.Errorf("invalid JWT_EXPIRES_IN_MINUTES format: %w", err)
+		return "", nil, fmt.Errorf("invalid JWT_EXPIRES_IN_MINUTES format: %w", err)
 	}
 
 	claims := Claims{
-		UserID:   userID,
-		TenantID: tenantID,
-		Role:     role,
+		UserID:    userID,
+		TenantID:  tenantID,
+		Role:      role,
+		TokenType: TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expMinutes)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -44,29 +77,29 @@ func GenerateAccessToken(userID, tenantID, role string) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(jwtSecret))
+	tokenString, err := signClaims(claims)
 	if err != nil {
-		return "", fmt.Errorf("failed to sign access token: %w", err)
+		return "", nil, fmt.Errorf("failed to sign access token: %w", err)
 	}
-	return tokenString, nil
+	return tokenString, &claims, nil
 }
 
-func GenerateRefreshToken(userID string) (string, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
+// GenerateRefreshToken signs a new refresh token and also returns its claims, so callers can
+// persist the token by its JWT ID (claims.ID).
+func GenerateRefreshToken(userID string) (string, *Claims, error) {
 	refreshExpiresInHours := os.Getenv("REFRESH_TOKEN_EXPIRES_IN_HOURS")
-
-	if jwtSecret == "" || refreshExpiresInHours == "" {
-		return "", fmt.Errorf("JWT_SECRET or REFRESH_TOKEN_EXPIRES_IN_HOURS not set")
+	if refreshExpiresInHours == "" {
+		return "", nil, fmt.Errorf("REFRESH_TOKEN_EXPIRES_IN_HOURS not set")
 	}
 
 	expHours, err := time.ParseDuration(refreshExpiresInHours + "h")
 	if err != nil {
-		return "", fmt.Errorf("invalid REFRESH_TOKEN_EXPIRES_IN_HOURS format: %w", err)
+		return "", nil, fmt.Errorf("invalid REFRESH_TOKEN_EXPIRES_IN_HOURS format: %w", err)
 	}
 
 	claims := Claims{
-		UserID: userID,
+		UserID:    userID,
+		TokenType: TokenTypeRefresh,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expHours)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -76,26 +109,74 @@ func GenerateRefreshToken(userID string) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(jwtSecret))
+	tokenString, err := signClaims(claims)
 	if err != nil {
-		return "", fmt.Errorf("failed to sign refresh token: %w", err)
+		return "", nil, fmt.Errorf("failed to sign refresh token: %w", err)
 	}
-	return tokenString, nil
+	return tokenString, &claims, nil
+}
+
+// GenerateMFAChallengeToken signs a short-lived token proving that the user passed the password
+// step of the login to the tenant. It only grants access to the MFA verification step.
+func GenerateMFAChallengeToken(userID, tenantID string, ttl time.Duration) (string, *Claims, error) {
+	claims := Claims{
+		UserID:    userID,
+		TenantID:  tenantID,
+		TokenType: TokenTypeMFAChallenge,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "go-starterpack",
+			Subject:   userID,
+			ID:        uuid.New().String(),
+		},
+	}
+
+	tokenString, err := signClaims(claims)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sign MFA challenge token: %w", err)
+	}
+	return tokenString, &claims, nil
+}
+
+func signClaims(claims Claims) (string, error) {
+	return SignClaims(claims)
+}
+
+// SignClaims signs arbitrary claims with the application's signing key. It is meant for
+// short-lived tokens the application issues to itself (e.g. SSO flow state); include a
+// "token_type" claim so they can't be confused with access tokens.
+func SignClaims(claims jwt.Claims) (string, error) {
+	km, err := getKeyManager()
+	if err != nil {
+		return "", err
+	}
+	return km.Sign(claims)
+}
+
+// ParseClaims verifies a token signed with SignClaims and decodes it into claims.
+func ParseClaims(tokenString string, claims jwt.Claims) error {
+	km, err := getKeyManager()
+	if err != nil {
+		return err
+	}
+	token, err := jwt.ParseWithClaims(tokenString, claims, km.Keyfunc, jwt.WithValidMethods(km.ValidMethods()))
+	if err != nil {
+		return fmt.Errorf("invalid token: %w", err)
+	}
+	if !token.Valid {
+		return fmt.Errorf("invalid token claims")
+	}
+	return nil
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		return nil, fmt.Errorf("JWT_SECRET not set")
+	km, err := getKeyManager()
+	if err != nil {
+		return nil, err
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(jwtSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, km.Keyfunc, jwt.WithValidMethods(km.ValidMethods()))
 
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
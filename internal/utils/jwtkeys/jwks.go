@@ -0,0 +1,93 @@
+package jwtkeys
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"time"
+
+	"starterpack-golang-cleanarch/internal/utils/log"
+)
+
+// JWK is a JSON Web Key (RFC 7517) holding a public verification key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA public exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKS is a JSON Web Key Set as served from /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of every key that can still verify tokens. HS256 secrets are
+// never published, so the set is empty in that mode.
+func (m *Manager) JWKS() JWKS {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := JWKS{Keys: []JWK{}}
+	for _, k := range m.keys {
+		if k.Algorithm == AlgHS256 {
+			continue
+		}
+		jwk := JWK{Use: "sig", Alg: k.Algorithm, Kid: k.ID}
+		switch pub := k.signer.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// StartRotation rotates the signing key every interval until ctx is cancelled. The key age is
+// checked regularly rather than on a fixed timer, so restarts don't postpone rotation.
+func (m *Manager) StartRotation(ctx context.Context, interval time.Duration) {
+	if interval <= 0 || m.cfg.Algorithm == AlgHS256 {
+		return
+	}
+	checkEvery := interval / 10
+	if checkEvery > time.Hour {
+		checkEvery = time.Hour
+	}
+	if checkEvery < time.Second {
+		checkEvery = time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(checkEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				rotated, err := m.RotateIfOlderThan(interval)
+				if err != nil {
+					log.Errorf(ctx, "JWT key rotation failed: %v", err)
+					continue
+				}
+				if rotated {
+					log.Infof(ctx, "JWT signing key rotated, active kid: %s", m.SigningKey().ID)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,422 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms.
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+	AlgHS256 = "HS256" // Legacy shared-secret mode; keys are never published in the JWKS.
+)
+
+const (
+	rsaKeyBits    = 2048
+	kidTimeFormat = "20060102T150405Z" // Generated key IDs start with their creation time
+)
+
+// Key is a single signing key identified by its key ID (kid).
+type Key struct {
+	ID        string
+	Algorithm string
+	CreatedAt time.Time
+
+	signer crypto.Signer // RS256 / EdDSA private key
+	secret []byte        // HS256 shared secret
+}
+
+// signingKey returns the value expected by the jwt library when signing.
+func (k *Key) signingKey() interface{} {
+	if k.Algorithm == AlgHS256 {
+		return k.secret
+	}
+	return k.signer
+}
+
+// verificationKey returns the value expected by the jwt library when verifying.
+func (k *Key) verificationKey() interface{} {
+	if k.Algorithm == AlgHS256 {
+		return k.secret
+	}
+	return k.signer.Public()
+}
+
+func (k *Key) signingMethod() jwt.SigningMethod {
+	switch k.Algorithm {
+	case AlgRS256:
+		return jwt.SigningMethodRS256
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+// Config configures a Manager.
+type Config struct {
+	Algorithm  string        // RS256, EdDSA or HS256
+	KeysDir    string        // Directory holding PEM private keys named <kid>.pem; empty keeps keys in memory only
+	Retention  time.Duration // How long a retired key keeps verifying tokens; should cover the longest token lifetime
+	HMACSecret string        // Shared secret, only used with HS256
+}
+
+// Manager owns the set of signing keys. The newest key signs new tokens while older keys stay
+// available for verification until their retention period has passed, so rotating never
+// invalidates tokens that were already issued.
+type Manager struct {
+	mu         sync.RWMutex
+	cfg        Config
+	keys       []*Key // Sorted by CreatedAt, the last key is the active signing key
+	lastReload time.Time
+}
+
+// NewManager loads the keys from cfg.KeysDir, creating a first key when none exist.
+func NewManager(cfg Config) (*Manager, error) {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = AlgHS256
+	}
+	m := &Manager{cfg: cfg}
+
+	switch cfg.Algorithm {
+	case AlgHS256:
+		if cfg.HMACSecret == "" {
+			return nil, fmt.Errorf("jwtkeys: HS256 requires a secret")
+		}
+		m.keys = []*Key{{ID: "hs256", Algorithm: AlgHS256, secret: []byte(cfg.HMACSecret)}}
+		return m, nil
+	case AlgRS256, AlgEdDSA:
+	default:
+		return nil, fmt.Errorf("jwtkeys: unsupported algorithm %q", cfg.Algorithm)
+	}
+
+	if cfg.KeysDir != "" {
+		if err := os.MkdirAll(cfg.KeysDir, 0o700); err != nil {
+			return nil, fmt.Errorf("jwtkeys: failed to create keys dir: %w", err)
+		}
+		if err := m.reload(); err != nil {
+			return nil, err
+		}
+	}
+	if len(m.keys) == 0 {
+		if _, err := m.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Algorithm returns the algorithm used for new keys.
+func (m *Manager) Algorithm() string {
+	return m.cfg.Algorithm
+}
+
+// SigningKey returns the active key used to sign new tokens.
+func (m *Manager) SigningKey() *Key {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.keys[len(m.keys)-1]
+}
+
+// Sign signs the claims with the active key and sets the "kid" header.
+func (m *Manager) Sign(claims jwt.Claims) (string, error) {
+	key := m.SigningKey()
+	token := jwt.NewWithClaims(key.signingMethod(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signingKey())
+}
+
+// Keyfunc resolves the verification key for a token from its "kid" header. It is meant to be
+// passed to jwt.Parse / jwt.ParseWithClaims.
+func (m *Manager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if m.cfg.Algorithm != AlgHS256 {
+			return nil, fmt.Errorf("token has no kid header")
+		}
+		kid = "hs256" // Tokens issued before key IDs were introduced
+	}
+
+	key, ok := m.lookup(kid)
+	if !ok && m.cfg.KeysDir != "" {
+		// Another instance may have rotated in a new key; pick it up from the shared directory.
+		if err := m.reloadThrottled(); err != nil {
+			return nil, err
+		}
+		key, ok = m.lookup(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.verificationKey(), nil
+}
+
+// ValidMethods lists the algorithms accepted when parsing tokens.
+func (m *Manager) ValidMethods() []string {
+	if m.cfg.Algorithm == AlgHS256 {
+		return []string{AlgHS256}
+	}
+	return []string{AlgRS256, AlgEdDSA}
+}
+
+func (m *Manager) lookup(kid string) (*Key, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, k := range m.keys {
+		if k.ID == kid {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// Rotate generates a new key, makes it the active signing key and prunes retired keys whose
+// retention period has passed. It is a no-op for HS256.
+func (m *Manager) Rotate() (*Key, error) {
+	if m.cfg.Algorithm == AlgHS256 {
+		return m.SigningKey(), nil
+	}
+
+	signer, err := generateSigner(m.cfg.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	key := &Key{
+		ID:        now.Format(kidTimeFormat) + "-" + randomSuffix(),
+		Algorithm: m.cfg.Algorithm,
+		CreatedAt: now,
+		signer:    signer,
+	}
+	if m.cfg.KeysDir != "" {
+		if err := writeKey(filepath.Join(m.cfg.KeysDir, key.ID+".pem"), signer); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	m.keys = append(m.keys, key)
+	m.pruneLocked(now)
+	m.mu.Unlock()
+	return key, nil
+}
+
+// RotateIfOlderThan rotates when the active key was created more than maxAge ago. Keys written
+// by other instances are loaded first, so a shared directory is rotated only once.
+func (m *Manager) RotateIfOlderThan(maxAge time.Duration) (bool, error) {
+	if m.cfg.Algorithm == AlgHS256 {
+		return false, nil
+	}
+	if m.cfg.KeysDir != "" {
+		if err := m.reload(); err != nil {
+			return false, err
+		}
+	}
+	if time.Since(m.SigningKey().CreatedAt) < maxAge {
+		return false, nil
+	}
+	_, err := m.Rotate()
+	return err == nil, err
+}
+
+// pruneLocked removes keys that were retired longer than the retention period ago. A key is
+// retired when the next key is created. Callers must hold m.mu.
+func (m *Manager) pruneLocked(now time.Time) {
+	if m.cfg.Retention <= 0 {
+		return
+	}
+	kept := m.keys[:0]
+	for i, k := range m.keys {
+		if i < len(m.keys)-1 && m.keys[i+1].CreatedAt.Add(m.cfg.Retention).Before(now) {
+			if m.cfg.KeysDir != "" {
+				_ = os.Remove(filepath.Join(m.cfg.KeysDir, k.ID+".pem"))
+			}
+			continue
+		}
+		kept = append(kept, k)
+	}
+	m.keys = kept
+}
+
+func (m *Manager) reloadThrottled() error {
+	m.mu.RLock()
+	recent := time.Since(m.lastReload) < 10*time.Second
+	m.mu.RUnlock()
+	if recent {
+		return nil
+	}
+	return m.reload()
+}
+
+// reload reads every <kid>.pem file from the keys directory.
+func (m *Manager) reload() error {
+	entries, err := os.ReadDir(m.cfg.KeysDir)
+	if err != nil {
+		return fmt.Errorf("jwtkeys: failed to read keys dir: %w", err)
+	}
+
+	var keys []*Key
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+			continue
+		}
+		path := filepath.Join(m.cfg.KeysDir, entry.Name())
+		signer, err := readKey(path)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("jwtkeys: failed to stat %s: %w", path, err)
+		}
+		alg, err := algorithmOf(signer)
+		if err != nil {
+			return fmt.Errorf("jwtkeys: %s: %w", path, err)
+		}
+		kid := strings.TrimSuffix(entry.Name(), ".pem")
+		keys = append(keys, &Key{
+			ID:        kid,
+			Algorithm: alg,
+			CreatedAt: createdAt(kid, info.ModTime()),
+			signer:    signer,
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].ID < keys[j].ID
+		}
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(keys) > 0 {
+		m.keys = keys
+		m.pruneLocked(time.Now())
+	}
+	m.lastReload = time.Now()
+	return nil
+}
+
+func generateSigner(alg string) (crypto.Signer, error) {
+	switch alg {
+	case AlgRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, fmt.Errorf("jwtkeys: failed to generate RSA key: %w", err)
+		}
+		return key, nil
+	case AlgEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("jwtkeys: failed to generate Ed25519 key: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("jwtkeys: unsupported algorithm %q", alg)
+	}
+}
+
+func algorithmOf(signer crypto.Signer) (string, error) {
+	switch signer.(type) {
+	case *rsa.PrivateKey:
+		return AlgRS256, nil
+	case ed25519.PrivateKey:
+		return AlgEdDSA, nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T", signer)
+	}
+}
+
+// writeKey writes the key to a temporary file in the same directory and renames it into place, so
+// instances reloading the directory never read a partially written key.
+func writeKey(path string, signer crypto.Signer) error {
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return fmt.Errorf("jwtkeys: failed to encode key: %w", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	// The temporary file is created with mode 0600 and its name doesn't end in .pem, so reload
+	// skips it.
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("jwtkeys: failed to write key: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("jwtkeys: failed to write key: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("jwtkeys: failed to write key: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("jwtkeys: failed to write key: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("jwtkeys: failed to write key: %w", err)
+	}
+	return nil
+}
+
+func readKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: failed to read key %s: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwtkeys: %s is not PEM encoded", path)
+	}
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: failed to parse key %s: %w", path, err)
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("jwtkeys: %s does not hold a private key", path)
+	}
+	return signer, nil
+}
+
+// createdAt reads the creation time from a generated key ID, falling back to the file's
+// modification time for keys that were named by hand.
+func createdAt(kid string, modTime time.Time) time.Time {
+	if len(kid) >= len(kidTimeFormat) {
+		if t, err := time.Parse(kidTimeFormat, kid[:len(kidTimeFormat)]); err == nil {
+			return t
+		}
+	}
+	return modTime.UTC()
+}
+
+func randomSuffix() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%x", b)
+}
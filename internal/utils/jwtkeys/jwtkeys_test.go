@@ -0,0 +1,76 @@
+package jwtkeys
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestManagerSharesKeysThroughDir(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			dir := t.TempDir()
+			first, err := NewManager(Config{Algorithm: alg, KeysDir: dir, Retention: time.Hour})
+			if err != nil {
+				t.Fatalf("NewManager() error = %v", err)
+			}
+			rotated, err := first.Rotate()
+			if err != nil {
+				t.Fatalf("Rotate() error = %v", err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 2 {
+				t.Fatalf("keys dir holds %d files, want the 2 keys and no temporary files", len(entries))
+			}
+			for _, entry := range entries {
+				info, err := entry.Info()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if mode := info.Mode().Perm(); mode != 0o600 {
+					t.Errorf("%s has mode %o, want 600", entry.Name(), mode)
+				}
+			}
+
+			// A second instance loads the keys and verifies tokens signed by the first.
+			second, err := NewManager(Config{Algorithm: alg, KeysDir: dir, Retention: time.Hour})
+			if err != nil {
+				t.Fatalf("NewManager() error = %v", err)
+			}
+			if _, ok := second.lookup(rotated.ID); !ok || len(second.keys) != 2 {
+				t.Errorf("second manager has %d keys, want both keys including %s", len(second.keys), rotated.ID)
+			}
+			signed, err := first.Sign(jwt.RegisteredClaims{Subject: "user"})
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			if _, err := jwt.Parse(signed, second.Keyfunc, jwt.WithValidMethods(second.ValidMethods())); err != nil {
+				t.Errorf("Parse() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestReloadSkipsTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(Config{Algorithm: AlgEdDSA, KeysDir: dir})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	// A key another instance is still writing.
+	if err := os.WriteFile(dir+"/.20990101T000000Z-0000.pem.tmp-1", []byte("-----BEGIN PRIV"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.reload(); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	if n := len(m.keys); n != 1 {
+		t.Errorf("reload() loaded %d keys, want 1", n)
+	}
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7396) to a JSON document and returns the patched
+// document. Members of the patch replace those of the document, objects are merged recursively
+// and null removes a member. A patch that isn't an object replaces the whole document.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+	if err := decodeJSONNumber(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := decodeJSONNumber(patch, &changes); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeValue(target, changes))
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// decodeJSONNumber decodes data keeping numbers as json.Number, so large integers survive the
+// round trip unchanged.
+func decodeJSONNumber(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// The examples of RFC 7396, Appendix A.
+func TestMergePatchRFC7396Examples(t *testing.T) {
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("MergePatch(%s, %s) error = %v", tt.doc, tt.patch, err)
+			continue
+		}
+		if want := canonicalJSON(t, tt.want); string(got) != want {
+			t.Errorf("MergePatch(%s, %s) = %s, want %s", tt.doc, tt.patch, got, want)
+		}
+	}
+}
+
+func TestMergePatchKeepsLargeNumbers(t *testing.T) {
+	got, err := MergePatch([]byte(`{"id":9007199254740993,"name":"a"}`), []byte(`{"name":"b"}`))
+	if err != nil {
+		t.Fatalf("MergePatch() error = %v", err)
+	}
+	if want := `{"id":9007199254740993,"name":"b"}`; string(got) != want {
+		t.Errorf("MergePatch() = %s, want %s", got, want)
+	}
+}
+
+func TestMergePatchInvalidJSON(t *testing.T) {
+	if _, err := MergePatch([]byte(`{"a":"b"}`), []byte(`{"a":`)); err == nil {
+		t.Error("MergePatch() error = nil for an invalid patch")
+	}
+	if _, err := MergePatch([]byte(`{"a":`), []byte(`{"a":"b"}`)); err == nil {
+		t.Error("MergePatch() error = nil for an invalid document")
+	}
+}
+
+// canonicalJSON re-encodes a document the way MergePatch does, with object keys sorted.
+func canonicalJSON(t *testing.T, doc string) string {
+	t.Helper()
+	var v interface{}
+	if err := decodeJSONNumber([]byte(doc), &v); err != nil {
+		t.Fatalf("invalid JSON %s: %v", doc, err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
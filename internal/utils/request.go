@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// trustProxyHeaders makes ClientIP read X-Forwarded-For. It is set once at startup with
+// SetTrustProxyHeaders and must only be enabled behind a proxy that sets the header.
+var trustProxyHeaders bool
+
+// SetTrustProxyHeaders configures whether ClientIP trusts the X-Forwarded-For header.
+func SetTrustProxyHeaders(trust bool) {
+	trustProxyHeaders = trust
+}
+
+// ClientIP returns the IP address of the client that sent the request. Behind a trusted proxy
+// it uses the last X-Forwarded-For entry, which is the one added by the proxy itself.
+func ClientIP(r *http.Request) string {
+	if trustProxyHeaders {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			parts := strings.Split(forwarded, ",")
+			if ip := strings.TrimSpace(parts[len(parts)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	// Import os untuk mengecek environment
 	"starterpack-golang-cleanarch/internal/utils/errors"
@@ -67,6 +69,9 @@ func HandleHTTPError(w http.ResponseWriter, err error, r *http.Request) {
 		})
 	} else { // 4xx errors are client errors
 		log.Warnf(r.Context(), "Client-side error: %v", appErr)
+		if retryAfter, ok := appErr.Details()[errors.DetailRetryAfter].(int); ok {
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		}
 		RespondJSON(w, appErr.Status(), ErrorResponse{
 			Code:    appErr.Code(),
 			Message: appErr.Message(),
@@ -80,7 +85,10 @@ func formatErrorDetails(details map[string]interface{}) string {
 	if len(details) == 0 {
 		return ""
 	}
-	for _, v := range details {
+	for k, v := range details {
+		if k == errors.DetailRetryAfter {
+			continue // Sent as the Retry-After header
+		}
 		return fmt.Sprintf("%v", v)
 	}
 	return ""
@@ -106,3 +114,15 @@ type PaginationResponse[T any] struct {
 
 // ISO8601TimeFormat provides a consistent format for time strings.
 const ISO8601TimeFormat = "2006-01-02T15:04:05Z07:00"
+
+// ISO8601DateFormat is the format of dates without a time, such as birthdays or hire dates.
+const ISO8601DateFormat = "2006-01-02"
+
+// FormatOptionalTime formats an optional timestamp with ISO8601TimeFormat, returning nil for nil.
+func FormatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(ISO8601TimeFormat)
+	return &s
+}
@@ -0,0 +1,82 @@
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the length of the key in bytes (AES-256).
+const KeySize = 32
+
+// prefix marks sealed values and the format they were sealed with, which tells them apart from
+// values stored in plain text before encryption was introduced.
+const prefix = "v1:"
+
+// Box encrypts small secrets, such as TOTP secrets, for storage with AES-256-GCM.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New returns a Box using the key, which must be KeySize bytes long.
+func New(key []byte) (*Box, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("secretbox: key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: %w", err)
+	}
+	return &Box{aead: aead}, nil
+}
+
+// NewFromBase64 returns a Box using a base64-encoded key, as found in configuration.
+func NewFromBase64(encodedKey string) (*Box, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: key is not valid base64: %w", err)
+	}
+	return New(key)
+}
+
+// Seal encrypts the plaintext with a random nonce. The result is printable and starts with a
+// version prefix.
+func (b *Box) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("secretbox: failed to generate nonce: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value returned by Seal. It fails for values sealed with another key or changed
+// since.
+func (b *Box) Open(sealed string) (string, error) {
+	if !IsSealed(sealed) {
+		return "", errors.New("secretbox: value is not sealed")
+	}
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, prefix))
+	if err != nil || len(data) < b.aead.NonceSize() {
+		return "", errors.New("secretbox: malformed sealed value")
+	}
+	nonce, ciphertext := data[:b.aead.NonceSize()], data[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.New("secretbox: value can't be decrypted with this key")
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed reports whether the value looks like the output of Seal.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
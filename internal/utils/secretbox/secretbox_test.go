@@ -0,0 +1,81 @@
+package secretbox
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func newTestBox(t *testing.T, fill byte) *Box {
+	t.Helper()
+	box, err := New(bytes.Repeat([]byte{fill}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return box
+}
+
+func TestSealOpen(t *testing.T) {
+	box := newTestBox(t, 1)
+	sealed, err := box.Seal("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if !IsSealed(sealed) || strings.Contains(sealed, "JBSWY3DPEHPK3PXP") {
+		t.Fatalf("Seal() = %q, want an encrypted value", sealed)
+	}
+	again, _ := box.Seal("JBSWY3DPEHPK3PXP")
+	if again == sealed {
+		t.Error("Seal() returned the same value twice, want a random nonce")
+	}
+
+	got, err := box.Open(sealed)
+	if err != nil || got != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Open() = %q, %v, want the plaintext", got, err)
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	box := newTestBox(t, 1)
+	sealed, err := box.Seal("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := []byte(sealed)
+	if tampered[10] == 'A' {
+		tampered[10] = 'B'
+	} else {
+		tampered[10] = 'A'
+	}
+
+	tests := []struct {
+		name  string
+		box   *Box
+		value string
+	}{
+		{name: "plain text", box: box, value: "JBSWY3DPEHPK3PXP"},
+		{name: "other key", box: newTestBox(t, 2), value: sealed},
+		{name: "tampered", box: box, value: string(tampered)},
+		{name: "truncated", box: box, value: "v1:AAAA"},
+		{name: "not base64", box: box, value: "v1:***"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := tt.box.Open(tt.value); err == nil {
+				t.Errorf("Open(%q) = %q, want an error", tt.value, got)
+			}
+		})
+	}
+}
+
+func TestNewFromBase64(t *testing.T) {
+	if _, err := NewFromBase64(base64.StdEncoding.EncodeToString(make([]byte, KeySize))); err != nil {
+		t.Errorf("NewFromBase64() error = %v for a valid key", err)
+	}
+	for _, key := range []string{"", "not base64!", base64.StdEncoding.EncodeToString(make([]byte, 16))} {
+		if _, err := NewFromBase64(key); err == nil {
+			t.Errorf("NewFromBase64(%q) error = nil, want an error", key)
+		}
+	}
+}
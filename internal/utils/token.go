@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// GenerateOpaqueToken returns a random URL-safe token together with its hash. The token is
+// handed to the user; only the hash should be stored.
+func GenerateOpaqueToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of a token generated by GenerateOpaqueToken.
+// A fast hash is enough because the tokens carry 256 bits of randomness.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,98 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors, "12345678901234567890", in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateRFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string // Last 6 digits of the RFC's 8-digit values
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+	for _, tt := range tests {
+		step, ok := Validate(rfcSecret, tt.code, time.Unix(tt.unix, 0))
+		if !ok {
+			t.Errorf("Validate(%s) at %d = false, want true", tt.code, tt.unix)
+			continue
+		}
+		if want := tt.unix / 30; step != want {
+			t.Errorf("Validate(%s) at %d step = %d, want %d", tt.code, tt.unix, step, want)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	issued := time.Unix(1111111109, 0) // Step 37037036, code 081804
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{name: "one period later", at: issued.Add(Period), want: true},
+		{name: "one period earlier", at: issued.Add(-Period), want: true},
+		{name: "two periods later", at: issued.Add(2 * Period), want: false},
+		{name: "two periods earlier", at: issued.Add(-2 * Period), want: false},
+	}
+	for _, tt := range tests {
+		step, ok := Validate(rfcSecret, "081804", tt.at)
+		if ok != tt.want {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, ok, tt.want)
+		}
+		// The step is the one the code was issued for, so it can be marked as used.
+		if ok && step != issued.Unix()/30 {
+			t.Errorf("%s: Validate() step = %d, want %d", tt.name, step, issued.Unix()/30)
+		}
+	}
+}
+
+func TestValidateRejectsMalformedInput(t *testing.T) {
+	at := time.Unix(59, 0)
+	if _, ok := Validate(rfcSecret, " 287082 ", at); !ok {
+		t.Error("Validate() = false for a code with surrounding spaces")
+	}
+	if _, ok := Validate(strings.ToLower(rfcSecret), "287082", at); !ok {
+		t.Error("Validate() = false for a lowercase secret")
+	}
+	for _, code := range []string{"", "28708", "2870820", "abcdef"} {
+		if _, ok := Validate(rfcSecret, code, at); ok {
+			t.Errorf("Validate(%q) = true, want false", code)
+		}
+	}
+	if _, ok := Validate("not base32!", "287082", at); ok {
+		t.Error("Validate() = true for an invalid secret")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("GenerateSecret() = %q, want 32 base32 characters", secret)
+	}
+	code := generate(mustDecode(t, secret), time.Now().Unix()/30)
+	if _, ok := Validate(secret, code, time.Now()); !ok {
+		t.Error("Validate() = false for a code of a generated secret")
+	}
+}
+
+func mustDecode(t *testing.T, secret string) []byte {
+	t.Helper()
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
@@ -0,0 +1,3 @@
+-- migrations/000002_create_refresh_tokens_table.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,19 @@
+-- migrations/000002_create_refresh_tokens_table.up.sql
+-- This migration creates the 'refresh_tokens' table that backs refresh-token sessions.
+-- Every issued refresh token is recorded by its JWT ID (jti). Tokens obtained by rotating an
+-- earlier token share its family_id, so reuse of a rotated token can revoke the whole chain.
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY,                                            -- JWT ID (jti) of the refresh token
+    family_id UUID NOT NULL,                                        -- Shared by every token rotated from the same login
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    rotated_at TIMESTAMPTZ,                                         -- Set once the token has been exchanged for a new one
+    replaced_by UUID,                                               -- jti of the token issued on rotation
+    revoked_at TIMESTAMPTZ,                                         -- Set when the token (or its whole family) is revoked
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id); -- Family-wide revocation
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);     -- Per-user session lookups
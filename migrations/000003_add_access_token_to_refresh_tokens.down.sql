@@ -0,0 +1,6 @@
+-- migrations/000003_add_access_token_to_refresh_tokens.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP INDEX IF EXISTS idx_refresh_tokens_access_token_id;
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS access_token_id,
+    DROP COLUMN IF EXISTS access_expires_at;
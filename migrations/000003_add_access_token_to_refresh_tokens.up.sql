@@ -0,0 +1,9 @@
+-- migrations/000003_add_access_token_to_refresh_tokens.up.sql
+-- This migration links each refresh-token session to the access token issued alongside it.
+-- Logging out uses these columns to deny the session's in-flight access token by its jti.
+
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS access_token_id UUID,                  -- JWT ID (jti) of the access token issued with this refresh token
+    ADD COLUMN IF NOT EXISTS access_expires_at TIMESTAMPTZ;         -- Expiry of that access token
+
+CREATE INDEX idx_refresh_tokens_access_token_id ON refresh_tokens (access_token_id);
@@ -0,0 +1,6 @@
+-- migrations/000004_create_rbac_tables.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
+DROP TABLE IF EXISTS permissions;
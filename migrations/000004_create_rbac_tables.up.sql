@@ -0,0 +1,65 @@
+-- migrations/000004_create_rbac_tables.up.sql
+-- This migration creates the role-based access control (RBAC) tables.
+-- Permissions are plain strings such as 'employees:write'. Roles group permissions and are
+-- either built in (tenant_id IS NULL, shared by every tenant) or defined by a single tenant.
+-- Users receive roles per tenant through 'user_roles'.
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(100) PRIMARY KEY,                                  -- e.g. 'employees:write'
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID,                                                 -- NULL for built-in roles available in every tenant
+    name VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id UUID NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL,                                        -- Tenant in which the role is granted
+    role_id UUID NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, tenant_id, role_id)
+);
+
+-- Indexes for performance
+CREATE UNIQUE INDEX idx_roles_tenant_name ON roles (COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid), name); -- Role names are unique per tenant
+CREATE INDEX idx_user_roles_tenant_id ON user_roles (tenant_id);
+
+-- Seed permissions and built-in roles
+INSERT INTO permissions (name, description) VALUES
+    ('users:read', 'View users of the tenant'),
+    ('users:write', 'Manage users of the tenant'),
+    ('roles:read', 'View roles and role assignments'),
+    ('roles:assign', 'Assign and remove roles of users in the tenant'),
+    ('employees:read', 'View employees'),
+    ('employees:write', 'Create, update and delete employees')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO roles (tenant_id, name, description) VALUES
+    (NULL, 'admin', 'Tenant administrator with every permission'),
+    (NULL, 'user', 'Regular tenant member');
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, p.name FROM roles r CROSS JOIN permissions p
+WHERE r.tenant_id IS NULL AND r.name = 'admin';
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, 'employees:read' FROM roles r
+WHERE r.tenant_id IS NULL AND r.name = 'user';
+
+-- Grant existing users the built-in role matching their 'users.role' column
+INSERT INTO user_roles (user_id, tenant_id, role_id)
+SELECT u.id, u.tenant_id, r.id FROM users u
+JOIN roles r ON r.tenant_id IS NULL AND r.name = u.role
+ON CONFLICT DO NOTHING;
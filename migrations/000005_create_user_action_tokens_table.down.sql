@@ -0,0 +1,3 @@
+-- migrations/000005_create_user_action_tokens_table.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP TABLE IF EXISTS user_action_tokens;
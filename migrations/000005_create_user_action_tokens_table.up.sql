@@ -0,0 +1,16 @@
+-- migrations/000005_create_user_action_tokens_table.up.sql
+-- This migration creates the 'user_action_tokens' table for single-use tokens sent to users
+-- (e.g. password reset links). Only a SHA-256 hash of each token is stored.
+
+CREATE TABLE IF NOT EXISTS user_action_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose VARCHAR(50) NOT NULL,                                   -- What the token allows, e.g. 'password_reset'
+    token_hash VARCHAR(64) UNIQUE NOT NULL,                         -- Hex-encoded SHA-256 of the token
+    expires_at TIMESTAMPTZ NOT NULL,
+    consumed_at TIMESTAMPTZ,                                        -- Set when the token is used or invalidated
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE INDEX idx_user_action_tokens_user_purpose ON user_action_tokens (user_id, purpose);
@@ -0,0 +1,3 @@
+-- migrations/000006_add_email_verified_at_to_users.down.sql
+-- This migration reverts the changes made by the up migration.
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
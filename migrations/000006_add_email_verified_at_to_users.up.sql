@@ -0,0 +1,8 @@
+-- migrations/000006_add_email_verified_at_to_users.up.sql
+-- This migration adds the email verification state of users.
+-- Accounts created before verification existed are treated as verified, so enabling the
+-- verification policy does not lock them out.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ; -- NULL until the user confirms their email
+
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
@@ -0,0 +1,4 @@
+-- migrations/000007_create_mfa_tables.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
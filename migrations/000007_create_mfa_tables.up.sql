@@ -0,0 +1,24 @@
+-- migrations/000007_create_mfa_tables.up.sql
+-- This migration creates the tables for TOTP-based multi-factor authentication (MFA).
+-- A 'user_mfa' row is created on enrollment and only takes effect once 'enabled_at' is set,
+-- after the user confirmed a first code. Recovery codes are stored as SHA-256 hashes.
+
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    totp_secret VARCHAR(64) NOT NULL,                               -- Base32 TOTP secret; protect with encryption at rest
+    enabled_at TIMESTAMPTZ,                                         -- NULL while enrollment is pending confirmation
+    last_used_step BIGINT NOT NULL DEFAULT 0,                       -- Last accepted TOTP time step, prevents code replay
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,                                 -- Hex-encoded SHA-256 of the normalized code
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE UNIQUE INDEX idx_mfa_recovery_codes_user_code ON mfa_recovery_codes (user_id, code_hash);
@@ -0,0 +1,3 @@
+-- migrations/000008_create_login_throttles_table.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP TABLE IF EXISTS login_throttles;
@@ -0,0 +1,15 @@
+-- migrations/000008_create_login_throttles_table.up.sql
+-- This migration creates the 'login_throttles' table, which counts failed login attempts
+-- per account (normalized email) and per client IP for progressive delays and temporary lockouts.
+
+CREATE TABLE IF NOT EXISTS login_throttles (
+    scope VARCHAR(16) NOT NULL,                                     -- 'account' or 'ip'
+    key VARCHAR(255) NOT NULL,                                      -- Normalized email or client IP, depending on scope
+    failures INT NOT NULL DEFAULT 0,                                -- Consecutive failures within the failure window
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ,                                       -- Set while the account/IP is locked out
+    PRIMARY KEY (scope, key)
+);
+
+-- Indexes for performance
+CREATE INDEX idx_login_throttles_last_failure_at ON login_throttles (last_failure_at);
@@ -0,0 +1,3 @@
+-- migrations/000009_create_user_identities_table.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,18 @@
+-- migrations/000009_create_user_identities_table.up.sql
+-- This migration creates the 'user_identities' table, which links users to accounts at external
+-- OpenID Connect providers. A user may have several identities, but each external account
+-- (provider + subject) belongs to exactly one user.
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider VARCHAR(100) NOT NULL,                                 -- Provider name from the OIDC provider configuration
+    subject VARCHAR(255) NOT NULL,                                  -- 'sub' claim of the ID token, stable per provider
+    email VARCHAR(255),                                             -- Email reported by the provider at the last login
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMPTZ
+);
+
+-- Indexes for performance
+CREATE UNIQUE INDEX idx_user_identities_provider_subject ON user_identities (provider, subject);
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
@@ -0,0 +1,4 @@
+-- migrations/000010_create_api_keys_table.down.sql
+-- This migration reverts the changes made by the up migration.
+DELETE FROM permissions WHERE name = 'api_keys:manage';
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,33 @@
+-- migrations/000010_create_api_keys_table.up.sql
+-- This migration creates the 'api_keys' table for machine-to-machine access. Only a SHA-256 hash
+-- of each key is stored; the prefix is kept in plain text so keys can be recognized in lists and logs.
+-- Each key carries the permissions (scopes) it grants within its tenant.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,                                     -- Label chosen by the creator, e.g. 'nightly-sync'
+    prefix VARCHAR(32) NOT NULL,                                    -- Public start of the key, e.g. 'sk_1a2b3c4d'
+    key_hash VARCHAR(64) NOT NULL,                                  -- Hex-encoded SHA-256 of the full key
+    scopes TEXT[] NOT NULL DEFAULT '{}',                            -- Permissions granted to the key
+    created_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ,                                         -- NULL for keys that don't expire
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys (key_hash);
+CREATE UNIQUE INDEX idx_api_keys_prefix ON api_keys (prefix);
+CREATE INDEX idx_api_keys_tenant_id ON api_keys (tenant_id);
+
+-- Permission to manage the tenant's API keys, granted to the built-in admin role
+INSERT INTO permissions (name, description) VALUES
+    ('api_keys:manage', 'Create, view and revoke API keys of the tenant')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, 'api_keys:manage' FROM roles r
+WHERE r.tenant_id IS NULL AND r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+-- migrations/000011_create_tenants_table.down.sql
+-- This migration reverts the changes made by the up migration.
+DELETE FROM roles WHERE tenant_id IS NULL AND name = 'platform_admin';
+DELETE FROM permissions WHERE name = 'tenants:manage';
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS fk_api_keys_tenant;
+ALTER TABLE roles DROP CONSTRAINT IF EXISTS fk_roles_tenant;
+ALTER TABLE user_roles DROP CONSTRAINT IF EXISTS fk_user_roles_tenant;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_tenant;
+DROP TABLE IF EXISTS tenants;
@@ -0,0 +1,51 @@
+-- migrations/000011_create_tenants_table.up.sql
+-- This migration makes tenants a first-class entity. Every tenant ID already used by users, roles
+-- or API keys gets a tenant row, so the existing data stays valid under the new foreign keys.
+-- A 'default' tenant is seeded so a fresh installation can register its first users.
+
+CREATE TABLE IF NOT EXISTS tenants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(63) NOT NULL,                                      -- URL-friendly identifier, e.g. 'acme'
+    status VARCHAR(20) NOT NULL DEFAULT 'active',                   -- 'active' or 'suspended'
+    plan VARCHAR(50) NOT NULL DEFAULT 'free',
+    settings JSONB NOT NULL DEFAULT '{}',                           -- Tenant-specific configuration
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE UNIQUE INDEX idx_tenants_slug ON tenants (slug);
+
+INSERT INTO tenants (id, name, slug) VALUES
+    ('a1b2c3d4-e5f6-4a7b-8c9d-0f1e2d3c4b5a', 'Default', 'default')
+ON CONFLICT DO NOTHING;
+
+-- Backfill the tenants referenced by existing data
+INSERT INTO tenants (id, name, slug)
+SELECT t.tenant_id, 'Tenant ' || t.tenant_id, 'tenant-' || REPLACE(t.tenant_id::text, '-', '')
+FROM (
+    SELECT tenant_id FROM users
+    UNION SELECT tenant_id FROM user_roles
+    UNION SELECT tenant_id FROM roles WHERE tenant_id IS NOT NULL
+    UNION SELECT tenant_id FROM api_keys
+) t
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ADD CONSTRAINT fk_users_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+ALTER TABLE user_roles ADD CONSTRAINT fk_user_roles_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+ALTER TABLE roles ADD CONSTRAINT fk_roles_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+ALTER TABLE api_keys ADD CONSTRAINT fk_api_keys_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+-- Managing tenants spans every tenant, so the permission is not granted to the tenant 'admin' role
+-- but to the built-in 'platform_admin' role, which can only be granted in the database.
+INSERT INTO permissions (name, description) VALUES
+    ('tenants:manage', 'Create, update and suspend tenants')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO roles (tenant_id, name, description) VALUES
+    (NULL, 'platform_admin', 'Platform operator managing all tenants');
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, 'tenants:manage' FROM roles r
+WHERE r.tenant_id IS NULL AND r.name = 'platform_admin';
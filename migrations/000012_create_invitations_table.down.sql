@@ -0,0 +1,4 @@
+-- migrations/000012_create_invitations_table.down.sql
+-- This migration reverts the changes made by the up migration.
+UPDATE tenants SET settings = settings - 'allow_self_registration';
+DROP TABLE IF EXISTS invitations;
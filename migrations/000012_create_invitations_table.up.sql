@@ -0,0 +1,24 @@
+-- migrations/000012_create_invitations_table.up.sql
+-- This migration creates the 'invitations' table for onboarding users into a tenant. An admin
+-- invites an email address with a role; the invitee accepts with the emailed single-use token.
+-- Only a SHA-256 hash of each token is stored.
+-- Open self-registration becomes the per-tenant setting 'allow_self_registration'. Existing
+-- tenants keep allowing it; tenants created from now on must opt in.
+
+CREATE TABLE IF NOT EXISTS invitations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,                                      -- Role granted on acceptance
+    token_hash VARCHAR(64) UNIQUE NOT NULL,                         -- Hex-encoded SHA-256 of the token
+    invited_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,                                         -- Set when revoked or replaced by a new invitation
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE INDEX idx_invitations_tenant_email ON invitations (tenant_id, email);
+
+UPDATE tenants SET settings = settings || '{"allow_self_registration": true}'::jsonb;
@@ -0,0 +1,19 @@
+-- migrations/000013_enable_row_level_security.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP POLICY IF EXISTS tenant_isolation ON roles;
+DROP POLICY IF EXISTS tenant_isolation ON invitations;
+DROP POLICY IF EXISTS tenant_isolation ON api_keys;
+DROP POLICY IF EXISTS tenant_isolation ON user_roles;
+DROP POLICY IF EXISTS tenant_isolation ON users;
+
+ALTER TABLE invitations DISABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys DISABLE ROW LEVEL SECURITY;
+ALTER TABLE roles DISABLE ROW LEVEL SECURITY;
+ALTER TABLE user_roles DISABLE ROW LEVEL SECURITY;
+ALTER TABLE users DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS app_current_tenant();
+
+-- Removes the role's privileges and default privileges in this database before dropping it.
+DROP OWNED BY tenant_scoped;
+DROP ROLE IF EXISTS tenant_scoped;
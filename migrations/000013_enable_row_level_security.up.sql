@@ -0,0 +1,56 @@
+-- migrations/000013_enable_row_level_security.up.sql
+-- This migration enables PostgreSQL row-level security (RLS) on the tables that hold tenant data,
+-- as a second line of defense behind the tenant_id conditions of the queries.
+-- For requests of an authenticated caller, the repositories run each query in a transaction that
+-- switches to the 'tenant_scoped' role and sets 'app.current_tenant' to the caller's tenant. The
+-- policies then hide and reject rows of other tenants. Queries made without a tenant (login,
+-- token refresh, accepting an invitation) don't switch roles and are not restricted.
+-- Superusers and table owners bypass RLS, which is why the restriction is applied through a
+-- separate role rather than on the application's login role.
+
+-- Role the tenant-scoped transactions switch to. Roles are shared by all databases of a cluster,
+-- so it may already exist.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'tenant_scoped') THEN
+        CREATE ROLE tenant_scoped NOLOGIN;
+    END IF;
+END
+$$;
+
+-- The application's login role must be a member to switch to it. This assumes the application
+-- connects as the role running the migrations; grant it to the application's role otherwise.
+-- The server checks the membership on startup.
+GRANT tenant_scoped TO CURRENT_USER;
+
+GRANT USAGE ON SCHEMA public TO tenant_scoped;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO tenant_scoped;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO tenant_scoped;
+-- Tables created by later migrations are covered as well.
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO tenant_scoped;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO tenant_scoped;
+
+-- Tenant of the current transaction, or NULL when it isn't scoped to a tenant.
+CREATE OR REPLACE FUNCTION app_current_tenant() RETURNS UUID
+    LANGUAGE sql STABLE
+    AS $$ SELECT NULLIF(current_setting('app.current_tenant', true), '')::uuid $$;
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_roles ENABLE ROW LEVEL SECURITY;
+ALTER TABLE roles ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invitations ENABLE ROW LEVEL SECURITY;
+
+-- Login roles other than the table owner see every row while no tenant is set.
+CREATE POLICY tenant_isolation ON users
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
+CREATE POLICY tenant_isolation ON user_roles
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
+CREATE POLICY tenant_isolation ON api_keys
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
+CREATE POLICY tenant_isolation ON invitations
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
+-- Built-in roles (tenant_id IS NULL) are visible to every tenant but can only be changed unscoped.
+CREATE POLICY tenant_isolation ON roles
+    USING (app_current_tenant() IS NULL OR tenant_id IS NULL OR tenant_id = app_current_tenant())
+    WITH CHECK (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
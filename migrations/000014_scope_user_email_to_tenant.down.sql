@@ -0,0 +1,7 @@
+-- migrations/000014_scope_user_email_to_tenant.down.sql
+-- This migration reverts the changes made by the up migration.
+-- It fails while an email has accounts in several tenants; remove the duplicates first.
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_users_tenant_email;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+CREATE UNIQUE INDEX idx_users_email ON users (email);
@@ -0,0 +1,10 @@
+-- migrations/000014_scope_user_email_to_tenant.up.sql
+-- This migration makes user emails unique per tenant instead of globally, so the same person can
+-- have an account in several tenants. Each account has its own password, role and settings.
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+DROP INDEX IF EXISTS idx_users_email;
+
+-- Indexes for performance
+CREATE UNIQUE INDEX idx_users_tenant_email ON users (tenant_id, email); -- Unique email per tenant
+CREATE INDEX idx_users_email ON users (email);                         -- Login looks up accounts by email across tenants
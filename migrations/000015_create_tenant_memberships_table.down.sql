@@ -0,0 +1,9 @@
+-- migrations/000015_create_tenant_memberships_table.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP POLICY IF EXISTS tenant_isolation ON users;
+CREATE POLICY tenant_isolation ON users
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
+
+DROP TABLE IF EXISTS tenant_memberships;
+DROP FUNCTION IF EXISTS app_current_user();
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS tenant_id;
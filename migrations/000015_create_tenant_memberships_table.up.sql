@@ -0,0 +1,41 @@
+-- migrations/000015_create_tenant_memberships_table.up.sql
+-- This migration creates the 'tenant_memberships' table, which links users to the tenants they
+-- can act in. A user's own tenant (users.tenant_id) is their home tenant; memberships in other
+-- tenants let the same account switch between tenants. Every user is a member of their home tenant.
+-- Refresh tokens record the tenant of their session, so refreshing stays in the chosen tenant.
+
+CREATE TABLE IF NOT EXISTS tenant_memberships (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    role VARCHAR(50) NOT NULL,                                      -- Role placed in access tokens for this tenant
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, tenant_id)
+);
+
+-- Indexes for performance
+CREATE INDEX idx_tenant_memberships_tenant_id ON tenant_memberships (tenant_id);
+
+INSERT INTO tenant_memberships (user_id, tenant_id, role, created_at)
+SELECT id, tenant_id, role, created_at FROM users
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE refresh_tokens ADD COLUMN tenant_id UUID REFERENCES tenants (id); -- NULL for sessions started before tenant switching; they use the home tenant
+
+-- User of the current transaction, or NULL when it isn't scoped to a user.
+CREATE OR REPLACE FUNCTION app_current_user() RETURNS UUID
+    LANGUAGE sql STABLE
+    AS $$ SELECT NULLIF(current_setting('app.current_user', true), '')::uuid $$;
+
+-- Users see their own memberships in every tenant, e.g. to list the tenants they can switch to.
+ALTER TABLE tenant_memberships ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON tenant_memberships
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant() OR user_id = app_current_user());
+
+-- Members from other tenants are visible in the tenants they belong to.
+DROP POLICY IF EXISTS tenant_isolation ON users;
+CREATE POLICY tenant_isolation ON users
+    USING (
+        app_current_tenant() IS NULL
+        OR tenant_id = app_current_tenant()
+        OR EXISTS (SELECT 1 FROM tenant_memberships m WHERE m.user_id = users.id AND m.tenant_id = app_current_tenant())
+    );
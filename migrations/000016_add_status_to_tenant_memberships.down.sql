@@ -0,0 +1,4 @@
+-- migrations/000016_add_status_to_tenant_memberships.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP INDEX IF EXISTS idx_tenant_memberships_tenant_status;
+ALTER TABLE tenant_memberships DROP COLUMN IF EXISTS status;
@@ -0,0 +1,8 @@
+-- migrations/000016_add_status_to_tenant_memberships.up.sql
+-- This migration adds the status of tenant memberships. Tenant admins deactivate a member to take
+-- away their access to the tenant without deleting the account or its role assignments.
+
+ALTER TABLE tenant_memberships ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'; -- 'active' or 'deactivated'
+
+-- Indexes for performance
+CREATE INDEX idx_tenant_memberships_tenant_status ON tenant_memberships (tenant_id, status); -- Member lists filtered by status
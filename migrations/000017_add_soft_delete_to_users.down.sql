@@ -0,0 +1,11 @@
+-- migrations/000017_add_soft_delete_to_users.down.sql
+-- This migration reverts the changes made by the up migration.
+-- Soft-deleted users are removed for good, as the full unique index would not allow them.
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_tenant_deleted;
+DROP INDEX IF EXISTS idx_users_tenant_email;
+CREATE UNIQUE INDEX idx_users_tenant_email ON users (tenant_id, email);
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS disabled_at;
@@ -0,0 +1,14 @@
+-- migrations/000017_add_soft_delete_to_users.up.sql
+-- This migration adds soft deletion and account deactivation to users. Deleted users stay in the
+-- table, so rows that reference them keep their history, but are hidden from the application and
+-- can be restored by an admin of their tenant. Disabled users can't sign in to any tenant.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ; -- NULL while the account may sign in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;  -- NULL unless the user was deleted
+
+-- A deleted user's email can be used for a new account in the same tenant.
+DROP INDEX IF EXISTS idx_users_tenant_email;
+
+-- Indexes for performance
+CREATE UNIQUE INDEX idx_users_tenant_email ON users (tenant_id, email) WHERE deleted_at IS NULL; -- Unique email per tenant among live users
+CREATE INDEX idx_users_tenant_deleted ON users (tenant_id) WHERE deleted_at IS NOT NULL;         -- Listing a tenant's deleted users
@@ -0,0 +1,5 @@
+-- migrations/000018_create_privacy_tables.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS privacy_requests;
+ALTER TABLE users DROP COLUMN IF EXISTS erased_at;
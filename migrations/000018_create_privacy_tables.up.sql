@@ -0,0 +1,46 @@
+-- migrations/000018_create_privacy_tables.up.sql
+-- This migration adds data subject requests (GDPR): exports of everything stored about a user and
+-- erasure of a user's personal data. Requests are queued in 'privacy_requests' and processed in
+-- the background. Erased users keep their row, anonymized, so references to them stay valid;
+-- each erasure is recorded in 'audit_logs'.
+
+CREATE TABLE IF NOT EXISTS privacy_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants (id),                -- Tenant the request was made in
+    user_id UUID NOT NULL REFERENCES users (id),                    -- Data subject
+    type VARCHAR(20) NOT NULL,                                      -- 'export' or 'erasure'
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',                  -- 'pending', 'processing', 'completed', 'failed' or 'expired'
+    requested_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    file_path TEXT,                                                 -- Export bundle, while it can be downloaded
+    error TEXT,                                                     -- Why processing failed
+    expires_at TIMESTAMPTZ,                                         -- When the export bundle is deleted
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    actor_id UUID,                                                  -- User who caused the event; kept when the user is gone
+    action VARCHAR(100) NOT NULL,                                   -- What happened, e.g. 'user.erased'
+    target_type VARCHAR(50) NOT NULL,                               -- Kind of the affected entity, e.g. 'user'
+    target_id UUID NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',                            -- Event-specific data; never personal data
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMPTZ; -- Set when the user's personal data was erased
+
+-- Indexes for performance
+CREATE INDEX idx_privacy_requests_pending ON privacy_requests (created_at) WHERE status = 'pending'; -- Worker queue
+CREATE INDEX idx_privacy_requests_user_id ON privacy_requests (user_id);
+CREATE INDEX idx_audit_logs_tenant_created ON audit_logs (tenant_id, created_at);
+CREATE INDEX idx_audit_logs_target ON audit_logs (target_type, target_id);
+
+ALTER TABLE privacy_requests ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_logs ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON privacy_requests
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
+CREATE POLICY tenant_isolation ON audit_logs
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
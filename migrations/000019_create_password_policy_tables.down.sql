@@ -0,0 +1,5 @@
+-- migrations/000019_create_password_policy_tables.down.sql
+-- This migration reverts the changes made by the up migration.
+DELETE FROM permissions WHERE name = 'password_policy:manage';
+DROP TABLE IF EXISTS password_history;
+DROP TABLE IF EXISTS password_policies;
@@ -0,0 +1,45 @@
+-- migrations/000019_create_password_policy_tables.up.sql
+-- This migration adds per-tenant password policies and the password history used to prevent
+-- reuse. Tenants without a 'password_policies' row use the default policy (at least 8 characters).
+-- History entries hold bcrypt hashes of the user's previous passwords, the current one included.
+
+CREATE TABLE IF NOT EXISTS password_policies (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants (id),
+    min_length INT NOT NULL DEFAULT 8,                              -- Minimum password length in characters
+    require_uppercase BOOLEAN NOT NULL DEFAULT FALSE,
+    require_lowercase BOOLEAN NOT NULL DEFAULT FALSE,
+    require_digit BOOLEAN NOT NULL DEFAULT FALSE,
+    require_symbol BOOLEAN NOT NULL DEFAULT FALSE,                  -- Any character that is not a letter or digit
+    history_count INT NOT NULL DEFAULT 0,                           -- Number of previous passwords that can't be reused; 0 allows reuse
+    check_breached BOOLEAN NOT NULL DEFAULT FALSE,                  -- Reject passwords found in the breached-password list
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS password_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,                            -- bcrypt hash of a password the user has set
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE INDEX idx_password_history_user_created ON password_history (user_id, created_at DESC);
+
+-- Existing passwords are the first history entry of their users.
+INSERT INTO password_history (user_id, password_hash, created_at)
+SELECT id, password_hash, updated_at FROM users WHERE password_hash <> '' AND erased_at IS NULL;
+
+ALTER TABLE password_policies ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON password_policies
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
+
+-- Permission to change the tenant's password policy, granted to the built-in admin role
+INSERT INTO permissions (name, description) VALUES
+    ('password_policy:manage', 'Change the password policy of the tenant')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, 'password_policy:manage' FROM roles r
+WHERE r.tenant_id IS NULL AND r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+-- migrations/000020_create_employees_table.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP TABLE IF EXISTS employees;
@@ -0,0 +1,22 @@
+-- migrations/000020_create_employees_table.up.sql
+-- This migration creates the 'employees' table of the employee module. Employees are records a
+-- tenant keeps about its staff; they are not accounts and can't sign in.
+
+CREATE TABLE IF NOT EXISTS employees (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,                                    -- Unique per tenant
+    phone_number VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE UNIQUE INDEX idx_employees_tenant_email ON employees (tenant_id, email);
+CREATE INDEX idx_employees_tenant_name ON employees (tenant_id, name);
+
+ALTER TABLE employees ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON employees
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
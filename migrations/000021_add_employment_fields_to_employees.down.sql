@@ -0,0 +1,11 @@
+-- migrations/000021_add_employment_fields_to_employees.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP INDEX IF EXISTS idx_employees_manager;
+DROP INDEX IF EXISTS idx_employees_tenant_department;
+DROP INDEX IF EXISTS idx_employees_tenant_status;
+
+ALTER TABLE employees DROP COLUMN IF EXISTS manager_id;
+ALTER TABLE employees DROP COLUMN IF EXISTS hire_date;
+ALTER TABLE employees DROP COLUMN IF EXISTS department;
+ALTER TABLE employees DROP COLUMN IF EXISTS job_title;
+ALTER TABLE employees DROP COLUMN IF EXISTS status;
@@ -0,0 +1,14 @@
+-- migrations/000021_add_employment_fields_to_employees.up.sql
+-- This migration adds the employment details of employees: their status, job title, department,
+-- hire date and manager. Existing employees become active employees without those details.
+
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'; -- active, on_leave or terminated
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS job_title VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS department VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS hire_date DATE;                                -- NULL when unknown
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS manager_id UUID REFERENCES employees (id) ON DELETE SET NULL; -- Another employee of the same tenant
+
+-- Indexes for performance
+CREATE INDEX idx_employees_tenant_status ON employees (tenant_id, status);
+CREATE INDEX idx_employees_tenant_department ON employees (tenant_id, department);
+CREATE INDEX idx_employees_manager ON employees (manager_id);
@@ -0,0 +1,14 @@
+-- migrations/000022_create_departments_table.down.sql
+-- This migration reverts the changes made by the up migration.
+-- Employees get the name of their department back; the hierarchy is lost.
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS department VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE employees e SET department = d.name
+FROM departments d
+WHERE d.id = e.department_id;
+
+DROP INDEX IF EXISTS idx_employees_department;
+ALTER TABLE employees DROP COLUMN IF EXISTS department_id;
+CREATE INDEX idx_employees_tenant_department ON employees (tenant_id, department);
+
+DROP TABLE IF EXISTS departments;
@@ -0,0 +1,36 @@
+-- migrations/000022_create_departments_table.up.sql
+-- This migration creates the 'departments' table, which organizes a tenant's employees into a
+-- hierarchy of departments. The free-text department of employees is replaced with a reference to
+-- a department; a department is created for every distinct name in use.
+
+CREATE TABLE IF NOT EXISTS departments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    name VARCHAR(255) NOT NULL,                                     -- Unique per tenant
+    parent_id UUID REFERENCES departments (id),                     -- NULL for a top-level department
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE UNIQUE INDEX idx_departments_tenant_name ON departments (tenant_id, name);
+CREATE INDEX idx_departments_parent ON departments (parent_id);
+
+ALTER TABLE departments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON departments
+    USING (app_current_tenant() IS NULL OR tenant_id = app_current_tenant());
+
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS department_id UUID REFERENCES departments (id) ON DELETE SET NULL; -- NULL when not in a department
+
+INSERT INTO departments (tenant_id, name)
+SELECT DISTINCT tenant_id, department FROM employees WHERE department <> '';
+
+UPDATE employees e SET department_id = d.id
+FROM departments d
+WHERE d.tenant_id = e.tenant_id AND d.name = e.department;
+
+DROP INDEX IF EXISTS idx_employees_tenant_department;
+ALTER TABLE employees DROP COLUMN IF EXISTS department;
+
+CREATE INDEX idx_employees_department ON employees (department_id);
@@ -0,0 +1,5 @@
+-- migrations/000023_cascade_privacy_requests_on_user_delete.down.sql
+-- This migration reverts the changes made by the up migration.
+ALTER TABLE privacy_requests DROP CONSTRAINT IF EXISTS privacy_requests_user_id_fkey;
+ALTER TABLE privacy_requests ADD CONSTRAINT privacy_requests_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (id);
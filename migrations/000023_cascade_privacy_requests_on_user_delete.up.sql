@@ -0,0 +1,7 @@
+-- migrations/000023_cascade_privacy_requests_on_user_delete.up.sql
+-- This migration deletes a user's privacy requests with the user. Erasure keeps the user row, but
+-- a user row that is deleted for good (e.g. with its tenant) must not be blocked by its requests.
+
+ALTER TABLE privacy_requests DROP CONSTRAINT IF EXISTS privacy_requests_user_id_fkey;
+ALTER TABLE privacy_requests ADD CONSTRAINT privacy_requests_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+-- migrations/000024_add_claimed_at_to_privacy_requests.down.sql
+-- This migration reverts the changes made by the up migration.
+DROP INDEX IF EXISTS idx_privacy_requests_processing;
+ALTER TABLE privacy_requests DROP COLUMN IF EXISTS claimed_at;
@@ -0,0 +1,8 @@
+-- migrations/000024_add_claimed_at_to_privacy_requests.up.sql
+-- This migration records when a worker claimed a privacy request, so requests left in
+-- 'processing' by a worker that stopped mid-way are claimed again after a timeout.
+
+ALTER TABLE privacy_requests ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ; -- When the request was last claimed by a worker
+
+-- Indexes for performance
+CREATE INDEX idx_privacy_requests_processing ON privacy_requests (claimed_at) WHERE status = 'processing'; -- Stale claims
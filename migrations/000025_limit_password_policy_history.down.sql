@@ -0,0 +1,3 @@
+-- migrations/000025_limit_password_policy_history.down.sql
+-- This migration reverts the changes made by the up migration.
+-- The clamped policy values and the dropped history entries can't be restored.
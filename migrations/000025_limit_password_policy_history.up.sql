@@ -0,0 +1,15 @@
+-- migrations/000025_limit_password_policy_history.up.sql
+-- This migration brings stored password policies within the lowered limits: a minimum length of
+-- at most 72 (bcrypt's input limit) and at most 10 previous passwords, each of which costs a
+-- bcrypt comparison whenever a password is set. Older history entries are dropped.
+
+UPDATE password_policies SET min_length = 72 WHERE min_length > 72;
+UPDATE password_policies SET history_count = 10 WHERE history_count > 10;
+
+DELETE FROM password_history WHERE id IN (
+    SELECT id FROM (
+        SELECT id, row_number() OVER (PARTITION BY user_id ORDER BY created_at DESC) AS position
+        FROM password_history
+    ) ranked
+    WHERE position > 10
+);
@@ -0,0 +1,5 @@
+-- migrations/000026_merge_case_duplicate_departments.down.sql
+-- This migration reverts the changes made by the up migration.
+-- Names are unique per tenant in their exact case again; merged departments can't be split.
+DROP INDEX IF EXISTS idx_departments_tenant_name;
+CREATE UNIQUE INDEX idx_departments_tenant_name ON departments (tenant_id, name);
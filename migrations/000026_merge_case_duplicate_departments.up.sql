@@ -0,0 +1,40 @@
+-- migrations/000026_merge_case_duplicate_departments.up.sql
+-- This migration merges departments whose names only differ in case, e.g. "Sales" and "sales"
+-- created from the free-text departments of employees, and makes names unique per tenant
+-- regardless of case. The oldest department of each group is kept; the employees and
+-- subdepartments of the others move to it.
+
+CREATE TEMPORARY TABLE department_merges AS
+SELECT id, keep_id FROM (
+    SELECT id, first_value(id) OVER (PARTITION BY tenant_id, lower(name) ORDER BY created_at, id) AS keep_id
+    FROM departments
+) grouped
+WHERE id <> keep_id;
+
+UPDATE employees e SET department_id = m.keep_id
+FROM department_merges m
+WHERE e.department_id = m.id;
+
+UPDATE departments d SET parent_id = m.keep_id
+FROM department_merges m
+WHERE d.parent_id = m.id;
+
+DELETE FROM departments WHERE id IN (SELECT id FROM department_merges);
+
+DROP TABLE department_merges;
+
+-- A merged department can end up as its own ancestor; the departments of such a cycle move to the
+-- top level.
+WITH RECURSIVE ancestors (id, ancestor_id, depth) AS (
+    SELECT id, parent_id, 1 FROM departments WHERE parent_id IS NOT NULL
+    UNION ALL
+    SELECT a.id, d.parent_id, a.depth + 1
+    FROM ancestors a
+    JOIN departments d ON d.id = a.ancestor_id
+    WHERE d.parent_id IS NOT NULL AND a.ancestor_id <> a.id AND a.depth < (SELECT count(*) FROM departments)
+)
+UPDATE departments SET parent_id = NULL
+WHERE id IN (SELECT id FROM ancestors WHERE ancestor_id = id);
+
+DROP INDEX IF EXISTS idx_departments_tenant_name;
+CREATE UNIQUE INDEX idx_departments_tenant_name ON departments (tenant_id, lower(name));
@@ -0,0 +1,7 @@
+-- migrations/000027_encrypt_mfa_secrets.down.sql
+-- This migration reverts the changes made by the up migration.
+-- Encrypted secrets don't fit the former column; those enrollments are removed and have to be set
+-- up again.
+DELETE FROM mfa_recovery_codes WHERE user_id IN (SELECT user_id FROM user_mfa WHERE totp_secret LIKE 'v1:%');
+DELETE FROM user_mfa WHERE totp_secret LIKE 'v1:%';
+ALTER TABLE user_mfa ALTER COLUMN totp_secret TYPE VARCHAR(64);
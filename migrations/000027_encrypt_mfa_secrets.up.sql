@@ -0,0 +1,6 @@
+-- migrations/000027_encrypt_mfa_secrets.up.sql
+-- This migration makes room for encrypted TOTP secrets. The application encrypts them with
+-- MFA_SECRET_KEY (AES-256-GCM) and encrypts the secrets stored in plain text when it starts.
+-- Recovery codes are now 80-bit codes; codes issued before keep working until replaced.
+
+ALTER TABLE user_mfa ALTER COLUMN totp_secret TYPE TEXT;